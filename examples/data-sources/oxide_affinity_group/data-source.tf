@@ -0,0 +1,7 @@
+data "oxide_affinity_group" "example" {
+  project_name = "my-project"
+  name         = "my-group"
+  timeouts = {
+    read = "1m"
+  }
+}
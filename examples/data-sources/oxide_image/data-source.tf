@@ -5,3 +5,9 @@ data "oxide_image" "example" {
     read = "1m"
   }
 }
+
+data "oxide_image" "ubuntu" {
+  os          = "ubuntu"
+  version     = "24.04"
+  most_recent = true
+}
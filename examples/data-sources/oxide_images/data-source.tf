@@ -1 +1,7 @@
 data "oxide_images" "example" {}
+
+data "oxide_images" "ubuntu" {
+  name_regex = "^ubuntu-"
+  os         = "ubuntu"
+  version    = "24.04"
+}
@@ -0,0 +1,11 @@
+data "oxide_instance" "example" {
+  project_name = "my-project"
+  name         = "my-instance"
+  timeouts = {
+    read = "1m"
+  }
+}
+
+data "oxide_instance" "by_id" {
+  id = "c1dee930-a8e4-11ed-afa1-0242ac120002"
+}
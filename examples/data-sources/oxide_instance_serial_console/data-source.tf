@@ -0,0 +1,12 @@
+data "oxide_instance_serial_console" "example" {
+  instance_id = "c1dee930-a8e4-11ed-afa1-0242ac120002"
+  most_recent = 4096
+  filter      = "^Cloud-init .* finished"
+
+  lifecycle {
+    postcondition {
+      condition     = self.output != ""
+      error_message = "cloud-init has not finished yet."
+    }
+  }
+}
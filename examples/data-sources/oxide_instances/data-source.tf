@@ -0,0 +1,13 @@
+data "oxide_instances" "example" {
+  project_name        = "my-project"
+  name_regex          = "^web-"
+  run_state           = "running"
+  include_primary_ips = true
+  timeouts = {
+    read = "1m"
+  }
+}
+
+output "web_ips" {
+  value = data.oxide_instances.example.instances[*].primary_ipv4
+}
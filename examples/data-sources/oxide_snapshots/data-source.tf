@@ -0,0 +1,15 @@
+data "oxide_snapshots" "backups" {
+  project_name = "my-project"
+  disk_id      = "c1dee930-a8e4-11ed-afa1-0242ac120002"
+  name_regex   = "^backup-"
+  max_age      = "168h"
+}
+
+# Restore a disk from the latest matching snapshot.
+resource "oxide_disk" "restored" {
+  project_id         = data.oxide_snapshots.backups.snapshots[0].project_id
+  description        = "Disk restored from the latest backup"
+  name               = "restored"
+  size               = data.oxide_snapshots.backups.snapshots[0].size
+  source_snapshot_id = data.oxide_snapshots.backups.snapshots[0].id
+}
@@ -0,0 +1,21 @@
+resource "oxide_instance" "example" {
+  project_id  = "c1dee930-a8e4-11ed-afa1-0242ac120002"
+  description = "Example instance."
+  name        = "myinstance"
+  hostname    = "myhostname"
+  memory      = 10737418240
+  ncpus       = 1
+
+  user_data = provider::oxide::cloud_init([
+    {
+      content_type = "text/cloud-config"
+      content = yamlencode({
+        packages = ["nginx"]
+      })
+    },
+    {
+      content_type = "text/x-shellscript"
+      content      = file("${path.module}/setup.sh")
+    },
+  ])
+}
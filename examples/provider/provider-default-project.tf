@@ -0,0 +1,10 @@
+provider "oxide" {
+  default_project = "my-project"
+}
+
+# The VPC is created in "my-project".
+resource "oxide_vpc" "example" {
+  name        = "example"
+  description = "Example VPC."
+  dns_name    = "example"
+}
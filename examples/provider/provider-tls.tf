@@ -0,0 +1,15 @@
+provider "oxide" {
+  host = "https://oxide.sys.example.com"
+
+  # Trust the CA that signed the certificate of the Oxide API, in addition to
+  # the system's trusted CAs.
+  ca_bundle = "/etc/ssl/certs/internal-ca.pem"
+
+  # Send requests through a proxy.
+  proxy_url = "http://proxy.example.com:3128"
+
+  # Add headers to every request.
+  headers = {
+    "X-Request-Source" = "terraform"
+  }
+}
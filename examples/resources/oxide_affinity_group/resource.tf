@@ -0,0 +1,12 @@
+resource "oxide_affinity_group" "example" {
+  project_id  = "c1dee930-a8e4-11ed-afa1-0242ac120002"
+  description = "a test affinity group"
+  name        = "my-affinity-group"
+  policy      = "allow"
+  timeouts = {
+    read   = "1m"
+    create = "3m"
+    delete = "2m"
+    update = "2m"
+  }
+}
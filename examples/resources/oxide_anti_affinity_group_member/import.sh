@@ -0,0 +1,2 @@
+# Import ID is the format `${GROUP_ID}/${INSTANCE_ID}`.
+terraform import oxide_anti_affinity_group_member.example 9b9f9be1-96bf-44ad-864a-0dedae3b3999/c1dee930-a8e4-11ed-afa1-0242ac120002
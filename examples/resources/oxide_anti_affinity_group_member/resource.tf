@@ -0,0 +1,9 @@
+resource "oxide_anti_affinity_group_member" "example" {
+  anti_affinity_group_id = "9b9f9be1-96bf-44ad-864a-0dedae3b3999"
+  instance_id            = "c1dee930-a8e4-11ed-afa1-0242ac120002"
+  timeouts = {
+    read   = "1m"
+    create = "3m"
+    delete = "2m"
+  }
+}
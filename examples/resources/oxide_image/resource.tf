@@ -10,3 +10,28 @@ resource "oxide_image" "example2" {
     create = "3m"
   }
 }
+
+resource "oxide_image" "example3" {
+  project_id  = "c1dee930-a8e4-11ed-afa1-0242ac120002"
+  description = "an image uploaded from a local file"
+  name        = "myimage3"
+  source_file = "${path.module}/alpine.raw"
+  os          = "alpine"
+  version     = "3.22"
+  timeouts = {
+    create = "30m"
+  }
+}
+
+resource "oxide_image" "example4" {
+  project_id        = "c1dee930-a8e4-11ed-afa1-0242ac120002"
+  description       = "an image downloaded from an HTTP server"
+  name              = "myimage4"
+  source_url        = "https://images.example.com/alpine-3.22.raw"
+  source_url_sha256 = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+  os                = "alpine"
+  version           = "3.22"
+  timeouts = {
+    create = "30m"
+  }
+}
@@ -0,0 +1,18 @@
+resource "oxide_instance" "example" {
+  project_id  = "c1dee930-a8e4-11ed-afa1-0242ac120002"
+  description = "Example instance."
+  name        = "myinstance"
+  hostname    = "myhostname"
+  memory      = 10737418240
+  ncpus       = 1
+
+  boot_disk = {
+    name            = "myinstance-boot"
+    source_image_id = "1f6b4a5e-7c3d-4e8a-9b2f-0d1c2e3f4a5b"
+    size            = 21474836480
+  }
+
+  wait_for = {
+    serial_console_regex = "login:"
+  }
+}
@@ -0,0 +1,2 @@
+# Import ID is the format `${INSTANCE_ID}/${DISK_ID}`.
+terraform import oxide_instance_disk_attachment.example c1dee930-a8e4-11ed-afa1-0242ac120002/611bb17d-6883-45be-b3aa-8a186fdeafe8
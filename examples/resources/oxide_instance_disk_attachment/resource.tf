@@ -0,0 +1,9 @@
+resource "oxide_instance_disk_attachment" "example" {
+  instance_id = "c1dee930-a8e4-11ed-afa1-0242ac120002"
+  disk_id     = "611bb17d-6883-45be-b3aa-8a186fdeafe8"
+  timeouts = {
+    read   = "1m"
+    create = "3m"
+    delete = "2m"
+  }
+}
@@ -0,0 +1,33 @@
+resource "oxide_instance_group" "example" {
+  project_id  = "c1dee930-a8e4-11ed-afa1-0242ac120002"
+  description = "web servers"
+  name        = "web"
+  size        = 3
+  template = {
+    ncpus  = 2
+    memory = 4294967296
+    boot_disk = {
+      source_image_id = "68a2e4a5-0a1c-4f6c-9e1e-4a3c7d1c7a53"
+      size            = 21474836480
+    }
+    network_interfaces = [
+      {
+        name        = "net0"
+        description = "a network interface"
+        vpc_id      = "9b9f9be1-96bf-44ad-864a-0dedae3b3999"
+        subnet_id   = "066cab1b-c550-4aea-8a21-e2f6e5a4d2e7"
+      },
+    ]
+    ssh_public_keys = ["066cab1b-c550-4aea-8a21-e2f6e5a4d2e7"]
+    user_data       = filebase64("path/to/init.sh")
+  }
+  rolling_update = {
+    max_unavailable = 1
+  }
+  timeouts = {
+    read   = "1m"
+    create = "15m"
+    delete = "15m"
+    update = "30m"
+  }
+}
@@ -0,0 +1,4 @@
+# Import ID is the ID or the path `${PROJECT}/${INSTANCE}/${NETWORK_INTERFACE}`.
+# The `${PROJECT}/` prefix may be omitted when the provider sets `default_project`.
+terraform import oxide_instance_network_interface.example 3e2c6e84-bed8-4c94-afc3-1032082d6a90
+terraform import oxide_instance_network_interface.example my-project/my-instance/net1
@@ -0,0 +1,19 @@
+resource "oxide_instance_network_interface" "example" {
+  instance_id = "c1dee930-a8e4-11ed-afa1-0242ac120002"
+  subnet_id   = "066cab1b-c550-4aea-8a80-8422fd3bfc40"
+  vpc_id      = "9b9f9be1-4fa4-4e54-8c5e-ed2b1e3c8f27"
+  name        = "net1"
+  description = "a secondary network interface"
+  ip_config = {
+    v4 = {
+      ip = "auto"
+    }
+  }
+  transit_ips = ["192.168.10.0/24"]
+  timeouts = {
+    read   = "1m"
+    create = "3m"
+    delete = "2m"
+    update = "2m"
+  }
+}
@@ -0,0 +1,11 @@
+resource "oxide_instance_snapshot_set" "example" {
+  instance_id   = "c1dee930-a8e4-11ed-afa1-0242ac120002"
+  name          = "nightly"
+  description   = "nightly snapshots of the database disks"
+  stop_instance = true
+  timeouts = {
+    read   = "1m"
+    create = "10m"
+    delete = "5m"
+  }
+}
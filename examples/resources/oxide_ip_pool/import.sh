@@ -1 +1,3 @@
+# Import ID is the ID or the path `${IP_POOL}`.
 terraform import oxide_ip_pool.example 3e2c6e84-bed8-4c94-afc3-1032082d6a90
+terraform import oxide_ip_pool.example my-pool
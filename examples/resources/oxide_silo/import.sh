@@ -1 +1,3 @@
+# Import ID is the ID or the path `${SILO}`.
 terraform import oxide_silo.example 3e2c6e84-bed8-4c94-afc3-1032082d6a90
+terraform import oxide_silo.example my-silo
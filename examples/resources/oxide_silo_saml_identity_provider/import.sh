@@ -1 +1,3 @@
+# Import ID is the ID or the path `${SILO}/${IDENTITY_PROVIDER}`.
 terraform import oxide_silo_saml_identity_provider.example 508d32b8-3685-43b6-846f-f339f3100ed9
+terraform import oxide_silo_saml_identity_provider.example my-silo/my-idp
@@ -1 +1,4 @@
+# Import ID is the ID or the path `${PROJECT}/${VPC}/${ROUTER}/${ROUTE}`.
+# The `${PROJECT}/` prefix may be omitted when the provider sets `default_project`.
 terraform import oxide_vpc_router_route.example 3e2c6e84-bed8-4c94-afc3-1032082d6a90
+terraform import oxide_vpc_router_route.example my-project/my-vpc/my-router/my-route
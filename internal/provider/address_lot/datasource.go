@@ -65,7 +65,7 @@ func (d *DataSource) Configure(
 		return
 	}
 
-	d.client = req.ProviderData.(*oxide.Client)
+	d.client = req.ProviderData.(*shared.ProviderData).Client
 }
 
 func (d *DataSource) Schema(
@@ -148,10 +148,10 @@ func (d *DataSource) Read(
 		AddressLot: oxide.NameOrId(state.Name.ValueString()),
 	})
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read address lot:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	lot := addressLot.Lot
@@ -76,7 +76,7 @@ func (r *Resource) Configure(
 		return
 	}
 
-	r.client = req.ProviderData.(*oxide.Client)
+	r.client = req.ProviderData.(*shared.ProviderData).Client
 }
 
 // ImportState imports an existing address lot into Terraform state.
@@ -85,7 +85,26 @@ func (r *Resource) ImportState(
 	req resource.ImportStateRequest,
 	resp *resource.ImportStateResponse,
 ) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	shared.ImportStateByPath(
+		ctx,
+		req,
+		resp,
+		path.Root("id"),
+		"address_lot",
+		"",
+		func(ctx context.Context, names []string) (string, error) {
+			addressLot, err := r.client.NetworkingAddressLotView(
+				ctx,
+				oxide.NetworkingAddressLotViewParams{
+					AddressLot: oxide.NameOrId(names[0]),
+				},
+			)
+			if err != nil {
+				return "", err
+			}
+			return addressLot.Lot.Id, nil
+		},
+	)
 }
 
 // Schema defines the schema for the resource.
@@ -209,10 +228,10 @@ func (r *Resource) Create(
 	}
 	lot, err := r.client.NetworkingAddressLotCreate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error creating address lot",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -270,10 +289,10 @@ func (r *Resource) Read(
 		AddressLot: oxide.NameOrId(state.ID.ValueString()),
 	})
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read address lot:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	lot := addressLot.Lot
@@ -350,10 +369,10 @@ func (r *Resource) Delete(
 			AddressLot: oxide.NameOrId(state.ID.ValueString()),
 		}); err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Error deleting Address Lot:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
@@ -0,0 +1,195 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package affinitygroup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/oxidecomputer/oxide.go/oxide"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/shared"
+)
+
+var (
+	_ datasource.DataSource              = (*DataSource)(nil)
+	_ datasource.DataSourceWithConfigure = (*DataSource)(nil)
+)
+
+// NewDataSource initialises an affinity group datasource
+func NewDataSource() datasource.DataSource {
+	return &DataSource{}
+}
+
+type DataSource struct {
+	client           *oxide.Client
+	defaultProjectID string
+}
+
+type DataSourceModel struct {
+	Description   types.String   `tfsdk:"description"`
+	FailureDomain types.String   `tfsdk:"failure_domain"`
+	ID            types.String   `tfsdk:"id"`
+	Members       types.List     `tfsdk:"members"`
+	Name          types.String   `tfsdk:"name"`
+	Policy        types.String   `tfsdk:"policy"`
+	ProjectID     types.String   `tfsdk:"project_id"`
+	TimeCreated   types.String   `tfsdk:"time_created"`
+	TimeModified  types.String   `tfsdk:"time_modified"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
+	ProjectName   types.String   `tfsdk:"project_name"`
+}
+
+func (d *DataSource) Metadata(
+	ctx context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = "oxide_affinity_group"
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *DataSource) Configure(
+	_ context.Context,
+	req datasource.ConfigureRequest,
+	_ *datasource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData := req.ProviderData.(*shared.ProviderData)
+	d.client = providerData.Client
+	d.defaultProjectID = providerData.DefaultProjectID
+}
+
+func (d *DataSource) Schema(
+	ctx context.Context,
+	req datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `
+Retrieve information about a specified affinity group.
+`,
+		Attributes: map[string]schema.Attribute{
+			"project_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the project that contains the affinity group. Defaults to the provider's `default_project`.",
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the affinity group.",
+			},
+			"project_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "ID of the project that contains the affinity group.",
+			},
+			"description": schema.StringAttribute{
+				Computed:    true,
+				Description: "Description for the affinity group.",
+			},
+			"policy": schema.StringAttribute{
+				Computed:    true,
+				Description: "Affinity policy used to describe what to do when a request cannot be satisfied.",
+			},
+			"failure_domain": schema.StringAttribute{
+				Computed:    true,
+				Description: "Describes the scope of affinity for the purposes of co-location.",
+			},
+			"members": schema.ListAttribute{
+				Computed:    true,
+				Description: "IDs of the instances that are members of the affinity group.",
+				ElementType: types.StringType,
+			},
+			"timeouts": timeouts.Attributes(ctx),
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique, immutable, system-controlled identifier of the affinity group.",
+			},
+			"time_created": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp of when this affinity group was created.",
+			},
+			"time_modified": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp of when this affinity group was last modified.",
+			},
+		},
+	}
+}
+
+func (d *DataSource) Read(
+	ctx context.Context,
+	req datasource.ReadRequest,
+	resp *datasource.ReadResponse,
+) {
+	var state DataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	project, diags := shared.ProjectOrDefault(state.ProjectName, "project_name", d.defaultProjectID)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	params := oxide.AffinityGroupViewParams{
+		AffinityGroup: oxide.NameOrId(state.Name.ValueString()),
+		Project:       project,
+	}
+	affinityGroup, err := d.client.AffinityGroupView(ctx, params)
+	if err != nil {
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+			"Unable to read affinity group:",
+			err,
+		))
+		return
+	}
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("read affinity group with ID: %v", affinityGroup.Id),
+		map[string]any{"success": true},
+	)
+
+	state.Description = types.StringValue(affinityGroup.Description)
+	state.FailureDomain = types.StringValue(string(affinityGroup.FailureDomain))
+	state.ID = types.StringValue(affinityGroup.Id)
+	state.Name = types.StringValue(string(affinityGroup.Name))
+	state.Policy = types.StringValue(string(affinityGroup.Policy))
+	state.ProjectID = types.StringValue(affinityGroup.ProjectId)
+	state.TimeCreated = types.StringValue(affinityGroup.TimeCreated.String())
+	state.TimeModified = types.StringValue(affinityGroup.TimeModified.String())
+
+	members, diags := newMembersList(ctx, d.client, affinityGroup.Id)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.Members = members
+
+	// Save state into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
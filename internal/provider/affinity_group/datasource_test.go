@@ -0,0 +1,85 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package affinitygroup_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/sharedtest"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+type dataSourceConfig struct {
+	BlockName         string
+	Name              string
+	SupportBlockName  string
+	SupportBlockName2 string
+}
+
+var dataSourceConfigTpl = `
+data "oxide_project" "{{.SupportBlockName}}" {
+	name = "tf-acc-test"
+}
+
+resource "oxide_affinity_group" "{{.SupportBlockName2}}" {
+  project_id  = data.oxide_project.{{.SupportBlockName}}.id
+  name        = "{{.Name}}"
+  description = "a group"
+  policy      = "allow"
+}
+
+data "oxide_affinity_group" "{{.BlockName}}" {
+  project_name = "tf-acc-test"
+  name         = oxide_affinity_group.{{.SupportBlockName2}}.name
+  timeouts = {
+    read = "1m"
+  }
+}
+`
+
+func TestAccCloudDataSourceAffinityGroup_full(t *testing.T) {
+	blockName := sharedtest.NewBlockName("datasource-affinity-group")
+	resourceName := sharedtest.NewResourceName()
+	config := sharedtest.ParsedAccConfig(t,
+		dataSourceConfig{
+			BlockName:         blockName,
+			SupportBlockName:  sharedtest.NewBlockName("support"),
+			SupportBlockName2: sharedtest.NewBlockName("support"),
+			Name:              resourceName,
+		},
+		dataSourceConfigTpl,
+	)
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { sharedtest.PreCheck(t) },
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: checkDataSource(
+					fmt.Sprintf("data.oxide_affinity_group.%s", blockName),
+					resourceName,
+				),
+			},
+		},
+	})
+}
+
+func checkDataSource(dataName, keyName string) resource.TestCheckFunc {
+	return resource.ComposeAggregateTestCheckFunc([]resource.TestCheckFunc{
+		resource.TestCheckResourceAttrSet(dataName, "id"),
+		resource.TestCheckResourceAttr(dataName, "name", keyName),
+		resource.TestCheckResourceAttr(dataName, "description", "a group"),
+		resource.TestCheckResourceAttr(dataName, "policy", "allow"),
+		resource.TestCheckResourceAttr(dataName, "failure_domain", "sled"),
+		resource.TestCheckResourceAttr(dataName, "members.#", "0"),
+		resource.TestCheckResourceAttrSet(dataName, "project_id"),
+		resource.TestCheckResourceAttrSet(dataName, "time_created"),
+		resource.TestCheckResourceAttrSet(dataName, "time_modified"),
+		resource.TestCheckResourceAttr(dataName, "timeouts.read", "1m"),
+	}...)
+}
@@ -0,0 +1,476 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package affinitygroup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/oxidecomputer/oxide.go/oxide"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/shared"
+	oxidevalidator "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/validator"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource               = (*Resource)(nil)
+	_ resource.ResourceWithConfigure  = (*Resource)(nil)
+	_ resource.ResourceWithModifyPlan = (*Resource)(nil)
+)
+
+// NewResource is a helper function to simplify the provider implementation.
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+// Resource is the resource implementation.
+type Resource struct {
+	client           *oxide.Client
+	defaultProjectID string
+}
+
+type ResourceModel struct {
+	Description   types.String   `tfsdk:"description"`
+	FailureDomain types.String   `tfsdk:"failure_domain"`
+	ID            types.String   `tfsdk:"id"`
+	Members       types.List     `tfsdk:"members"`
+	Name          types.String   `tfsdk:"name"`
+	Policy        types.String   `tfsdk:"policy"`
+	ProjectID     types.String   `tfsdk:"project_id"`
+	TimeCreated   types.String   `tfsdk:"time_created"`
+	TimeModified  types.String   `tfsdk:"time_modified"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
+}
+
+// Metadata returns the resource type name.
+func (r *Resource) Metadata(
+	_ context.Context,
+	req resource.MetadataRequest,
+	resp *resource.MetadataResponse,
+) {
+	resp.TypeName = "oxide_affinity_group"
+}
+
+// Configure adds the provider configured client to the data source.
+func (r *Resource) Configure(
+	_ context.Context,
+	req resource.ConfigureRequest,
+	_ *resource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData := req.ProviderData.(*shared.ProviderData)
+	r.client = providerData.Client
+	r.defaultProjectID = providerData.DefaultProjectID
+}
+
+// ModifyPlan sets project_id to the provider's default project when it's not
+// set in the configuration.
+func (r *Resource) ModifyPlan(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	shared.ModifyPlanForDefaultProject(ctx, r.defaultProjectID, req, resp)
+}
+
+// ImportState imports an existing affinity group into Terraform state.
+func (r *Resource) ImportState(
+	ctx context.Context,
+	req resource.ImportStateRequest,
+	resp *resource.ImportStateResponse,
+) {
+	shared.ImportStateByPath(
+		ctx,
+		req,
+		resp,
+		path.Root("id"),
+		"project/affinity_group",
+		r.defaultProjectID,
+		func(ctx context.Context, names []string) (string, error) {
+			affinityGroup, err := r.client.AffinityGroupView(
+				ctx,
+				oxide.AffinityGroupViewParams{
+					Project:       oxide.NameOrId(names[0]),
+					AffinityGroup: oxide.NameOrId(names[1]),
+				},
+			)
+			if err != nil {
+				return "", err
+			}
+			return affinityGroup.Id, nil
+		},
+	)
+}
+
+// Schema defines the schema for the resource.
+func (r *Resource) Schema(
+	ctx context.Context,
+	_ resource.SchemaRequest,
+	resp *resource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `
+This resource manages affinity groups.
+`,
+		Attributes: map[string]schema.Attribute{
+			"project_id": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "ID of the project that will contain the affinity group. Defaults to the provider's `default_project`.",
+				Validators: []validator.String{
+					oxidevalidator.IsUUID(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the affinity group.",
+			},
+			"description": schema.StringAttribute{
+				Required:    true,
+				Description: "Description for the affinity group.",
+			},
+			"policy": schema.StringAttribute{
+				Required:    true,
+				Description: "Affinity policy used to describe what to do when a request cannot be satisfied.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						string(oxide.AffinityPolicyAllow),
+						string(oxide.AffinityPolicyFail),
+					),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique, immutable, system-controlled identifier of the affinity group.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"failure_domain": schema.StringAttribute{
+				// For now this will remain as a computed attribute as there is
+				// only a single option: "sled".
+				Computed:    true,
+				Description: "Describes the scope of affinity for the purposes of co-location.",
+			},
+			"members": schema.ListAttribute{
+				Computed:    true,
+				Description: "IDs of the instances that are members of the affinity group.",
+				ElementType: types.StringType,
+			},
+			"time_created": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp of when this affinity group was created.",
+			},
+			"time_modified": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp of when this affinity group was last modified.",
+			},
+		},
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *Resource) Create(
+	ctx context.Context,
+	req resource.CreateRequest,
+	resp *resource.CreateResponse,
+) {
+	var plan ResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	params := oxide.AffinityGroupCreateParams{
+		Project: oxide.NameOrId(plan.ProjectID.ValueString()),
+		Body: &oxide.AffinityGroupCreate{
+			Description: plan.Description.ValueString(),
+			Name:        oxide.Name(plan.Name.ValueString()),
+			// TODO: For now, the only option is "sled", change this into
+			// an attribute when there are more options.
+			FailureDomain: oxide.FailureDomainSled,
+			Policy:        oxide.AffinityPolicy(plan.Policy.ValueString()),
+		},
+	}
+	affinityGroup, err := r.client.AffinityGroupCreate(ctx, params)
+	if err != nil {
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+			"Error creating AffinityGroup",
+			err,
+		))
+		return
+	}
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("created AffinityGroup with ID: %v", affinityGroup.Id),
+		map[string]any{"success": true},
+	)
+
+	// Map response body to schema and populate Computed attribute values
+	plan.ID = types.StringValue(affinityGroup.Id)
+	plan.FailureDomain = types.StringValue(string(affinityGroup.FailureDomain))
+	plan.TimeCreated = types.StringValue(affinityGroup.TimeCreated.String())
+	plan.TimeModified = types.StringValue(affinityGroup.TimeModified.String())
+
+	members, diags := newMembersList(ctx, r.client, affinityGroup.Id)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.Members = members
+
+	// Save plan into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *Resource) Read(
+	ctx context.Context,
+	req resource.ReadRequest,
+	resp *resource.ReadResponse,
+) {
+	var state ResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	params := oxide.AffinityGroupViewParams{
+		AffinityGroup: oxide.NameOrId(state.ID.ValueString()),
+	}
+	affinityGroup, err := r.client.AffinityGroupView(ctx, params)
+	if err != nil {
+		if shared.Is404(err) {
+			// Remove resource from state during a refresh
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+			"Unable to read affinity group:",
+			err,
+		))
+		return
+	}
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("read affinity group with ID: %v", affinityGroup.Id),
+		map[string]any{"success": true},
+	)
+
+	state.Description = types.StringValue(affinityGroup.Description)
+	state.FailureDomain = types.StringValue(string(affinityGroup.FailureDomain))
+	state.ID = types.StringValue(affinityGroup.Id)
+	state.Policy = types.StringValue(string(affinityGroup.Policy))
+	state.Name = types.StringValue(string(affinityGroup.Name))
+	state.ProjectID = types.StringValue(affinityGroup.ProjectId)
+	state.TimeCreated = types.StringValue(affinityGroup.TimeCreated.String())
+	state.TimeModified = types.StringValue(affinityGroup.TimeModified.String())
+
+	members, diags := newMembersList(ctx, r.client, affinityGroup.Id)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.Members = members
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *Resource) Update(
+	ctx context.Context,
+	req resource.UpdateRequest,
+	resp *resource.UpdateResponse,
+) {
+	var plan ResourceModel
+	var state ResourceModel
+
+	// Read Terraform plan data into the plan model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Read Terraform prior state data into the state model to retrieve ID
+	// which is a computed attribute, so it won't show up in the plan.
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	params := oxide.AffinityGroupUpdateParams{
+		AffinityGroup: oxide.NameOrId(state.ID.ValueString()),
+		Body: &oxide.AffinityGroupUpdate{
+			Description: plan.Description.ValueString(),
+			Name:        oxide.Name(plan.Name.ValueString()),
+		},
+	}
+	affinityGroup, err := r.client.AffinityGroupUpdate(ctx, params)
+	if err != nil {
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+			"Error updating affinity group",
+			err,
+		))
+		return
+	}
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("updated affinity group with ID: %v", affinityGroup.Id),
+		map[string]any{"success": true},
+	)
+
+	// Map response body to schema and populate Computed attribute values
+	plan.ID = types.StringValue(affinityGroup.Id)
+	plan.FailureDomain = types.StringValue(string(affinityGroup.FailureDomain))
+	plan.TimeCreated = types.StringValue(affinityGroup.TimeCreated.String())
+	plan.TimeModified = types.StringValue(affinityGroup.TimeModified.String())
+
+	members, diags := newMembersList(ctx, r.client, affinityGroup.Id)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.Members = members
+
+	// Save plan into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *Resource) Delete(
+	ctx context.Context,
+	req resource.DeleteRequest,
+	resp *resource.DeleteResponse,
+) {
+	var state ResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	params := oxide.AffinityGroupDeleteParams{
+		AffinityGroup: oxide.NameOrId(state.ID.ValueString()),
+	}
+	if err := r.client.AffinityGroupDelete(ctx, params); err != nil {
+		if !shared.Is404(err) {
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+				"Error deleting AffinityGroup:",
+				err,
+			))
+			return
+		}
+	}
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("deleted AffinityGroup with ID: %v", state.ID.ValueString()),
+		map[string]any{"success": true},
+	)
+}
+
+// newMembersList returns the IDs of the instances that are members of the
+// affinity group.
+func newMembersList(
+	ctx context.Context,
+	client *oxide.Client,
+	groupID string,
+) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	params := oxide.AffinityGroupMemberListParams{
+		AffinityGroup: oxide.NameOrId(groupID),
+	}
+	members, err := client.AffinityGroupMemberListAllPages(ctx, params)
+	if err != nil {
+		diags.Append(shared.APIErrorDiagnostic(
+			"Unable to read affinity group members:",
+			err,
+		))
+		return types.ListNull(types.StringType), diags
+	}
+
+	ids := []string{}
+	for _, member := range members {
+		if m, ok := member.Value.(*oxide.AffinityGroupMemberInstance); ok {
+			ids = append(ids, m.Value.Id)
+		}
+	}
+
+	return types.ListValueFrom(ctx, types.StringType, ids)
+}
@@ -0,0 +1,173 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package affinitygroup_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/sharedtest"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/oxidecomputer/oxide.go/oxide"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/shared"
+)
+
+type resourceConfig struct {
+	BlockName         string
+	SupportBlockName  string
+	AffinityGroupName string
+}
+
+var resourceConfigTpl = `
+data "oxide_project" "{{.SupportBlockName}}" {
+	name = "tf-acc-test"
+}
+
+resource "oxide_affinity_group" "{{.BlockName}}" {
+	project_id  = data.oxide_project.{{.SupportBlockName}}.id
+	description = "a test affinity group"
+	name        = "{{.AffinityGroupName}}"
+	policy      = "allow"
+	timeouts = {
+		read   = "1m"
+		create = "3m"
+		delete = "2m"
+		update = "4m"
+	}
+  }
+`
+
+var resourceUpdateConfigTpl = `
+data "oxide_project" "{{.SupportBlockName}}" {
+	name = "tf-acc-test"
+}
+
+resource "oxide_affinity_group" "{{.BlockName}}" {
+	project_id  = data.oxide_project.{{.SupportBlockName}}.id
+	description = "a test updated"
+	name        = "{{.AffinityGroupName}}"
+	policy      = "allow"
+  }
+`
+
+func TestAccCloudResourceAffinityGroup_full(t *testing.T) {
+	affinityGroupName := sharedtest.NewResourceName()
+	blockName := sharedtest.NewBlockName("affinity_group")
+	resourceName := fmt.Sprintf("oxide_affinity_group.%s", blockName)
+	supportBlockName := sharedtest.NewBlockName("support")
+	config := sharedtest.ParsedAccConfig(t,
+		resourceConfig{
+			BlockName:         blockName,
+			AffinityGroupName: affinityGroupName,
+			SupportBlockName:  supportBlockName,
+		},
+		resourceConfigTpl,
+	)
+
+	affinityGroupNameUpdated := affinityGroupName + "-updated"
+	configUpdate := sharedtest.ParsedAccConfig(t,
+		resourceConfig{
+			BlockName:         blockName,
+			AffinityGroupName: affinityGroupNameUpdated,
+			SupportBlockName:  supportBlockName,
+		},
+		resourceUpdateConfigTpl,
+	)
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { sharedtest.PreCheck(t) },
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		CheckDestroy:             testAccResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check:  checkResource(resourceName, affinityGroupName),
+			},
+			{
+				Config: configUpdate,
+				Check: checkResourceUpdate(
+					resourceName,
+					affinityGroupNameUpdated,
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func checkResource(
+	resourceName, affinityGroupName string,
+) resource.TestCheckFunc {
+	return resource.ComposeAggregateTestCheckFunc([]resource.TestCheckFunc{
+		resource.TestCheckResourceAttrSet(resourceName, "id"),
+		resource.TestCheckResourceAttr(resourceName, "description", "a test affinity group"),
+		resource.TestCheckResourceAttr(resourceName, "name", affinityGroupName),
+		resource.TestCheckResourceAttr(resourceName, "failure_domain", "sled"),
+		resource.TestCheckResourceAttr(resourceName, "members.#", "0"),
+		resource.TestCheckResourceAttr(resourceName, "policy", "allow"),
+		resource.TestCheckResourceAttrSet(resourceName, "project_id"),
+		resource.TestCheckResourceAttrSet(resourceName, "time_created"),
+		resource.TestCheckResourceAttrSet(resourceName, "time_modified"),
+		resource.TestCheckResourceAttr(resourceName, "timeouts.read", "1m"),
+		resource.TestCheckResourceAttr(resourceName, "timeouts.delete", "2m"),
+		resource.TestCheckResourceAttr(resourceName, "timeouts.create", "3m"),
+		resource.TestCheckResourceAttr(resourceName, "timeouts.update", "4m"),
+	}...)
+}
+
+func checkResourceUpdate(
+	resourceName, affinityGroupName string,
+) resource.TestCheckFunc {
+	return resource.ComposeAggregateTestCheckFunc([]resource.TestCheckFunc{
+		resource.TestCheckResourceAttrSet(resourceName, "id"),
+		resource.TestCheckResourceAttr(resourceName, "description", "a test updated"),
+		resource.TestCheckResourceAttr(resourceName, "name", affinityGroupName),
+		resource.TestCheckResourceAttr(resourceName, "failure_domain", "sled"),
+		resource.TestCheckResourceAttr(resourceName, "members.#", "0"),
+		resource.TestCheckResourceAttr(resourceName, "policy", "allow"),
+		resource.TestCheckResourceAttrSet(resourceName, "project_id"),
+		resource.TestCheckResourceAttrSet(resourceName, "time_created"),
+		resource.TestCheckResourceAttrSet(resourceName, "time_modified"),
+	}...)
+}
+
+func testAccResourceDestroy(s *terraform.State) error {
+	client, err := sharedtest.NewTestClient()
+	if err != nil {
+		return err
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "oxide_affinity_group" {
+			continue
+		}
+
+		params := oxide.AffinityGroupViewParams{
+			AffinityGroup: oxide.NameOrId(rs.Primary.Attributes["id"]),
+		}
+
+		ctx := context.Background()
+		ctx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+
+		res, err := client.AffinityGroupView(ctx, params)
+		if err != nil && shared.Is404(err) {
+			continue
+		}
+
+		return fmt.Errorf("affinity group (%v) still exists", &res.Name)
+	}
+
+	return nil
+}
@@ -29,7 +29,8 @@ func NewDataSource() datasource.DataSource {
 }
 
 type DataSource struct {
-	client *oxide.Client
+	client           *oxide.Client
+	defaultProjectID string
 }
 
 type DataSourceModel struct {
@@ -63,7 +64,9 @@ func (d *DataSource) Configure(
 		return
 	}
 
-	d.client = req.ProviderData.(*oxide.Client)
+	providerData := req.ProviderData.(*shared.ProviderData)
+	d.client = providerData.Client
+	d.defaultProjectID = providerData.DefaultProjectID
 }
 
 func (d *DataSource) Schema(
@@ -77,8 +80,8 @@ Retrieve information about a specified anti-affinity group.
 `,
 		Attributes: map[string]schema.Attribute{
 			"project_name": schema.StringAttribute{
-				Required:    true,
-				Description: "Name of the project that contains the anti-affinity group.",
+				Optional:    true,
+				Description: "Name of the project that contains the anti-affinity group. Defaults to the provider's `default_project`.",
 			},
 			"name": schema.StringAttribute{
 				Required:    true,
@@ -138,16 +141,22 @@ func (d *DataSource) Read(
 	ctx, cancel := context.WithTimeout(ctx, readTimeout)
 	defer cancel()
 
+	project, diags := shared.ProjectOrDefault(state.ProjectName, "project_name", d.defaultProjectID)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	params := oxide.AntiAffinityGroupViewParams{
 		AntiAffinityGroup: oxide.NameOrId(state.Name.ValueString()),
-		Project:           oxide.NameOrId(state.ProjectName.ValueString()),
+		Project:           project,
 	}
 	antiAffinityGroup, err := d.client.AntiAffinityGroupView(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read anti-affinity group:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
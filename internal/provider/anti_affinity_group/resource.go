@@ -10,6 +10,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -26,8 +27,9 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = (*Resource)(nil)
-	_ resource.ResourceWithConfigure = (*Resource)(nil)
+	_ resource.Resource               = (*Resource)(nil)
+	_ resource.ResourceWithConfigure  = (*Resource)(nil)
+	_ resource.ResourceWithModifyPlan = (*Resource)(nil)
 )
 
 // NewResource is a helper function to simplify the provider implementation.
@@ -37,13 +39,15 @@ func NewResource() resource.Resource {
 
 // Resource is the resource implementation.
 type Resource struct {
-	client *oxide.Client
+	client           *oxide.Client
+	defaultProjectID string
 }
 
 type ResourceModel struct {
 	Description   types.String   `tfsdk:"description"`
 	FailureDomain types.String   `tfsdk:"failure_domain"`
 	ID            types.String   `tfsdk:"id"`
+	Members       types.List     `tfsdk:"members"`
 	Name          types.String   `tfsdk:"name"`
 	Policy        types.String   `tfsdk:"policy"`
 	ProjectID     types.String   `tfsdk:"project_id"`
@@ -71,7 +75,19 @@ func (r *Resource) Configure(
 		return
 	}
 
-	r.client = req.ProviderData.(*oxide.Client)
+	providerData := req.ProviderData.(*shared.ProviderData)
+	r.client = providerData.Client
+	r.defaultProjectID = providerData.DefaultProjectID
+}
+
+// ModifyPlan sets project_id to the provider's default project when it's not
+// set in the configuration.
+func (r *Resource) ModifyPlan(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	shared.ModifyPlanForDefaultProject(ctx, r.defaultProjectID, req, resp)
 }
 
 // ImportState imports an existing anti-affinity group into Terraform state.
@@ -80,7 +96,27 @@ func (r *Resource) ImportState(
 	req resource.ImportStateRequest,
 	resp *resource.ImportStateResponse,
 ) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	shared.ImportStateByPath(
+		ctx,
+		req,
+		resp,
+		path.Root("id"),
+		"project/anti_affinity_group",
+		r.defaultProjectID,
+		func(ctx context.Context, names []string) (string, error) {
+			antiAffinityGroup, err := r.client.AntiAffinityGroupView(
+				ctx,
+				oxide.AntiAffinityGroupViewParams{
+					Project:           oxide.NameOrId(names[0]),
+					AntiAffinityGroup: oxide.NameOrId(names[1]),
+				},
+			)
+			if err != nil {
+				return "", err
+			}
+			return antiAffinityGroup.Id, nil
+		},
+	)
 }
 
 // Schema defines the schema for the resource.
@@ -95,13 +131,14 @@ This resource manages anti-affinity groups.
 `,
 		Attributes: map[string]schema.Attribute{
 			"project_id": schema.StringAttribute{
-				Required:    true,
-				Description: "ID of the project that will contain the anti-affinity group.",
+				Optional:    true,
+				Computed:    true,
+				Description: "ID of the project that will contain the anti-affinity group. Defaults to the provider's `default_project`.",
 				Validators: []validator.String{
 					oxidevalidator.IsUUID(),
 				},
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIfConfigured(),
 				},
 			},
 			"name": schema.StringAttribute{
@@ -144,6 +181,11 @@ This resource manages anti-affinity groups.
 				Computed:    true,
 				Description: "Describes the scope of affinity for the purposes of co-location.",
 			},
+			"members": schema.ListAttribute{
+				Computed:    true,
+				Description: "IDs of the instances that are members of the anti-affinity group.",
+				ElementType: types.StringType,
+			},
 			"time_created": schema.StringAttribute{
 				Computed:    true,
 				Description: "Timestamp of when this anti-affinity group was created.",
@@ -191,10 +233,10 @@ func (r *Resource) Create(
 	}
 	antiAffinityGroup, err := r.client.AntiAffinityGroupCreate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error creating AntiAffinityGroup",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -209,6 +251,13 @@ func (r *Resource) Create(
 	plan.TimeCreated = types.StringValue(antiAffinityGroup.TimeCreated.String())
 	plan.TimeModified = types.StringValue(antiAffinityGroup.TimeModified.String())
 
+	members, diags := newMembersList(ctx, r.client, antiAffinityGroup.Id)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.Members = members
+
 	// Save plan into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 	if resp.Diagnostics.HasError() {
@@ -248,10 +297,10 @@ func (r *Resource) Read(
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read anti-affinity group:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -269,6 +318,13 @@ func (r *Resource) Read(
 	state.TimeCreated = types.StringValue(antiAffinityGroup.TimeCreated.String())
 	state.TimeModified = types.StringValue(antiAffinityGroup.TimeModified.String())
 
+	members, diags := newMembersList(ctx, r.client, antiAffinityGroup.Id)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.Members = members
+
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
@@ -315,10 +371,10 @@ func (r *Resource) Update(
 	}
 	antiAffinityGroup, err := r.client.AntiAffinityGroupUpdate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error updating anti-affinity group",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -333,6 +389,13 @@ func (r *Resource) Update(
 	plan.TimeCreated = types.StringValue(antiAffinityGroup.TimeCreated.String())
 	plan.TimeModified = types.StringValue(antiAffinityGroup.TimeModified.String())
 
+	members, diags := newMembersList(ctx, r.client, antiAffinityGroup.Id)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.Members = members
+
 	// Save plan into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 	if resp.Diagnostics.HasError() {
@@ -367,10 +430,10 @@ func (r *Resource) Delete(
 	}
 	if err := r.client.AntiAffinityGroupDelete(ctx, params); err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Error deleting AntiAffinityGroup:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
@@ -380,3 +443,34 @@ func (r *Resource) Delete(
 		map[string]any{"success": true},
 	)
 }
+
+// newMembersList returns the IDs of the instances that are members of the
+// anti-affinity group.
+func newMembersList(
+	ctx context.Context,
+	client *oxide.Client,
+	groupID string,
+) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	params := oxide.AntiAffinityGroupMemberListParams{
+		AntiAffinityGroup: oxide.NameOrId(groupID),
+	}
+	members, err := client.AntiAffinityGroupMemberListAllPages(ctx, params)
+	if err != nil {
+		diags.Append(shared.APIErrorDiagnostic(
+			"Unable to read anti-affinity group members:",
+			err,
+		))
+		return types.ListNull(types.StringType), diags
+	}
+
+	ids := []string{}
+	for _, member := range members {
+		if m, ok := member.Value.(*oxide.AntiAffinityGroupMemberInstance); ok {
+			ids = append(ids, m.Value.Id)
+		}
+	}
+
+	return types.ListValueFrom(ctx, types.StringType, ids)
+}
@@ -114,6 +114,7 @@ func checkResource(
 		resource.TestCheckResourceAttr(resourceName, "description", "a test anti-affinity group"),
 		resource.TestCheckResourceAttr(resourceName, "name", antiAffinityGroupName),
 		resource.TestCheckResourceAttr(resourceName, "failure_domain", "sled"),
+		resource.TestCheckResourceAttr(resourceName, "members.#", "0"),
 		resource.TestCheckResourceAttr(resourceName, "policy", "allow"),
 		resource.TestCheckResourceAttrSet(resourceName, "project_id"),
 		resource.TestCheckResourceAttrSet(resourceName, "time_created"),
@@ -133,6 +134,7 @@ func checkResourceUpdate(
 		resource.TestCheckResourceAttr(resourceName, "description", "a test updated"),
 		resource.TestCheckResourceAttr(resourceName, "name", antiAffinityGroupName),
 		resource.TestCheckResourceAttr(resourceName, "failure_domain", "sled"),
+		resource.TestCheckResourceAttr(resourceName, "members.#", "0"),
 		resource.TestCheckResourceAttr(resourceName, "policy", "allow"),
 		resource.TestCheckResourceAttrSet(resourceName, "project_id"),
 		resource.TestCheckResourceAttrSet(resourceName, "time_created"),
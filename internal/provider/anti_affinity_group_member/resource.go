@@ -0,0 +1,350 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package antiaffinitygroupmember
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/oxidecomputer/oxide.go/oxide"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/instance"
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/shared"
+	oxidevalidator "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/validator"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = (*Resource)(nil)
+	_ resource.ResourceWithConfigure   = (*Resource)(nil)
+	_ resource.ResourceWithImportState = (*Resource)(nil)
+)
+
+// NewResource is a helper function to simplify the provider implementation.
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+// Resource is the resource implementation.
+type Resource struct {
+	client *oxide.Client
+}
+
+type ResourceModel struct {
+	ID                  types.String   `tfsdk:"id"`
+	AntiAffinityGroupID types.String   `tfsdk:"anti_affinity_group_id"`
+	InstanceID          types.String   `tfsdk:"instance_id"`
+	Timeouts            timeouts.Value `tfsdk:"timeouts"`
+}
+
+// Metadata returns the resource type name.
+func (r *Resource) Metadata(
+	_ context.Context,
+	req resource.MetadataRequest,
+	resp *resource.MetadataResponse,
+) {
+	resp.TypeName = "oxide_anti_affinity_group_member"
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *Resource) Configure(
+	_ context.Context,
+	req resource.ConfigureRequest,
+	_ *resource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*shared.ProviderData).Client
+}
+
+// ImportState imports an existing anti-affinity group member into Terraform
+// state.
+func (r *Resource) ImportState(
+	ctx context.Context,
+	req resource.ImportStateRequest,
+	resp *resource.ImportStateResponse,
+) {
+	idParts := strings.Split(req.ID, "/")
+	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("Expected import ID format: group_id/instance_id, got: %s", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(
+		resp.State.SetAttribute(ctx, path.Root("anti_affinity_group_id"), idParts[0])...)
+	resp.Diagnostics.Append(
+		resp.State.SetAttribute(ctx, path.Root("instance_id"), idParts[1])...)
+}
+
+// Schema defines the schema for the resource.
+func (r *Resource) Schema(
+	ctx context.Context,
+	_ resource.SchemaRequest,
+	resp *resource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `
+This resource manages the membership of an instance in an anti-affinity group.
+` + instance.StopRequiredNotes(
+			"adding instances to and removing them from anti-affinity groups",
+			"Anti-affinity groups",
+			"anti_affinity_groups",
+			"the instance is removed from them",
+		),
+		Attributes: map[string]schema.Attribute{
+			// Memberships don't have their own IDs, so the ID is made of the
+			// anti-affinity group and instance IDs.
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier for the membership, in the format `group_id/instance_id`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"anti_affinity_group_id": schema.StringAttribute{
+				Required:    true,
+				Description: "ID of the anti-affinity group to add the instance to.",
+				Validators: []validator.String{
+					oxidevalidator.IsUUID(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"instance_id": schema.StringAttribute{
+				Required:    true,
+				Description: "ID of the instance to add to the anti-affinity group.",
+				Validators: []validator.String{
+					oxidevalidator.IsUUID(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *Resource) Create(
+	ctx context.Context,
+	req resource.CreateRequest,
+	resp *resource.CreateResponse,
+) {
+	var plan ResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	instanceID := plan.InstanceID.ValueString()
+	resp.Diagnostics.Append(instance.WithInstanceStopped(ctx, r.client, createTimeout, instanceID,
+		func() diag.Diagnostics {
+			return instance.AddAntiAffinityGroups(
+				ctx, r.client, []attr.Value{plan.AntiAffinityGroupID}, instanceID,
+			)
+		},
+	)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = types.StringValue(
+		fmt.Sprintf("%s/%s", plan.AntiAffinityGroupID.ValueString(), instanceID),
+	)
+
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("created anti-affinity group member with ID: %v", plan.ID.ValueString()),
+		map[string]any{"success": true},
+	)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *Resource) Read(
+	ctx context.Context,
+	req resource.ReadRequest,
+	resp *resource.ReadResponse,
+) {
+	var state ResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	member, err := isAntiAffinityGroupMember(ctx, r.client,
+		state.AntiAffinityGroupID.ValueString(), state.InstanceID.ValueString())
+	if err != nil {
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+			"Unable to read anti-affinity group member:",
+			err,
+		))
+		return
+	}
+
+	// Remove the membership from state if the group or the instance is gone,
+	// or the instance was removed from the group.
+	if !member {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("read anti-affinity group member with ID: %v", state.ID.ValueString()),
+		map[string]any{"success": true},
+	)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+// Only timeouts can change in-place; both IDs trigger replacement.
+func (r *Resource) Update(
+	ctx context.Context,
+	req resource.UpdateRequest,
+	resp *resource.UpdateResponse,
+) {
+	var plan ResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *Resource) Delete(
+	ctx context.Context,
+	req resource.DeleteRequest,
+	resp *resource.DeleteResponse,
+) {
+	var state ResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	// Only remove the instance if it's still a member of the group, so the
+	// instance isn't stopped for nothing.
+	instanceID := state.InstanceID.ValueString()
+	member, err := isAntiAffinityGroupMember(ctx, r.client,
+		state.AntiAffinityGroupID.ValueString(), instanceID)
+	if err != nil {
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+			"Error reading anti-affinity group member during delete:",
+			err,
+		))
+		return
+	}
+	if !member {
+		return
+	}
+
+	resp.Diagnostics.Append(instance.WithInstanceStopped(ctx, r.client, deleteTimeout, instanceID,
+		func() diag.Diagnostics {
+			return instance.RemoveAntiAffinityGroups(
+				ctx, r.client, []attr.Value{state.AntiAffinityGroupID}, instanceID,
+			)
+		},
+	)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("deleted anti-affinity group member with ID: %v", state.ID.ValueString()),
+		map[string]any{"success": true},
+	)
+}
+
+// isAntiAffinityGroupMember reports whether the instance is a member of the
+// anti-affinity group. An instance or group that doesn't exist isn't a member.
+func isAntiAffinityGroupMember(
+	ctx context.Context,
+	client *oxide.Client,
+	groupID string,
+	instanceID string,
+) (bool, error) {
+	_, err := client.AntiAffinityGroupMemberInstanceView(
+		ctx,
+		oxide.AntiAffinityGroupMemberInstanceViewParams{
+			AntiAffinityGroup: oxide.NameOrId(groupID),
+			Instance:          oxide.NameOrId(instanceID),
+		},
+	)
+	if err != nil {
+		if shared.Is404(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,189 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package antiaffinitygroupmember_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/oxidecomputer/oxide.go/oxide"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/shared"
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/sharedtest"
+)
+
+type antiAffinityGroupMemberResourceConfig struct {
+	BlockName    string
+	InstanceName string
+	GroupName    string
+	Group        string
+}
+
+var antiAffinityGroupMemberResourceConfigTpl = `
+data "oxide_project" "test" {
+  name = "tf-acc-test"
+}
+
+resource "oxide_anti_affinity_group" "one" {
+  project_id  = data.oxide_project.test.id
+  description = "a test anti-affinity group"
+  name        = "{{.GroupName}}-a"
+  policy      = "allow"
+}
+
+resource "oxide_anti_affinity_group" "two" {
+  project_id  = data.oxide_project.test.id
+  description = "another test anti-affinity group"
+  name        = "{{.GroupName}}-b"
+  policy      = "allow"
+}
+
+resource "oxide_instance" "test" {
+  project_id      = data.oxide_project.test.id
+  description     = "a test instance"
+  name            = "{{.InstanceName}}"
+  hostname        = "terraform-acc-myhost"
+  memory          = 1073741824
+  ncpus           = 1
+  start_on_create = false
+
+  ignore_unowned_anti_affinity_groups = true
+}
+
+resource "oxide_anti_affinity_group_member" "{{.BlockName}}" {
+  anti_affinity_group_id = oxide_anti_affinity_group.{{.Group}}.id
+  instance_id            = oxide_instance.test.id
+  timeouts = {
+    create = "5m"
+    delete = "5m"
+  }
+}
+`
+
+func TestAccCloudResourceAntiAffinityGroupMember_full(t *testing.T) {
+	blockName := sharedtest.NewBlockName("anti-affinity-group-member")
+	resourceName := fmt.Sprintf("oxide_anti_affinity_group_member.%s", blockName)
+	cfg := antiAffinityGroupMemberResourceConfig{
+		BlockName:    blockName,
+		InstanceName: sharedtest.NewResourceName(),
+		GroupName:    sharedtest.NewResourceName(),
+		Group:        "one",
+	}
+
+	// Move the instance to another group without touching the instance.
+	cfgSwap := cfg
+	cfgSwap.Group = "two"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { sharedtest.PreCheck(t) },
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		CheckDestroy:             testAccResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: sharedtest.ParsedAccConfig(
+					t, cfg, antiAffinityGroupMemberResourceConfigTpl,
+				),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkAntiAffinityGroupMemberResource(
+						resourceName, "oxide_anti_affinity_group.one",
+					),
+					// The instance doesn't manage the membership.
+					resource.TestCheckNoResourceAttr(
+						"oxide_instance.test", "anti_affinity_groups.#",
+					),
+				),
+			},
+			{
+				Config: sharedtest.ParsedAccConfig(
+					t, cfgSwap, antiAffinityGroupMemberResourceConfigTpl,
+				),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkAntiAffinityGroupMemberResource(
+						resourceName, "oxide_anti_affinity_group.two",
+					),
+					resource.TestCheckNoResourceAttr(
+						"oxide_instance.test", "anti_affinity_groups.#",
+					),
+				),
+			},
+			{
+				// Refresh the groups to read their members.
+				RefreshState: true,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"oxide_anti_affinity_group.one", "members.#", "0",
+					),
+					resource.TestCheckResourceAttr(
+						"oxide_anti_affinity_group.two", "members.#", "1",
+					),
+					resource.TestCheckResourceAttrPair(
+						"oxide_anti_affinity_group.two", "members.0",
+						"oxide_instance.test", "id",
+					),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeouts"},
+			},
+		},
+	})
+}
+
+func checkAntiAffinityGroupMemberResource(resourceName, groupName string) resource.TestCheckFunc {
+	return resource.ComposeAggregateTestCheckFunc([]resource.TestCheckFunc{
+		resource.TestCheckResourceAttrPair(resourceName, "anti_affinity_group_id", groupName, "id"),
+		resource.TestCheckResourceAttrPair(resourceName, "instance_id", "oxide_instance.test", "id"),
+		func(s *terraform.State) error {
+			rs := s.RootModule().Resources[resourceName]
+			want := rs.Primary.Attributes["anti_affinity_group_id"] + "/" +
+				rs.Primary.Attributes["instance_id"]
+			if rs.Primary.ID != want {
+				return fmt.Errorf("expected ID %s, got %s", want, rs.Primary.ID)
+			}
+			return nil
+		},
+	}...)
+}
+
+func testAccResourceDestroy(s *terraform.State) error {
+	client, err := sharedtest.NewTestClient()
+	if err != nil {
+		return err
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "oxide_anti_affinity_group_member" {
+			continue
+		}
+
+		ctx := context.Background()
+		ctx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+
+		params := oxide.AntiAffinityGroupMemberInstanceViewParams{
+			AntiAffinityGroup: oxide.NameOrId(rs.Primary.Attributes["anti_affinity_group_id"]),
+			Instance:          oxide.NameOrId(rs.Primary.Attributes["instance_id"]),
+		}
+		_, err := client.AntiAffinityGroupMemberInstanceView(ctx, params)
+		if err != nil && shared.Is404(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		return fmt.Errorf("instance (%v) is still a member of the anti-affinity group",
+			rs.Primary.Attributes["instance_id"])
+	}
+
+	return nil
+}
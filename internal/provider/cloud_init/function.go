@@ -0,0 +1,173 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package cloudinit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/shared"
+)
+
+var _ function.Function = &Function{}
+
+// MaxUserDataSize is the maximum size of unencoded user data accepted by the
+// Oxide API.
+const MaxUserDataSize = 32 * 1024
+
+type part struct {
+	ContentType string `tfsdk:"content_type"`
+	Content     string `tfsdk:"content"`
+}
+
+func NewFunction() function.Function {
+	return &Function{}
+}
+
+type Function struct{}
+
+func (f *Function) Metadata(
+	ctx context.Context,
+	req function.MetadataRequest,
+	resp *function.MetadataResponse,
+) {
+	resp.Name = "cloud_init"
+}
+
+func (f *Function) Definition(
+	ctx context.Context,
+	req function.DefinitionRequest,
+	resp *function.DefinitionResponse,
+) {
+	resp.Definition = function.Definition{
+		Summary: "Builds a cloud-init multipart document for instance user data.",
+		MarkdownDescription: shared.ReplaceBackticks(`
+Assembles cloud-config documents, shell scripts and other files into a
+cloud-init MIME multipart document and returns it Base64-encoded, ready to be
+used as the ''user_data'' of an ''oxide_instance''. Refer to the [cloud-init
+documentation](https://cloudinit.readthedocs.io/en/latest/explanation/format.html)
+for the supported content types.
+
+The function fails if the document exceeds the maximum user data size of 32 KiB
+accepted by the Oxide API.
+`),
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:        "parts",
+				Description: "Parts of the document, in order. Each part is an object with a `content_type`, such as `text/cloud-config` or `text/x-shellscript`, and its `content`.",
+				ElementType: types.ObjectType{
+					AttrTypes: map[string]attr.Type{
+						"content_type": types.StringType,
+						"content":      types.StringType,
+					},
+				},
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *Function) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var parts []part
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &parts))
+	if resp.Error != nil {
+		return
+	}
+
+	if len(parts) == 0 {
+		resp.Error = function.NewArgumentFuncError(0, "At least one part is required.")
+		return
+	}
+
+	document, err := newDocument(parts)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf(
+			"Unable to build the cloud-init document: %v",
+			err,
+		))
+		return
+	}
+
+	if len(document) > MaxUserDataSize {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf(
+			"The cloud-init document is %d bytes, which exceeds the maximum user data size of %d bytes.",
+			len(document),
+			MaxUserDataSize,
+		))
+		return
+	}
+
+	result := base64.StdEncoding.EncodeToString(document)
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, result))
+}
+
+// newDocument returns the MIME multipart document made of parts.
+func newDocument(parts []part) ([]byte, error) {
+	// Functions must return the same result for the same arguments, so the
+	// boundary is derived from the content instead of being random.
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p.ContentType))
+		h.Write([]byte(p.Content))
+	}
+	boundary := "MIMEBOUNDARY-" + hex.EncodeToString(h.Sum(nil))[:32]
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	if err := w.SetBoundary(boundary); err != nil {
+		return nil, err
+	}
+
+	for i, p := range parts {
+		mediaType, _, err := mime.ParseMediaType(p.ContentType)
+		if err != nil {
+			return nil, fmt.Errorf("invalid content type %q for part %d: %w", p.ContentType, i, err)
+		}
+		if !strings.HasPrefix(mediaType, "text/") {
+			return nil, fmt.Errorf(
+				"invalid content type %q for part %d: only text/* content types are supported",
+				p.ContentType,
+				i,
+			)
+		}
+
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", fmt.Sprintf("%s; charset=\"utf-8\"", mediaType))
+		header.Set("MIME-Version", "1.0")
+		header.Set(
+			"Content-Disposition",
+			fmt.Sprintf("attachment; filename=\"part-%03d\"", i+1),
+		)
+		pw, err := w.CreatePart(header)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := pw.Write([]byte(p.Content)); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	var document bytes.Buffer
+	fmt.Fprintf(&document, "Content-Type: multipart/mixed; boundary=\"%s\"\r\n", boundary)
+	fmt.Fprintf(&document, "MIME-Version: 1.0\r\n\r\n")
+	document.Write(body.Bytes())
+
+	return document.Bytes(), nil
+}
@@ -0,0 +1,92 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package cloudinit_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/sharedtest"
+)
+
+var functionTpl = `
+locals {
+  parts = [
+    {
+      content_type = "{{.ContentType}}"
+      content      = "#cloud-config\nhostname: {{.Hostname}}\n"
+    },
+    {
+      content_type = "text/x-shellscript"
+      content      = "#!/bin/sh\necho ready > /dev/console\n"
+    },
+  ]
+
+  user_data = provider::oxide::cloud_init(local.parts)
+}
+
+output "document" {
+  value = base64decode(local.user_data)
+}
+
+output "deterministic" {
+  value = local.user_data == provider::oxide::cloud_init(local.parts)
+}
+`
+
+type functionTplConfig struct {
+	ContentType string
+	Hostname    string
+}
+
+func TestFunction(t *testing.T) {
+	config := func(contentType, hostname string) string {
+		return sharedtest.ParsedAccConfig(t,
+			functionTplConfig{ContentType: contentType, Hostname: hostname},
+			functionTpl,
+		)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { sharedtest.PreCheck(t) },
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: config("text/cloud-config", "myhost"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue(
+						"document",
+						knownvalue.StringRegexp(regexp.MustCompile(
+							`(?s)^Content-Type: multipart/mixed; boundary="MIMEBOUNDARY-[0-9a-f]{32}"\r\n`+
+								`MIME-Version: 1\.0\r\n\r\n`+
+								`.*Content-Type: text/cloud-config; charset="utf-8"`+
+								`.*hostname: myhost\n`+
+								`.*Content-Type: text/x-shellscript; charset="utf-8"`+
+								`.*echo ready > /dev/console\n`,
+						)),
+					),
+					statecheck.ExpectKnownOutputValue(
+						"deterministic",
+						knownvalue.Bool(true),
+					),
+				},
+			},
+			{
+				// Only text content types are supported.
+				Config:      config("application/octet-stream", "myhost"),
+				ExpectError: regexp.MustCompile(`only text/\* content types are supported`),
+			},
+			{
+				// The document exceeds the user data size limit.
+				Config:      config("text/cloud-config", `${join("", [for i in range(4000) : "abcdefghij"])}`),
+				ExpectError: regexp.MustCompile(`exceeds the maximum user data size`),
+			},
+		},
+	})
+}
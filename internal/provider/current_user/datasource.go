@@ -59,7 +59,7 @@ func (d *DataSource) Configure(
 		return
 	}
 
-	d.client = req.ProviderData.(*oxide.Client)
+	d.client = req.ProviderData.(*shared.ProviderData).Client
 }
 
 // Schema defines the schema for the data source.
@@ -123,10 +123,10 @@ func (d *DataSource) Read(
 
 	user, err := d.client.CurrentUserView(ctx)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read current user:",
-			err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
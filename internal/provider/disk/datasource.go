@@ -30,7 +30,8 @@ func NewDataSource() datasource.DataSource {
 
 // DataSource is the data source implementation.
 type DataSource struct {
-	client *oxide.Client
+	client           *oxide.Client
+	defaultProjectID string
 }
 
 // DataSourceModel are the attributes that are supported on this data source.
@@ -78,7 +79,9 @@ func (d *DataSource) Configure(
 		return
 	}
 
-	d.client = req.ProviderData.(*oxide.Client)
+	providerData := req.ProviderData.(*shared.ProviderData)
+	d.client = providerData.Client
+	d.defaultProjectID = providerData.DefaultProjectID
 }
 
 // Schema defines the schema for the data source.
@@ -101,8 +104,8 @@ Retrieve information about a specified disk.
 				Description: "Name of the disk.",
 			},
 			"project_name": schema.StringAttribute{
-				Required:    true,
-				Description: "Name of the project that contains the disk.",
+				Optional:    true,
+				Description: "Name of the project that contains the disk. Defaults to the provider's `default_project`.",
 			},
 			"description": schema.StringAttribute{
 				Computed:    true,
@@ -189,16 +192,22 @@ func (d *DataSource) Read(
 	ctx, cancel := context.WithTimeout(ctx, readTimeout)
 	defer cancel()
 
+	project, diags := shared.ProjectOrDefault(state.ProjectName, "project_name", d.defaultProjectID)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	params := oxide.DiskViewParams{
 		Disk:    oxide.NameOrId(state.Name.ValueString()),
-		Project: oxide.NameOrId(state.ProjectName.ValueString()),
+		Project: project,
 	}
 	disk, err := d.client.DiskView(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read disk:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(ctx, fmt.Sprintf("read disk with ID: %v", disk.Id), map[string]any{"success": true})
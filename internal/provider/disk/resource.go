@@ -34,6 +34,7 @@ var (
 	_ resource.Resource                     = (*Resource)(nil)
 	_ resource.ResourceWithConfigure        = (*Resource)(nil)
 	_ resource.ResourceWithConfigValidators = (*Resource)(nil)
+	_ resource.ResourceWithModifyPlan       = (*Resource)(nil)
 )
 
 // NewResource is a helper function to simplify the provider implementation.
@@ -43,7 +44,8 @@ func NewResource() resource.Resource {
 
 // Resource is the resource implementation.
 type Resource struct {
-	client *oxide.Client
+	client           *oxide.Client
+	defaultProjectID string
 }
 
 type ResourceModel struct {
@@ -82,7 +84,19 @@ func (r *Resource) Configure(
 		return
 	}
 
-	r.client = req.ProviderData.(*oxide.Client)
+	providerData := req.ProviderData.(*shared.ProviderData)
+	r.client = providerData.Client
+	r.defaultProjectID = providerData.DefaultProjectID
+}
+
+// ModifyPlan sets project_id to the provider's default project when it's not
+// set in the configuration.
+func (r *Resource) ModifyPlan(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	shared.ModifyPlanForDefaultProject(ctx, r.defaultProjectID, req, resp)
 }
 
 // ImportState imports an existing disk resource into Terraform state.
@@ -91,7 +105,24 @@ func (r *Resource) ImportState(
 	req resource.ImportStateRequest,
 	resp *resource.ImportStateResponse,
 ) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	shared.ImportStateByPath(
+		ctx,
+		req,
+		resp,
+		path.Root("id"),
+		"project/disk",
+		r.defaultProjectID,
+		func(ctx context.Context, names []string) (string, error) {
+			disk, err := r.client.DiskView(ctx, oxide.DiskViewParams{
+				Project: oxide.NameOrId(names[0]),
+				Disk:    oxide.NameOrId(names[1]),
+			})
+			if err != nil {
+				return "", err
+			}
+			return disk.Id, nil
+		},
+	)
 }
 
 // ConfigValidators returns the config validators for the resource.
@@ -120,13 +151,14 @@ To create a blank disk it's necessary to set ''block_size''. Otherwise, one of '
 `),
 		Attributes: map[string]schema.Attribute{
 			"project_id": schema.StringAttribute{
-				Required:    true,
-				Description: "ID of the project that will contain the disk.",
+				Optional:    true,
+				Computed:    true,
+				Description: "ID of the project that will contain the disk. Defaults to the provider's `default_project`.",
 				Validators: []validator.String{
 					oxidevalidator.IsUUID(),
 				},
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIfConfigured(),
 				},
 			},
 			"name": schema.StringAttribute{
@@ -315,10 +347,10 @@ func (r *Resource) Create(
 
 	disk, err := r.client.DiskCreate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error creating disk",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -376,10 +408,10 @@ func (r *Resource) Read(
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read disk:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(ctx, fmt.Sprintf("read disk with ID: %v", disk.Id), map[string]any{"success": true})
@@ -449,10 +481,10 @@ func (r *Resource) Delete(
 	}
 	if err := r.client.DiskDelete(ctx, params); err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Unable to delete disk:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
@@ -34,6 +34,7 @@ var (
 	_ resource.ResourceWithConfigure        = (*Resource)(nil)
 	_ resource.ResourceWithImportState      = (*Resource)(nil)
 	_ resource.ResourceWithConfigValidators = (*Resource)(nil)
+	_ resource.ResourceWithModifyPlan       = (*Resource)(nil)
 )
 
 // NewResource is a helper function to simplify the provider implementation.
@@ -43,7 +44,8 @@ func NewResource() resource.Resource {
 
 // Resource is the resource implementation.
 type Resource struct {
-	client *oxide.Client
+	client           *oxide.Client
+	defaultProjectID string
 }
 
 type ResourceModel struct {
@@ -81,7 +83,19 @@ func (r *Resource) Configure(
 		return
 	}
 
-	r.client = req.ProviderData.(*oxide.Client)
+	providerData := req.ProviderData.(*shared.ProviderData)
+	r.client = providerData.Client
+	r.defaultProjectID = providerData.DefaultProjectID
+}
+
+// ModifyPlan sets project_id to the provider's default project when it's not
+// set in the configuration.
+func (r *Resource) ModifyPlan(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	shared.ModifyPlanForDefaultProject(ctx, r.defaultProjectID, req, resp)
 }
 
 // ImportState imports an external subnet using its ID.
@@ -90,7 +104,24 @@ func (r *Resource) ImportState(
 	req resource.ImportStateRequest,
 	resp *resource.ImportStateResponse,
 ) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	shared.ImportStateByPath(
+		ctx,
+		req,
+		resp,
+		path.Root("id"),
+		"project/external_subnet",
+		r.defaultProjectID,
+		func(ctx context.Context, names []string) (string, error) {
+			externalSubnet, err := r.client.ExternalSubnetView(ctx, oxide.ExternalSubnetViewParams{
+				Project:        oxide.NameOrId(names[0]),
+				ExternalSubnet: oxide.NameOrId(names[1]),
+			})
+			if err != nil {
+				return "", err
+			}
+			return externalSubnet.Id, nil
+		},
+	)
 }
 
 // ConfigValidators returns the config validators for the resource.
@@ -128,13 +159,14 @@ func (r *Resource) Schema(
 				Description: "Human-readable free-form text about the external subnet.",
 			},
 			"project_id": schema.StringAttribute{
-				Required:    true,
-				Description: "Project ID where this external subnet is located.",
+				Optional:    true,
+				Computed:    true,
+				Description: "Project ID where this external subnet is located. Defaults to the provider's `default_project`.",
 				Validators: []validator.String{
 					oxidevalidator.IsUUID(),
 				},
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIfConfigured(),
 				},
 			},
 			"subnet": schema.StringAttribute{
@@ -290,10 +322,10 @@ func (r *Resource) Create(
 
 	externalSubnet, err := r.client.ExternalSubnetCreate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error creating external subnet",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -354,10 +386,10 @@ func (r *Resource) Read(
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read external subnet:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -422,10 +454,10 @@ func (r *Resource) Update(
 
 	externalSubnet, err := r.client.ExternalSubnetUpdate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to update external subnet:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -480,10 +512,10 @@ func (r *Resource) Delete(
 
 	if err := r.client.ExternalSubnetDelete(ctx, params); err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Error deleting external subnet:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
@@ -37,7 +37,8 @@ func NewResource() resource.Resource {
 
 // Resource is the resource implementation.
 type Resource struct {
-	client *oxide.Client
+	client           *oxide.Client
+	defaultProjectID string
 }
 
 type ResourceModel struct {
@@ -66,7 +67,9 @@ func (r *Resource) Configure(
 		return
 	}
 
-	r.client = req.ProviderData.(*oxide.Client)
+	providerData := req.ProviderData.(*shared.ProviderData)
+	r.client = providerData.Client
+	r.defaultProjectID = providerData.DefaultProjectID
 }
 
 // ImportState imports an external subnet attachment using the external subnet ID.
@@ -75,7 +78,24 @@ func (r *Resource) ImportState(
 	req resource.ImportStateRequest,
 	resp *resource.ImportStateResponse,
 ) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	shared.ImportStateByPath(
+		ctx,
+		req,
+		resp,
+		path.Root("id"),
+		"project/external_subnet",
+		r.defaultProjectID,
+		func(ctx context.Context, names []string) (string, error) {
+			externalSubnet, err := r.client.ExternalSubnetView(ctx, oxide.ExternalSubnetViewParams{
+				Project:        oxide.NameOrId(names[0]),
+				ExternalSubnet: oxide.NameOrId(names[1]),
+			})
+			if err != nil {
+				return "", err
+			}
+			return externalSubnet.Id, nil
+		},
+	)
 }
 
 // Schema defines the schema for the resource.
@@ -156,10 +176,10 @@ func (r *Resource) Create(
 
 	externalSubnet, err := r.client.ExternalSubnetAttach(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error attaching external subnet",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -214,10 +234,10 @@ func (r *Resource) Read(
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read external subnet attachment:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -297,10 +317,10 @@ func (r *Resource) Delete(
 		if shared.Is404(err) {
 			return
 		}
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error reading external subnet during delete:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -315,10 +335,10 @@ func (r *Resource) Delete(
 		ctx, detachParams,
 	); err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Error detaching external subnet:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
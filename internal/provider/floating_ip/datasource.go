@@ -44,7 +44,8 @@ var (
 // DataSource is the concrete type that implements the necessary
 // Terraform data source interfaces. It holds state to interact with the Oxide API.
 type DataSource struct {
-	client *oxide.Client
+	client           *oxide.Client
+	defaultProjectID string
 }
 
 // NewDataSource is a helper to easily construct a
@@ -74,7 +75,9 @@ func (f *DataSource) Configure(
 		return
 	}
 
-	f.client = req.ProviderData.(*oxide.Client)
+	providerData := req.ProviderData.(*shared.ProviderData)
+	f.client = providerData.Client
+	f.defaultProjectID = providerData.DefaultProjectID
 }
 
 // Schema defines the attributes for this Oxide floating IP data source.
@@ -117,8 +120,8 @@ Retrieve information about a specified floating IP.
 				Description: "Project ID where this floating IP is located.",
 			},
 			"project_name": schema.StringAttribute{
-				Required:    true,
-				Description: "Project name where this floating IP is located.",
+				Optional:    true,
+				Description: "Project name where this floating IP is located. Defaults to the provider's `default_project`.",
 			},
 			"time_created": schema.StringAttribute{
 				Computed:    true,
@@ -157,9 +160,15 @@ func (f *DataSource) Read(
 	ctx, cancel := context.WithTimeout(ctx, readTimeout)
 	defer cancel()
 
+	project, diags := shared.ProjectOrDefault(state.ProjectName, "project_name", f.defaultProjectID)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	params := oxide.FloatingIpViewParams{
 		FloatingIp: oxide.NameOrId(state.Name.ValueString()),
-		Project:    oxide.NameOrId(state.ProjectName.ValueString()),
+		Project:    project,
 	}
 
 	floatingIP, err := f.client.FloatingIpView(ctx, params)
@@ -168,10 +177,10 @@ func (f *DataSource) Read(
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read floating IP:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -48,12 +48,14 @@ var (
 	_ resource.Resource                = (*Resource)(nil)
 	_ resource.ResourceWithConfigure   = (*Resource)(nil)
 	_ resource.ResourceWithImportState = (*Resource)(nil)
+	_ resource.ResourceWithModifyPlan  = (*Resource)(nil)
 )
 
 // Resource is the concrete type that implements the necessary
 // Terraform resource interfaces. It holds state to interact with the Oxide API.
 type Resource struct {
-	client *oxide.Client
+	client           *oxide.Client
+	defaultProjectID string
 }
 
 // NewResource is a helper to easily construct a Resource as
@@ -82,7 +84,19 @@ func (f *Resource) Configure(
 		return
 	}
 
-	f.client = req.ProviderData.(*oxide.Client)
+	providerData := req.ProviderData.(*shared.ProviderData)
+	f.client = providerData.Client
+	f.defaultProjectID = providerData.DefaultProjectID
+}
+
+// ModifyPlan sets project_id to the provider's default project when it's not
+// set in the configuration.
+func (f *Resource) ModifyPlan(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	shared.ModifyPlanForDefaultProject(ctx, f.defaultProjectID, req, resp)
 }
 
 // ImportState imports an Oxide floating IP using its ID.
@@ -91,7 +105,24 @@ func (f *Resource) ImportState(
 	req resource.ImportStateRequest,
 	resp *resource.ImportStateResponse,
 ) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	shared.ImportStateByPath(
+		ctx,
+		req,
+		resp,
+		path.Root("id"),
+		"project/floating_ip",
+		f.defaultProjectID,
+		func(ctx context.Context, names []string) (string, error) {
+			floatingIP, err := f.client.FloatingIpView(ctx, oxide.FloatingIpViewParams{
+				Project:    oxide.NameOrId(names[0]),
+				FloatingIp: oxide.NameOrId(names[1]),
+			})
+			if err != nil {
+				return "", err
+			}
+			return floatingIP.Id, nil
+		},
+	)
 }
 
 // Schema defines the attributes for this Oxide floating IP resource.
@@ -168,13 +199,14 @@ This resource manages Oxide floating IPs.
 				},
 			},
 			"project_id": schema.StringAttribute{
-				Required:    true,
-				Description: "Project ID where this floating IP is located.",
+				Optional:    true,
+				Computed:    true,
+				Description: "Project ID where this floating IP is located. Defaults to the provider's `default_project`.",
 				Validators: []validator.String{
 					oxidevalidator.IsUUID(),
 				},
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIfConfigured(),
 				},
 			},
 			"time_created": schema.StringAttribute{
@@ -254,10 +286,10 @@ func (f *Resource) Create(
 
 	floatingIP, err := f.client.FloatingIpCreate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error creating floating IP:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -315,10 +347,10 @@ func (f *Resource) Read(
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read floating IP:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -384,10 +416,10 @@ func (f *Resource) Update(
 
 	floatingIP, err := f.client.FloatingIpUpdate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to update floating IP:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -441,10 +473,10 @@ func (f *Resource) Delete(
 
 	if err := f.client.FloatingIpDelete(ctx, params); err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Unable to delete floating IP:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
@@ -0,0 +1,168 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package provider
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// downloadResponseHeaderTimeout is how long downloads wait for the response
+// headers, so an unresponsive server doesn't hang until the resource times out.
+const downloadResponseHeaderTimeout = time.Minute
+
+// newTransport returns the HTTP transport configured with the TLS and proxy
+// settings of the provider.
+func newTransport(data oxideProviderModel) (*http.Transport, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if data.InsecureSkipVerify.ValueBool() {
+		transport.TLSClientConfig.InsecureSkipVerify = true
+	}
+
+	if caBundle := data.CABundle.ValueString(); caBundle != "" {
+		rootCAs, err := newCertPool(caBundle)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("ca_bundle"),
+				"Invalid ca_bundle",
+				err.Error(),
+			)
+			return nil, diags
+		}
+		transport.TLSClientConfig.RootCAs = rootCAs
+	}
+
+	if proxyURL := data.ProxyURL.ValueString(); proxyURL != "" {
+		u, err := url.Parse(proxyURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			diags.AddAttributeError(
+				path.Root("proxy_url"),
+				"Invalid proxy_url",
+				fmt.Sprintf(
+					"Expected a URL such as \"http://proxy.example.com:3128\", got: %q",
+					proxyURL,
+				),
+			)
+			return nil, diags
+		}
+		transport.Proxy = http.ProxyURL(u)
+	}
+
+	return transport, diags
+}
+
+// newHTTPClient returns the HTTP client used to make requests to the Oxide
+// API, sending them through transport with the header and retry settings of
+// the provider.
+func newHTTPClient(
+	ctx context.Context,
+	data oxideProviderModel,
+	transport *http.Transport,
+) (*http.Client, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	headers := make(map[string]string, len(data.Headers.Elements()))
+	diags.Append(data.Headers.ElementsAs(ctx, &headers, false)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	maxRetries := defaultMaxRetries
+	if !data.MaxRetries.IsNull() {
+		maxRetries = int(data.MaxRetries.ValueInt64())
+	}
+
+	maxBackoff := defaultRetryMaxBackoff
+	if backoff := data.RetryMaxBackoff.ValueString(); backoff != "" {
+		// The schema validates that the value is a positive duration.
+		maxBackoff, _ = time.ParseDuration(backoff)
+	}
+
+	var next http.RoundTripper = transport
+	if len(headers) > 0 {
+		next = &headerTransport{
+			next:    transport,
+			headers: headers,
+		}
+	}
+
+	return &http.Client{
+		Transport: newRetryTransport(next, maxRetries, maxBackoff),
+	}, diags
+}
+
+// newDownloadClient returns the HTTP client used to download files, such as
+// images from a URL. It shares the TLS and proxy settings of transport, but
+// not the headers and retries meant for the Oxide API.
+func newDownloadClient(transport *http.Transport) *http.Client {
+	transport = transport.Clone()
+	transport.ResponseHeaderTimeout = downloadResponseHeaderTimeout
+
+	return &http.Client{
+		Transport: transport,
+	}
+}
+
+// newCertPool returns the system certificate pool with the certificates in
+// caBundle added to it. caBundle is either PEM encoded certificates or the
+// path to a file that contains them.
+func newCertPool(caBundle string) (*x509.CertPool, error) {
+	pem := []byte(caBundle)
+	if !strings.Contains(caBundle, "-----BEGIN") {
+		var err error
+		pem, err = os.ReadFile(caBundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("no PEM encoded certificates found in CA bundle")
+	}
+
+	return pool, nil
+}
+
+// headerTransport is an [http.RoundTripper] that adds the headers configured
+// in the provider to every request. Headers already set on the request, such
+// as Authorization, are never overridden.
+type headerTransport struct {
+	next    http.RoundTripper
+	headers map[string]string
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the request it was given.
+	req = req.Clone(req.Context())
+	for k, v := range t.headers {
+		if req.Header.Get(k) == "" {
+			req.Header.Set(k, v)
+		}
+	}
+
+	return t.next.RoundTrip(req)
+}
@@ -9,13 +9,17 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/oxidecomputer/oxide.go/oxide"
 
 	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/shared"
+	oxidevalidator "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/validator"
 )
 
 var (
@@ -34,6 +38,8 @@ type DataSource struct {
 
 type DataSourceModel struct {
 	ID           types.String           `tfsdk:"id"`
+	MostRecent   types.Bool             `tfsdk:"most_recent"`
+	NameRegex    types.String           `tfsdk:"name_regex"`
 	ProjectName  types.String           `tfsdk:"project_name"`
 	ProjectID    types.String           `tfsdk:"project_id"`
 	Timeouts     timeouts.Value         `tfsdk:"timeouts"`
@@ -71,7 +77,7 @@ func (d *DataSource) Configure(
 		return
 	}
 
-	d.client = req.ProviderData.(*oxide.Client)
+	d.client = req.ProviderData.(*shared.ProviderData).Client
 }
 
 func (d *DataSource) Schema(
@@ -80,13 +86,35 @@ func (d *DataSource) Schema(
 	resp *datasource.SchemaResponse,
 ) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: `
+		MarkdownDescription: shared.ReplaceBackticks(`
 Retrieve information about a specified image.
-`,
+
+The image is looked up by ''name'', or searched for with ''name_regex'', ''os'' and ''version''. It's an error if more than one image matches, unless ''most_recent'' is set.
+`),
 		Attributes: map[string]schema.Attribute{
 			"name": schema.StringAttribute{
-				Required:    true,
+				Optional:    true,
+				Computed:    true,
 				Description: "Name of the image.",
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("name_regex")),
+					stringvalidator.AtLeastOneOf(
+						path.MatchRoot("name_regex"),
+						path.MatchRoot("os"),
+						path.MatchRoot("version"),
+					),
+				},
+			},
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Regular expression the image name must match.",
+				Validators: []validator.String{
+					oxidevalidator.IsRegexp(),
+				},
+			},
+			"most_recent": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Use the most recently created image when more than one image matches.",
 			},
 			"project_name": schema.StringAttribute{
 				Optional:    true,
@@ -124,8 +152,9 @@ Retrieve information about a specified image.
 				Description: "ID of the project which contains the image.",
 			},
 			"os": schema.StringAttribute{
+				Optional:    true,
 				Computed:    true,
-				Description: "OS image distribution.",
+				Description: "OS image distribution. When set, only images with this OS match.",
 			},
 			"size": schema.Int64Attribute{
 				Computed:    true,
@@ -140,8 +169,9 @@ Retrieve information about a specified image.
 				Description: "Timestamp of when this image was last modified.",
 			},
 			"version": schema.StringAttribute{
+				Optional:    true,
 				Computed:    true,
-				Description: "Version of the OS.",
+				Description: "Version of the OS. When set, only images with this version match.",
 			},
 		},
 	}
@@ -168,18 +198,58 @@ func (d *DataSource) Read(
 	ctx, cancel := context.WithTimeout(ctx, readTimeout)
 	defer cancel()
 
-	params := oxide.ImageViewParams{
-		Image:   oxide.NameOrId(state.Name.ValueString()),
-		Project: oxide.NameOrId(state.ProjectName.ValueString()),
+	var images []oxide.Image
+	if !state.Name.IsNull() {
+		params := oxide.ImageViewParams{
+			Image:   oxide.NameOrId(state.Name.ValueString()),
+			Project: oxide.NameOrId(state.ProjectName.ValueString()),
+		}
+		image, err := d.client.ImageView(ctx, params)
+		if err != nil {
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+				"Unable to read image:",
+				err,
+			))
+			return
+		}
+		images = []oxide.Image{*image}
+	} else {
+		params := oxide.ImageListParams{
+			Project: oxide.NameOrId(state.ProjectName.ValueString()),
+			SortBy:  oxide.NameOrIdSortModeNameAscending,
+		}
+		var err error
+		images, err = d.client.ImageListAllPages(ctx, params)
+		if err != nil {
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+				"Unable to read images:",
+				err,
+			))
+			return
+		}
 	}
-	image, err := d.client.ImageView(ctx, params)
-	if err != nil {
+
+	images = shared.NewImageFilter(state.NameRegex, state.OS, state.Version).Apply(images)
+	switch {
+	case len(images) == 0:
 		resp.Diagnostics.AddError(
-			"Unable to read image:",
-			"API error: "+err.Error(),
+			"No matching image",
+			"Your query returned no results. Please change your search criteria and try again.",
+		)
+		return
+	case len(images) > 1 && !state.MostRecent.ValueBool():
+		resp.Diagnostics.AddError(
+			"Multiple matching images",
+			fmt.Sprintf(
+				"Your query returned %d results. Please try a more specific search criteria, "+
+					"or set the most_recent attribute to true.",
+				len(images),
+			),
 		)
 		return
 	}
+	image := shared.MostRecentImage(images)
+
 	tflog.Trace(
 		ctx,
 		fmt.Sprintf("read image with ID: %v", image.Id),
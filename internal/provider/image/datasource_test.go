@@ -6,6 +6,7 @@ package image_test
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/sharedtest"
@@ -63,6 +64,56 @@ func TestAccCloudDataSourceImage_full(t *testing.T) {
 	})
 }
 
+var dataSourceFilterConfigTpl = `
+data "oxide_image" "{{.BlockName}}" {
+  project_name = "tf-acc-test"
+  name_regex   = "{{.NameRegex}}"
+  most_recent  = true
+  timeouts = {
+    read = "1m"
+  }
+}
+`
+
+// NB: The project must be populated with at least one image for this test to pass
+func TestAccCloudDataSourceImage_filter(t *testing.T) {
+	type dataSourceFilterConfig struct {
+		BlockName string
+		NameRegex string
+	}
+
+	blockName := sharedtest.NewBlockName("datasource-image")
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { sharedtest.PreCheck(t) },
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: sharedtest.ParsedAccConfig(t,
+					dataSourceFilterConfig{
+						BlockName: blockName,
+						NameRegex: ".",
+					},
+					dataSourceFilterConfigTpl,
+				),
+				Check: checkDataSource(
+					fmt.Sprintf("data.oxide_image.%s", blockName),
+				),
+			},
+			{
+				Config: sharedtest.ParsedAccConfig(t,
+					dataSourceFilterConfig{
+						BlockName: blockName,
+						NameRegex: "^does-not-exist$",
+					},
+					dataSourceFilterConfigTpl,
+				),
+				ExpectError: regexp.MustCompile(`Your query returned no results`),
+			},
+		},
+	})
+}
+
 func checkDataSource(dataName string) resource.TestCheckFunc {
 	return resource.ComposeAggregateTestCheckFunc([]resource.TestCheckFunc{
 		resource.TestCheckResourceAttrSet(dataName, "id"),
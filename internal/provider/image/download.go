@@ -0,0 +1,193 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/oxidecomputer/oxide.go/oxide"
+)
+
+const (
+	// downloadMaxRetries is the number of times a failed download is retried
+	// without making progress.
+	downloadMaxRetries = 5
+
+	// downloadMaxBackoff is the longest wait between download retries.
+	downloadMaxBackoff = 30 * time.Second
+)
+
+// importURL downloads the raw disk image at url with httpClient and streams it
+// into a new snapshot, returning the snapshot. The download must match the
+// expected SHA-256 digest.
+func importURL(
+	ctx context.Context,
+	client *oxide.Client,
+	httpClient *http.Client,
+	projectID string,
+	name string,
+	url string,
+	digest string,
+) (*oxide.Snapshot, error) {
+	r := &urlReader{
+		ctx:    ctx,
+		client: httpClient,
+		url:    url,
+	}
+	if err := r.reopen(); err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	if r.size < 0 {
+		return nil, fmt.Errorf("the response for %s has no Content-Length", url)
+	}
+
+	return importImage(ctx, client, projectID, name, importSource{
+		r:      r,
+		size:   r.size,
+		sha256: digest,
+	})
+}
+
+// urlReader reads the body of a URL. When the connection fails, the download
+// is resumed where it stopped with a range request.
+type urlReader struct {
+	ctx    context.Context
+	client *http.Client
+	url    string
+
+	body    io.ReadCloser
+	size    int64
+	offset  int64
+	retries int
+}
+
+// Read implements [io.Reader].
+func (r *urlReader) Read(p []byte) (int, error) {
+	for {
+		n, err := r.body.Read(p)
+		r.offset += int64(n)
+		if n > 0 {
+			r.retries = 0
+			// A failed read is retried when the next read fails again.
+			if err != nil && !errors.Is(err, io.EOF) {
+				err = nil
+			}
+			return n, err
+		}
+		if err == nil || errors.Is(err, io.EOF) {
+			return n, err
+		}
+
+		r.body.Close()
+		r.body = nil
+		if err := r.retry(err); err != nil {
+			return 0, err
+		}
+		if err := r.reopen(); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Close closes the body of the current response.
+func (r *urlReader) Close() error {
+	if r.body == nil {
+		return nil
+	}
+	return r.body.Close()
+}
+
+// reopen requests the rest of the URL from the current offset, retrying
+// transient failures.
+func (r *urlReader) reopen() error {
+	for {
+		retryable, err := r.open()
+		if err == nil {
+			return nil
+		}
+		if !retryable {
+			return err
+		}
+		if err := r.retry(err); err != nil {
+			return err
+		}
+	}
+}
+
+// open sends a single request for the rest of the URL and reports whether a
+// failure is worth retrying.
+func (r *urlReader) open() (bool, error) {
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return false, err
+	}
+
+	want := http.StatusOK
+	if r.offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", r.offset))
+		want = http.StatusPartialContent
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return r.ctx.Err() == nil, err
+	}
+	if resp.StatusCode != want {
+		resp.Body.Close()
+		retryable := resp.StatusCode == http.StatusTooManyRequests ||
+			resp.StatusCode >= http.StatusInternalServerError
+		if r.offset > 0 && resp.StatusCode == http.StatusOK {
+			return false, fmt.Errorf("unable to resume the download of %s: "+
+				"the server does not support range requests", r.url)
+		}
+		return retryable, fmt.Errorf("unexpected response downloading %s: %s", r.url, resp.Status)
+	}
+
+	if r.offset == 0 {
+		r.size = resp.ContentLength
+	}
+	r.body = resp.Body
+	return false, nil
+}
+
+// retry waits before the next attempt after err, or returns err when there
+// are no retries left.
+func (r *urlReader) retry(err error) error {
+	if r.retries >= downloadMaxRetries || r.ctx.Err() != nil {
+		return err
+	}
+
+	wait := min(time.Second<<r.retries, downloadMaxBackoff)
+	r.retries++
+	tflog.Warn(r.ctx, "Retrying image download after transient failure", map[string]any{
+		"url":     r.url,
+		"offset":  r.offset,
+		"reason":  err.Error(),
+		"attempt": r.retries,
+		"wait":    wait.String(),
+	})
+
+	return sleepContext(r.ctx, wait)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,393 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package image
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/oxidecomputer/oxide.go/oxide"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/shared"
+)
+
+const (
+	// importBlockSize is the block size of the disk used to import a file.
+	importBlockSize = 512
+
+	// importChunkSize is the largest amount of data the API accepts in a
+	// single bulk write request.
+	importChunkSize = 512 * 1024
+
+	// importWorkers is the number of bulk write requests sent in parallel.
+	importWorkers = 8
+
+	// importDiskSizeAlignment is the granularity of disk sizes.
+	importDiskSizeAlignment = 1024 * 1024 * 1024
+)
+
+// qcow2Magic is the header that starts every qcow2 file.
+var qcow2Magic = []byte{'Q', 'F', 'I', 0xfb}
+
+// fileSHA256 returns the hex encoded SHA-256 digest of the file at path.
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// validateSourceFile checks that the file at path is a raw disk image that
+// can be imported.
+func validateSourceFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() == 0 || info.Size()%importBlockSize != 0 {
+		return fmt.Errorf(
+			"file size %d is not a multiple of the %d byte block size",
+			info.Size(), importBlockSize,
+		)
+	}
+
+	header := make([]byte, len(qcow2Magic))
+	if _, err := f.ReadAt(header, 0); err != nil {
+		return err
+	}
+	if bytes.Equal(header, qcow2Magic) {
+		return errors.New(
+			"qcow2 images are not supported, convert the file to a raw image first " +
+				"(for example with `qemu-img convert -O raw`)",
+		)
+	}
+
+	return nil
+}
+
+// importSource is the contents of a raw disk image to import.
+type importSource struct {
+	// r reads the contents of the image.
+	r io.Reader
+
+	// size is the size of the image in bytes.
+	size int64
+
+	// sha256 is the expected hex encoded SHA-256 digest of the contents. The
+	// import is aborted before it's finalized when the digest doesn't match.
+	sha256 string
+}
+
+// importFile uploads the raw disk image at path into a new snapshot and
+// returns the snapshot. The file must match the SHA-256 digest computed when
+// the plan was made.
+func importFile(
+	ctx context.Context,
+	client *oxide.Client,
+	projectID string,
+	name string,
+	path string,
+	digest string,
+) (*oxide.Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	return importImage(ctx, client, projectID, name, importSource{
+		r:      f,
+		size:   info.Size(),
+		sha256: digest,
+	})
+}
+
+// importDescription returns the description of the disk used to import an
+// image with the given SHA-256 digest. It tells whether a disk or snapshot
+// left behind by an interrupted import holds the same image.
+func importDescription(digest string) string {
+	return "Temporary disk for an image import. SHA-256: " + digest
+}
+
+// importImage uploads a raw disk image into a new snapshot using the bulk
+// import API and returns the snapshot. The disk the snapshot is taken from is
+// kept, and must be deleted along with the snapshot once the image is created.
+//
+// The disk and snapshot used for the import are named after the image. When a
+// previous import of the same contents was interrupted, the snapshot is reused,
+// or the upload is started again on the existing disk. A disk or snapshot left
+// behind by an import of different contents is deleted first.
+func importImage(
+	ctx context.Context,
+	client *oxide.Client,
+	projectID string,
+	name string,
+	src importSource,
+) (*oxide.Snapshot, error) {
+	importName := oxide.Name(name + "-import")
+	description := importDescription(src.sha256)
+
+	// The snapshot already exists if a previous import was finalized.
+	snapshot, err := client.SnapshotView(ctx, oxide.SnapshotViewParams{
+		Project:  oxide.NameOrId(projectID),
+		Snapshot: oxide.NameOrId(importName),
+	})
+	switch {
+	case err == nil:
+		// Snapshots can't have a description of their own, so the digest is
+		// read from the disk they were taken from.
+		disk, err := client.DiskView(ctx, oxide.DiskViewParams{
+			Disk: oxide.NameOrId(snapshot.DiskId),
+		})
+		if err != nil && !shared.Is404(err) {
+			return nil, err
+		}
+		if err == nil && disk.Description == description {
+			tflog.Debug(ctx, fmt.Sprintf("reusing imported snapshot with ID: %v", snapshot.Id))
+			return snapshot, nil
+		}
+
+		tflog.Debug(ctx, fmt.Sprintf("deleting stale imported snapshot with ID: %v", snapshot.Id))
+		if err := client.SnapshotDelete(ctx, oxide.SnapshotDeleteParams{
+			Snapshot: oxide.NameOrId(snapshot.Id),
+		}); err != nil && !shared.Is404(err) {
+			return nil, fmt.Errorf("deleting stale import snapshot %s: %w", importName, err)
+		}
+	case !shared.Is404(err):
+		return nil, err
+	}
+
+	if src.size <= 0 || src.size%importBlockSize != 0 {
+		return nil, fmt.Errorf(
+			"image size %d is not a multiple of the %d byte block size",
+			src.size, importBlockSize,
+		)
+	}
+
+	disk, reused, err := importDisk(ctx, client, projectID, importName, description, src.size)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ignore the error, the import is only stopped if it was left running by
+	// an interrupted upload.
+	if reused {
+		_ = client.DiskBulkWriteImportStop(ctx, oxide.DiskBulkWriteImportStopParams{
+			Disk: oxide.NameOrId(disk.Id),
+		})
+	}
+
+	if err := client.DiskBulkWriteImportStart(ctx, oxide.DiskBulkWriteImportStartParams{
+		Disk: oxide.NameOrId(disk.Id),
+	}); err != nil {
+		return nil, err
+	}
+
+	// A new disk is zeroed, and a reused disk only holds parts of the same
+	// contents, so there is no need to write zeroed chunks.
+	h := sha256.New()
+	r := io.TeeReader(src.r, h)
+	if err := writeChunks(ctx, client, disk.Id, r, src.size); err != nil {
+		return nil, err
+	}
+
+	if err := client.DiskBulkWriteImportStop(ctx, oxide.DiskBulkWriteImportStopParams{
+		Disk: oxide.NameOrId(disk.Id),
+	}); err != nil {
+		return nil, err
+	}
+
+	// Don't turn unexpected contents into a snapshot.
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != src.sha256 {
+		if err := client.DiskDelete(ctx, oxide.DiskDeleteParams{
+			Disk: oxide.NameOrId(disk.Id),
+		}); err != nil && !shared.Is404(err) {
+			tflog.Warn(ctx, fmt.Sprintf("unable to delete import disk: %v", err))
+		}
+		return nil, fmt.Errorf("SHA-256 checksum mismatch: expected %s, got %s", src.sha256, sum)
+	}
+
+	if err := client.DiskFinalizeImport(ctx, oxide.DiskFinalizeImportParams{
+		Disk: oxide.NameOrId(disk.Id),
+		Body: &oxide.FinalizeDisk{
+			SnapshotName: importName,
+		},
+	}); err != nil {
+		return nil, err
+	}
+
+	return client.SnapshotView(ctx, oxide.SnapshotViewParams{
+		Project:  oxide.NameOrId(projectID),
+		Snapshot: oxide.NameOrId(importName),
+	})
+}
+
+// importDisk returns the disk used to import an image of the given size,
+// creating it in the importing state when it doesn't exist yet. An existing
+// disk is only reused when its description matches, and it's still importing.
+// The returned boolean reports whether an existing disk is reused.
+func importDisk(
+	ctx context.Context,
+	client *oxide.Client,
+	projectID string,
+	name oxide.Name,
+	description string,
+	size int64,
+) (*oxide.Disk, bool, error) {
+	disk, err := client.DiskView(ctx, oxide.DiskViewParams{
+		Project: oxide.NameOrId(projectID),
+		Disk:    oxide.NameOrId(name),
+	})
+	if err == nil {
+		switch disk.State.Value.(type) {
+		case *oxide.DiskStateImportReady, *oxide.DiskStateImportingFromBulkWrites:
+			if disk.Description == description {
+				tflog.Debug(ctx, fmt.Sprintf("reusing import disk with ID: %v", disk.Id))
+				return disk, true, nil
+			}
+		}
+
+		tflog.Debug(ctx, fmt.Sprintf("deleting stale import disk with ID: %v", disk.Id))
+		if err := client.DiskDelete(ctx, oxide.DiskDeleteParams{
+			Disk: oxide.NameOrId(disk.Id),
+		}); err != nil && !shared.Is404(err) {
+			return nil, false, fmt.Errorf("deleting stale import disk %s: %w", name, err)
+		}
+	} else if !shared.Is404(err) {
+		return nil, false, err
+	}
+
+	// Disks must be a multiple of 1 GiB, the remainder is left zeroed.
+	diskSize := (size + importDiskSizeAlignment - 1) /
+		importDiskSizeAlignment * importDiskSizeAlignment
+
+	disk, err = client.DiskCreate(ctx, oxide.DiskCreateParams{
+		Project: oxide.NameOrId(projectID),
+		Body: &oxide.DiskCreate{
+			Name:        name,
+			Description: description,
+			Size:        oxide.ByteCount(diskSize),
+			DiskBackend: oxide.DiskBackend{Value: &oxide.DiskBackendDistributed{
+				DiskSource: oxide.DiskSource{Value: &oxide.DiskSourceImportingBlocks{
+					BlockSize: oxide.BlockSize(importBlockSize),
+				}},
+			}},
+		},
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	tflog.Trace(ctx, fmt.Sprintf("created import disk with ID: %v", disk.Id))
+	return disk, false, nil
+}
+
+// importChunk is a part of an image written with a single bulk write request.
+type importChunk struct {
+	offset int64
+	data   []byte
+}
+
+// writeChunks streams size bytes read from r to the disk. The chunks are read
+// in order and written with bulk write requests sent in parallel. Chunks that
+// only contain zeroes are skipped.
+func writeChunks(
+	ctx context.Context,
+	client *oxide.Client,
+	diskID string,
+	r io.Reader,
+	size int64,
+) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	chunks := make(chan importChunk)
+	var written atomic.Int64
+	var wg sync.WaitGroup
+	for range importWorkers {
+		wg.Go(func() {
+			for chunk := range chunks {
+				if !isZero(chunk.data) {
+					if err := client.DiskBulkWriteImport(ctx, oxide.DiskBulkWriteImportParams{
+						Disk: oxide.NameOrId(diskID),
+						Body: &oxide.ImportBlocksBulkWrite{
+							Base64EncodedData: base64.StdEncoding.EncodeToString(chunk.data),
+							Offset:            oxide.NewPointer(int(chunk.offset)),
+						},
+					}); err != nil {
+						cancel(fmt.Errorf("writing chunk at offset %d: %w", chunk.offset, err))
+						return
+					}
+				}
+
+				// Log the progress every 10%.
+				n := int64(len(chunk.data))
+				total := written.Add(n)
+				if (total-n)*10/size != total*10/size {
+					tflog.Info(ctx, "Uploading image", map[string]any{
+						"disk_id":       diskID,
+						"bytes_written": total,
+						"bytes_total":   size,
+					})
+				}
+			}
+		})
+	}
+
+	for offset := int64(0); offset < size && ctx.Err() == nil; offset += importChunkSize {
+		data := make([]byte, min(importChunkSize, size-offset))
+		if _, err := io.ReadFull(r, data); err != nil {
+			cancel(fmt.Errorf("reading image at offset %d: %w", offset, err))
+			break
+		}
+
+		select {
+		case chunks <- importChunk{offset: offset, data: data}:
+		case <-ctx.Done():
+		}
+	}
+	close(chunks)
+	wg.Wait()
+
+	return context.Cause(ctx)
+}
+
+// isZero reports whether b only contains zeroes.
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,793 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package image_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/sharedtest"
+)
+
+const fakeProjectID = "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+
+type importResourceConfig struct {
+	Host       string
+	BlockName  string
+	ImageName  string
+	SourceFile string
+}
+
+var importResourceConfigTpl = `
+provider "oxide" {
+  host  = "{{.Host}}"
+  token = "fake"
+}
+
+resource "oxide_image" "{{.BlockName}}" {
+  project_id  = "` + fakeProjectID + `"
+  description = "an imported image"
+  name        = "{{.ImageName}}"
+  os          = "alpine"
+  version     = "3.22"
+  source_file = "{{.SourceFile}}"
+}
+`
+
+type urlResourceConfig struct {
+	Host      string
+	CABundle  string
+	BlockName string
+	ImageName string
+	SourceURL string
+	SHA256    string
+}
+
+var urlResourceConfigTpl = `
+provider "oxide" {
+  host  = "{{.Host}}"
+  token = "fake"
+{{- if .CABundle}}
+  ca_bundle = <<EOT
+{{.CABundle}}EOT
+{{- end}}
+}
+
+resource "oxide_image" "{{.BlockName}}" {
+  project_id        = "` + fakeProjectID + `"
+  description       = "an imported image"
+  name              = "{{.ImageName}}"
+  os                = "alpine"
+  version           = "3.22"
+  source_url        = "{{.SourceURL}}"
+  source_url_sha256 = "{{.SHA256}}"
+}
+`
+
+// fakeImportAPI implements the endpoints used to import a file as an image.
+// Disks only hold the data up to the last written byte, the rest is zeroed.
+// Disks and snapshots are identified by their names.
+type fakeImportAPI struct {
+	t *testing.T
+
+	mu        sync.Mutex
+	lastID    int
+	disks     map[string]*fakeDisk
+	snapshots map[string]fakeSnapshot
+	images    map[string]fakeImage
+	creates   int
+	writes    int
+
+	// failSnapshotDelete makes deleting snapshots fail.
+	failSnapshotDelete bool
+}
+
+type fakeDisk struct {
+	description string
+	state       string
+	size        int
+	data        []byte
+}
+
+type fakeSnapshot struct {
+	diskID string
+	data   []byte
+}
+
+type fakeImage struct {
+	name string
+	data []byte
+}
+
+func newFakeImportAPI(t *testing.T) *fakeImportAPI {
+	return &fakeImportAPI{
+		t:         t,
+		disks:     map[string]*fakeDisk{},
+		snapshots: map[string]fakeSnapshot{},
+		images:    map[string]fakeImage{},
+	}
+}
+
+// leaveImport simulates an interrupted import of contents into the disk name,
+// after the first written bytes were uploaded. When finalized is true, all
+// contents were uploaded and the snapshot is left behind as well. The request
+// counters are reset.
+func (f *fakeImportAPI) leaveImport(name string, contents []byte, written int, finalized bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	sum := sha256.Sum256(contents)
+	disk := &fakeDisk{
+		description: "Temporary disk for an image import. SHA-256: " + hex.EncodeToString(sum[:]),
+		state:       "importing_from_bulk_writes",
+		size:        1024 * 1024 * 1024,
+		data:        bytes.Clone(contents[:written]),
+	}
+	if finalized {
+		disk.state = "detached"
+		f.snapshots[name] = fakeSnapshot{diskID: name, data: disk.data}
+	}
+	f.disks[name] = disk
+	f.creates = 0
+	f.writes = 0
+}
+
+// checkRequests checks how many disks were created and how many bulk writes
+// were sent since the counters were last reset.
+func (f *fakeImportAPI) checkRequests(creates, writes int) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		if f.creates != creates || f.writes != writes {
+			return fmt.Errorf(
+				"expected %d disk creations and %d bulk writes, got %d and %d",
+				creates, writes, f.creates, f.writes,
+			)
+		}
+		return nil
+	}
+}
+
+func (f *fakeImportAPI) handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /v1/disks/{disk}", func(w http.ResponseWriter, req *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		name := req.PathValue("disk")
+		if _, ok := f.disks[name]; !ok {
+			f.writeNotFound(w)
+			return
+		}
+		f.writeDisk(w, name)
+	})
+	mux.HandleFunc("POST /v1/disks", func(w http.ResponseWriter, req *http.Request) {
+		var body struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+			Size        int    `json:"size"`
+		}
+		if !f.decode(w, req, &body) {
+			return
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.disks[body.Name] = &fakeDisk{
+			description: body.Description,
+			state:       "import_ready",
+			size:        body.Size,
+		}
+		f.creates++
+		f.writeDisk(w, body.Name)
+	})
+	mux.HandleFunc("DELETE /v1/disks/{disk}", func(w http.ResponseWriter, req *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.disks, req.PathValue("disk"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /v1/disks/{disk}/bulk-write-start", f.noContent)
+	mux.HandleFunc("POST /v1/disks/{disk}/bulk-write-stop", f.noContent)
+	mux.HandleFunc("POST /v1/disks/{disk}/bulk-write", func(w http.ResponseWriter, req *http.Request) {
+		var body struct {
+			Base64EncodedData string `json:"base64_encoded_data"`
+			Offset            int    `json:"offset"`
+		}
+		if !f.decode(w, req, &body) {
+			return
+		}
+		data, err := base64.StdEncoding.DecodeString(body.Base64EncodedData)
+		if err != nil {
+			f.t.Errorf("invalid bulk write data: %v", err)
+			http.Error(w, "invalid data", http.StatusBadRequest)
+			return
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		disk := f.disks[req.PathValue("disk")]
+		if end := body.Offset + len(data); end > len(disk.data) {
+			disk.data = append(disk.data, make([]byte, end-len(disk.data))...)
+		}
+		copy(disk.data[body.Offset:], data)
+		f.writes++
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /v1/disks/{disk}/finalize", func(w http.ResponseWriter, req *http.Request) {
+		var body struct {
+			SnapshotName string `json:"snapshot_name"`
+		}
+		if !f.decode(w, req, &body) {
+			return
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		disk := f.disks[req.PathValue("disk")]
+		disk.state = "detached"
+		f.snapshots[body.SnapshotName] = fakeSnapshot{
+			diskID: req.PathValue("disk"),
+			data:   disk.data,
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("GET /v1/snapshots/{snapshot}", func(w http.ResponseWriter, req *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		name := req.PathValue("snapshot")
+		snapshot, ok := f.snapshots[name]
+		if !ok {
+			f.writeNotFound(w)
+			return
+		}
+		f.writeJSON(w, map[string]any{
+			"id":            name,
+			"name":          name,
+			"description":   "",
+			"project_id":    fakeProjectID,
+			"disk_id":       snapshot.diskID,
+			"size":          len(snapshot.data),
+			"state":         "ready",
+			"time_created":  time.Now(),
+			"time_modified": time.Now(),
+		})
+	})
+	mux.HandleFunc("DELETE /v1/snapshots/{snapshot}", func(w http.ResponseWriter, req *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if f.failSnapshotDelete {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			if err := json.NewEncoder(w).Encode(map[string]any{
+				"error_code": "InvalidRequest",
+				"message":    "snapshot is in use",
+				"request_id": "fake",
+			}); err != nil {
+				f.t.Errorf("failed to encode response: %v", err)
+			}
+			return
+		}
+		delete(f.snapshots, req.PathValue("snapshot"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("POST /v1/images", func(w http.ResponseWriter, req *http.Request) {
+		var body struct {
+			Name   string `json:"name"`
+			Source struct {
+				ID string `json:"id"`
+			} `json:"source"`
+		}
+		if !f.decode(w, req, &body) {
+			return
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		snapshot, ok := f.snapshots[body.Source.ID]
+		if !ok {
+			f.writeNotFound(w)
+			return
+		}
+		f.lastID++
+		id := fmt.Sprintf("00000000-0000-0000-0000-%012d", f.lastID)
+		f.images[id] = fakeImage{name: body.Name, data: snapshot.data}
+		f.writeImage(w, id)
+	})
+	mux.HandleFunc("GET /v1/images/{image}", func(w http.ResponseWriter, req *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		id := req.PathValue("image")
+		if _, ok := f.images[id]; !ok {
+			f.writeNotFound(w)
+			return
+		}
+		f.writeImage(w, id)
+	})
+	mux.HandleFunc("DELETE /v1/images/{image}", func(w http.ResponseWriter, req *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.images, req.PathValue("image"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	return mux
+}
+
+func (f *fakeImportAPI) noContent(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (f *fakeImportAPI) decode(w http.ResponseWriter, req *http.Request, v any) bool {
+	if err := json.NewDecoder(req.Body).Decode(v); err != nil {
+		f.t.Errorf("failed to decode request body: %v", err)
+		http.Error(w, "invalid body", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func (f *fakeImportAPI) writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		f.t.Errorf("failed to encode response: %v", err)
+	}
+}
+
+func (f *fakeImportAPI) writeNotFound(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	if err := json.NewEncoder(w).Encode(map[string]any{
+		"error_code": "ObjectNotFound",
+		"message":    "not found",
+		"request_id": "fake",
+	}); err != nil {
+		f.t.Errorf("failed to encode response: %v", err)
+	}
+}
+
+func (f *fakeImportAPI) writeDisk(w http.ResponseWriter, name string) {
+	disk := f.disks[name]
+	f.writeJSON(w, map[string]any{
+		"id":            name,
+		"name":          name,
+		"description":   disk.description,
+		"project_id":    fakeProjectID,
+		"size":          disk.size,
+		"block_size":    512,
+		"state":         map[string]any{"state": disk.state},
+		"time_created":  time.Now(),
+		"time_modified": time.Now(),
+	})
+}
+
+func (f *fakeImportAPI) writeImage(w http.ResponseWriter, id string) {
+	image := f.images[id]
+	sum := sha256.Sum256(image.data)
+	f.writeJSON(w, map[string]any{
+		"id":            id,
+		"name":          image.name,
+		"description":   "an imported image",
+		"os":            "alpine",
+		"version":       "3.22",
+		"project_id":    fakeProjectID,
+		"size":          len(image.data),
+		"block_size":    512,
+		"digest":        map[string]any{"type": "sha256", "value": hex.EncodeToString(sum[:])},
+		"time_created":  time.Now(),
+		"time_modified": time.Now(),
+	})
+}
+
+// checkImage checks that the image holds the contents of the file and that the
+// temporary disk and snapshot are deleted.
+func (f *fakeImportAPI) checkImage(resourceName string, contents []byte) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", resourceName)
+		}
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		image, ok := f.images[rs.Primary.ID]
+		if !ok {
+			return fmt.Errorf("image (%v) does not exist", rs.Primary.ID)
+		}
+		if !bytes.Equal(image.data, contents) {
+			return fmt.Errorf("image (%v) does not hold the file contents", rs.Primary.ID)
+		}
+		if len(f.disks) != 0 || len(f.snapshots) != 0 {
+			return fmt.Errorf("temporary import disks or snapshots were not deleted")
+		}
+		return nil
+	}
+}
+
+func (f *fakeImportAPI) checkDestroy(_ *terraform.State) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.images) != 0 {
+		return fmt.Errorf("%d images still exist", len(f.images))
+	}
+	return nil
+}
+
+func TestResourceImage_sourceFile(t *testing.T) {
+	api := newFakeImportAPI(t)
+	ts := httptest.NewServer(api.handler())
+	t.Cleanup(func() {
+		ts.Close()
+	})
+
+	sourceFile := filepath.Join(t.TempDir(), "image.raw")
+
+	// The first half is zeroed and doesn't need to be written.
+	first := make([]byte, 1024*1024)
+	for i := len(first) / 2; i < len(first); i++ {
+		first[i] = 'a'
+	}
+	second := bytes.Repeat([]byte{'b'}, 2048)
+	qcow2 := append([]byte{'Q', 'F', 'I', 0xfb}, make([]byte, 508)...)
+
+	writeFile := func(contents []byte) func() {
+		return func() {
+			if err := os.WriteFile(sourceFile, contents, 0o600); err != nil {
+				t.Fatalf("failed to write source file: %v", err)
+			}
+		}
+	}
+	digest := func(contents []byte) string {
+		sum := sha256.Sum256(contents)
+		return hex.EncodeToString(sum[:])
+	}
+
+	blockName := sharedtest.NewBlockName("image-source-file")
+	resourceName := fmt.Sprintf("oxide_image.%s", blockName)
+	cfg := importResourceConfig{
+		Host:       ts.URL,
+		BlockName:  blockName,
+		ImageName:  sharedtest.NewResourceName(),
+		SourceFile: sourceFile,
+	}
+
+	var firstID string
+
+	//lintignore:AT004 // Provider must connect to test server.
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		CheckDestroy:             api.checkDestroy,
+		Steps: []resource.TestStep{
+			{
+				PreConfig:   writeFile(qcow2),
+				Config:      sharedtest.ParsedAccConfig(t, cfg, importResourceConfigTpl),
+				ExpectError: regexp.MustCompile(`qcow2 images are not supported`),
+			},
+			{
+				PreConfig: writeFile(first),
+				Config:    sharedtest.ParsedAccConfig(t, cfg, importResourceConfigTpl),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						resourceName, "source_file_sha256", digest(first),
+					),
+					resource.TestCheckNoResourceAttr(resourceName, "source_snapshot_id"),
+					api.checkImage(resourceName, first),
+					func(s *terraform.State) error {
+						firstID = s.RootModule().Resources[resourceName].Primary.ID
+
+						api.mu.Lock()
+						defer api.mu.Unlock()
+						// Only the chunk with data is written.
+						if api.writes != 1 {
+							return fmt.Errorf("expected 1 bulk write, got %d", api.writes)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// Changing the contents of the file replaces the image.
+				PreConfig: writeFile(second),
+				Config:    sharedtest.ParsedAccConfig(t, cfg, importResourceConfigTpl),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						resourceName, "source_file_sha256", digest(second),
+					),
+					api.checkImage(resourceName, second),
+					func(s *terraform.State) error {
+						id := s.RootModule().Resources[resourceName].Primary.ID
+						if id == firstID {
+							return fmt.Errorf("expected image to be replaced")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestResourceImage_interruptedImport(t *testing.T) {
+	api := newFakeImportAPI(t)
+	ts := httptest.NewServer(api.handler())
+	t.Cleanup(func() {
+		ts.Close()
+	})
+
+	// Two chunks, so a disk can be left behind with half of the contents.
+	contents := bytes.Repeat([]byte{'d'}, 1024*1024)
+	stale := bytes.Repeat([]byte{'e'}, 1024*1024)
+	sourceFile := filepath.Join(t.TempDir(), "image.raw")
+	if err := os.WriteFile(sourceFile, contents, 0o600); err != nil {
+		t.Fatalf("failed to write source file: %v", err)
+	}
+
+	blockName := sharedtest.NewBlockName("image-interrupted-import")
+	resourceName := fmt.Sprintf("oxide_image.%s", blockName)
+	cfgStale := importResourceConfig{
+		Host:       ts.URL,
+		BlockName:  blockName,
+		ImageName:  sharedtest.NewResourceName(),
+		SourceFile: sourceFile,
+	}
+
+	// Each step uses a new image name, so it starts from what its PreConfig
+	// left behind.
+	cfgSnapshot := cfgStale
+	cfgSnapshot.ImageName = sharedtest.NewResourceName()
+	cfgDisk := cfgStale
+	cfgDisk.ImageName = sharedtest.NewResourceName()
+
+	//lintignore:AT004 // Provider must connect to test server.
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		CheckDestroy:             api.checkDestroy,
+		Steps: []resource.TestStep{
+			{
+				// A snapshot of other contents is deleted, not reused.
+				PreConfig: func() {
+					api.leaveImport(cfgStale.ImageName+"-import", stale, len(stale), true)
+				},
+				Config: sharedtest.ParsedAccConfig(t, cfgStale, importResourceConfigTpl),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					api.checkImage(resourceName, contents),
+					api.checkRequests(1, 2),
+				),
+			},
+			{
+				// A snapshot of the same contents is reused.
+				PreConfig: func() {
+					api.leaveImport(cfgSnapshot.ImageName+"-import", contents, len(contents), true)
+				},
+				Config: sharedtest.ParsedAccConfig(t, cfgSnapshot, importResourceConfigTpl),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					api.checkImage(resourceName, contents),
+					api.checkRequests(0, 0),
+				),
+			},
+			{
+				// A disk with part of the same contents is reused, and the
+				// contents are uploaded again.
+				PreConfig: func() {
+					api.leaveImport(cfgDisk.ImageName+"-import", contents, len(contents)/2, false)
+				},
+				Config: sharedtest.ParsedAccConfig(t, cfgDisk, importResourceConfigTpl),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					api.checkImage(resourceName, contents),
+					api.checkRequests(0, 2),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceImage_snapshotDeleteFails(t *testing.T) {
+	api := newFakeImportAPI(t)
+	api.failSnapshotDelete = true
+	ts := httptest.NewServer(api.handler())
+	t.Cleanup(func() {
+		ts.Close()
+	})
+
+	contents := bytes.Repeat([]byte{'f'}, 512*1024)
+	sourceFile := filepath.Join(t.TempDir(), "image.raw")
+	if err := os.WriteFile(sourceFile, contents, 0o600); err != nil {
+		t.Fatalf("failed to write source file: %v", err)
+	}
+
+	blockName := sharedtest.NewBlockName("image-snapshot-delete-fails")
+	resourceName := fmt.Sprintf("oxide_image.%s", blockName)
+	cfg := importResourceConfig{
+		Host:       ts.URL,
+		BlockName:  blockName,
+		ImageName:  sharedtest.NewResourceName(),
+		SourceFile: sourceFile,
+	}
+
+	//lintignore:AT004 // Provider must connect to test server.
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		CheckDestroy:             api.checkDestroy,
+		Steps: []resource.TestStep{
+			{
+				// The image is saved to the state with a warning instead of
+				// being orphaned.
+				Config: sharedtest.ParsedAccConfig(t, cfg, importResourceConfigTpl),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "id"),
+					func(s *terraform.State) error {
+						id := s.RootModule().Resources[resourceName].Primary.ID
+
+						api.mu.Lock()
+						defer api.mu.Unlock()
+						if image, ok := api.images[id]; !ok || !bytes.Equal(image.data, contents) {
+							return fmt.Errorf("image (%v) does not hold the file contents", id)
+						}
+						if len(api.snapshots) != 1 {
+							return fmt.Errorf("expected the imported snapshot to be left behind")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestResourceImage_sourceURL(t *testing.T) {
+	api := newFakeImportAPI(t)
+	ts := httptest.NewServer(api.handler())
+	t.Cleanup(func() {
+		ts.Close()
+	})
+
+	contents := bytes.Repeat([]byte{'c'}, 3*512*1024)
+	sum := sha256.Sum256(contents)
+
+	var interrupted, resumed atomic.Bool
+	imageServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/alpine.raw" {
+			http.NotFound(w, req)
+			return
+		}
+
+		// Cut the first download short so it's resumed with a range request.
+		if !interrupted.Swap(true) {
+			w.Header().Set("Content-Length", fmt.Sprint(len(contents)))
+			if _, err := w.Write(contents[:len(contents)/2]); err != nil {
+				t.Errorf("failed to write response: %v", err)
+			}
+			return
+		}
+		if req.Header.Get("Range") != "" {
+			resumed.Store(true)
+		}
+		http.ServeContent(w, req, "alpine.raw", time.Time{}, bytes.NewReader(contents))
+	}))
+	t.Cleanup(func() {
+		imageServer.Close()
+	})
+
+	blockName := sharedtest.NewBlockName("image-source-url")
+	resourceName := fmt.Sprintf("oxide_image.%s", blockName)
+	cfg := urlResourceConfig{
+		Host:      ts.URL,
+		BlockName: blockName,
+		ImageName: sharedtest.NewResourceName(),
+		SourceURL: imageServer.URL + "/alpine.raw",
+		SHA256:    strings.Repeat("0", 64),
+	}
+	cfgValid := cfg
+	cfgValid.SHA256 = hex.EncodeToString(sum[:])
+
+	//lintignore:AT004 // Provider must connect to test server.
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		CheckDestroy:             api.checkDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      sharedtest.ParsedAccConfig(t, cfg, urlResourceConfigTpl),
+				ExpectError: regexp.MustCompile(`SHA-256 checksum mismatch`),
+			},
+			{
+				Config: sharedtest.ParsedAccConfig(t, cfgValid, urlResourceConfigTpl),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "source_url", cfg.SourceURL),
+					resource.TestCheckResourceAttr(
+						resourceName, "source_url_sha256", cfgValid.SHA256,
+					),
+					api.checkImage(resourceName, contents),
+					func(_ *terraform.State) error {
+						if !resumed.Load() {
+							return fmt.Errorf("expected the download to be resumed")
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestResourceImage_sourceURLTLS(t *testing.T) {
+	api := newFakeImportAPI(t)
+	ts := httptest.NewServer(api.handler())
+	t.Cleanup(func() {
+		ts.Close()
+	})
+
+	contents := bytes.Repeat([]byte{'g'}, 512*1024)
+	stale := bytes.Repeat([]byte{'h'}, 512*1024)
+	sum := sha256.Sum256(contents)
+
+	serveImage := func(w http.ResponseWriter, req *http.Request) {
+		http.ServeContent(w, req, "alpine.raw", time.Time{}, bytes.NewReader(contents))
+	}
+	imageServer := httptest.NewTLSServer(http.HandlerFunc(serveImage))
+	t.Cleanup(func() {
+		imageServer.Close()
+	})
+	caBundle := string(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: imageServer.Certificate().Raw,
+	}))
+
+	blockName := sharedtest.NewBlockName("image-source-url-tls")
+	resourceName := fmt.Sprintf("oxide_image.%s", blockName)
+	cfg := urlResourceConfig{
+		Host:      ts.URL,
+		BlockName: blockName,
+		ImageName: sharedtest.NewResourceName(),
+		SourceURL: imageServer.URL + "/alpine.raw",
+		SHA256:    hex.EncodeToString(sum[:]),
+	}
+	cfgCABundle := cfg
+	cfgCABundle.CABundle = caBundle
+
+	//lintignore:AT004 // Provider must connect to test server.
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		CheckDestroy:             api.checkDestroy,
+		Steps: []resource.TestStep{
+			{
+				// The certificate of the image server isn't trusted without
+				// the provider's ca_bundle.
+				Config:      sharedtest.ParsedAccConfig(t, cfg, urlResourceConfigTpl),
+				ExpectError: regexp.MustCompile(`certificate`),
+			},
+			{
+				// A snapshot left behind by a download of other contents
+				// isn't reused.
+				PreConfig: func() {
+					api.leaveImport(cfg.ImageName+"-import", stale, len(stale), true)
+				},
+				Config: sharedtest.ParsedAccConfig(t, cfgCABundle, urlResourceConfigTpl),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					api.checkImage(resourceName, contents),
+					api.checkRequests(1, 1),
+				),
+			},
+		},
+	})
+}
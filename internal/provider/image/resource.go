@@ -7,13 +7,17 @@ package image
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"regexp"
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -26,8 +30,9 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = (*Resource)(nil)
-	_ resource.ResourceWithConfigure = (*Resource)(nil)
+	_ resource.Resource               = (*Resource)(nil)
+	_ resource.ResourceWithConfigure  = (*Resource)(nil)
+	_ resource.ResourceWithModifyPlan = (*Resource)(nil)
 )
 
 // NewResource is a helper function to simplify the provider implementation.
@@ -37,7 +42,9 @@ func NewResource() resource.Resource {
 
 // Resource is the resource implementation.
 type Resource struct {
-	client *oxide.Client
+	client           *oxide.Client
+	downloadClient   *http.Client
+	defaultProjectID string
 }
 
 type ResourceModel struct {
@@ -49,13 +56,28 @@ type ResourceModel struct {
 	OS               types.String   `tfsdk:"os"`
 	ProjectID        types.String   `tfsdk:"project_id"`
 	Size             types.Int64    `tfsdk:"size"`
+	SourceFile       types.String   `tfsdk:"source_file"`
+	SourceFileSHA256 types.String   `tfsdk:"source_file_sha256"`
 	SourceSnapshotID types.String   `tfsdk:"source_snapshot_id"`
+	SourceURL        types.String   `tfsdk:"source_url"`
+	SourceURLSHA256  types.String   `tfsdk:"source_url_sha256"`
 	TimeCreated      types.String   `tfsdk:"time_created"`
 	TimeModified     types.String   `tfsdk:"time_modified"`
 	Version          types.String   `tfsdk:"version"`
+	Visibility       types.String   `tfsdk:"visibility"`
 	Timeouts         timeouts.Value `tfsdk:"timeouts"`
 }
 
+const (
+	// visibilityProject is the visibility of images that can only be used
+	// in the project that contains them.
+	visibilityProject = "project"
+
+	// visibilitySilo is the visibility of images that can be used in every
+	// project of the silo.
+	visibilitySilo = "silo"
+)
+
 type DigestResourceModel struct {
 	Type  types.String `tfsdk:"type"`
 	Value types.String `tfsdk:"value"`
@@ -80,7 +102,61 @@ func (r *Resource) Configure(
 		return
 	}
 
-	r.client = req.ProviderData.(*oxide.Client)
+	providerData := req.ProviderData.(*shared.ProviderData)
+	r.client = providerData.Client
+	r.downloadClient = providerData.DownloadClient
+	r.defaultProjectID = providerData.DefaultProjectID
+}
+
+// ModifyPlan sets project_id to the provider's default project when it's not
+// set in the configuration. It also computes the SHA-256 digest of
+// source_file so the image is replaced when the contents of the file change.
+func (r *Resource) ModifyPlan(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	shared.ModifyPlanForDefaultProject(ctx, r.defaultProjectID, req, resp)
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var sourceFile types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("source_file"), &sourceFile)...)
+	if resp.Diagnostics.HasError() || sourceFile.IsUnknown() {
+		return
+	}
+
+	digest := types.StringNull()
+	if !sourceFile.IsNull() {
+		sum, err := fileSHA256(sourceFile.ValueString())
+		if err == nil {
+			err = validateSourceFile(sourceFile.ValueString())
+		}
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("source_file"),
+				"Unable to read source file",
+				err.Error(),
+			)
+			return
+		}
+		digest = types.StringValue(sum)
+	}
+	resp.Diagnostics.Append(
+		resp.Plan.SetAttribute(ctx, path.Root("source_file_sha256"), digest)...,
+	)
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+	var stateDigest types.String
+	resp.Diagnostics.Append(
+		req.State.GetAttribute(ctx, path.Root("source_file_sha256"), &stateDigest)...,
+	)
+	if !sourceFile.IsNull() && !stateDigest.Equal(digest) {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("source_file_sha256"))
+	}
 }
 
 // ImportState imports an existing image resource into Terraform state.
@@ -89,7 +165,24 @@ func (r *Resource) ImportState(
 	req resource.ImportStateRequest,
 	resp *resource.ImportStateResponse,
 ) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	shared.ImportStateByPath(
+		ctx,
+		req,
+		resp,
+		path.Root("id"),
+		"project/image",
+		r.defaultProjectID,
+		func(ctx context.Context, names []string) (string, error) {
+			image, err := r.client.ImageView(ctx, oxide.ImageViewParams{
+				Project: oxide.NameOrId(names[0]),
+				Image:   oxide.NameOrId(names[1]),
+			})
+			if err != nil {
+				return "", err
+			}
+			return image.Id, nil
+		},
+	)
 }
 
 // Schema defines the schema for the resource.
@@ -99,9 +192,13 @@ func (r *Resource) Schema(
 	resp *resource.SchemaResponse,
 ) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: `
+		MarkdownDescription: shared.ReplaceBackticks(`
 This resource manages images.
-`,
+
+Images are created from a snapshot with ''source_snapshot_id'', from a local raw disk image with ''source_file'', or from a raw disk image downloaded from ''source_url''. Files and downloads are streamed to the disk bulk import API. The image is replaced when the contents of the file or the expected digest of the download change.
+
+The temporary disk and snapshot used for an upload are named after the image with an ''-import'' suffix, and are deleted once the image is created. If an upload is interrupted, the next apply uploads the image again. The disk or snapshot left behind is reused when it holds the same contents, as recorded by the SHA-256 digest in the description of the disk, and deleted otherwise.
+`),
 		Attributes: map[string]schema.Attribute{
 			"name": schema.StringAttribute{
 				Required:    true,
@@ -111,13 +208,14 @@ This resource manages images.
 				},
 			},
 			"project_id": schema.StringAttribute{
-				Required:    true,
-				Description: "ID of the project that will contain the image.",
+				Optional:    true,
+				Computed:    true,
+				Description: "ID of the project that will contain the image. Defaults to the provider's `default_project`.",
 				Validators: []validator.String{
 					oxidevalidator.IsUUID(),
 				},
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIfConfigured(),
 				},
 			},
 			"description": schema.StringAttribute{
@@ -146,15 +244,77 @@ This resource manages images.
 				},
 			},
 			"source_snapshot_id": schema.StringAttribute{
-				Required:    true,
-				Description: "Snapshot ID of the image source if applicable.",
+				Optional: true,
+				MarkdownDescription: shared.ReplaceBackticks(
+					`Snapshot ID of the image source. Exactly one of ''source_snapshot_id'', ''source_file'' and ''source_url'' must be set.`,
+				),
 				Validators: []validator.String{
 					oxidevalidator.IsUUID(),
+					stringvalidator.ExactlyOneOf(
+						path.MatchRoot("source_file"),
+						path.MatchRoot("source_url"),
+					),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"source_file": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: shared.ReplaceBackticks(
+					`Path to a local raw disk image to upload as the image source. The file size must be a multiple of 512 bytes. qcow2 files must be converted to raw first. The image is replaced when the contents of the file change.`,
+				),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"source_file_sha256": schema.StringAttribute{
+				Computed:    true,
+				Description: "SHA-256 digest of the contents of the uploaded source file.",
+			},
+			"source_url": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: shared.ReplaceBackticks(
+					`HTTP or HTTPS URL of a raw disk image to download and upload as the image source. The server must send a ''Content-Length'' header, and support range requests for interrupted downloads to be resumed. The download uses the provider's ''ca_bundle'', ''insecure_skip_verify'' and ''proxy_url'' settings. Requires ''source_url_sha256''.`,
+				),
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^https?://`),
+						"must be an HTTP or HTTPS URL",
+					),
+					stringvalidator.AlsoRequires(path.MatchRoot("source_url_sha256")),
 				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"source_url_sha256": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: shared.ReplaceBackticks(
+					`Expected hex encoded SHA-256 digest of the image at ''source_url''. The image is not created when the downloaded contents don't match, and is replaced when the digest changes.`,
+				),
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(
+						regexp.MustCompile(`^[0-9a-f]{64}$`),
+						"must be a lowercase hex encoded SHA-256 digest",
+					),
+					stringvalidator.AlsoRequires(path.MatchRoot("source_url")),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"visibility": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				MarkdownDescription: shared.ReplaceBackticks(
+					`Visibility of the image. A ''project'' image can only be used in ''project_id'', and a ''silo'' image can be used in every project of the silo. Changing the visibility promotes or demotes the image without replacing it. Defaults to ''project''.`,
+				),
+				Default: stringdefault.StaticString(visibilityProject),
+				Validators: []validator.String{
+					stringvalidator.OneOf(visibilityProject, visibilitySilo),
+				},
+			},
 			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
 				Create: true,
 				Read:   true,
@@ -230,16 +390,59 @@ func (r *Resource) Create(
 		},
 	}
 
+	// imported is the snapshot a file or download is imported into.
+	var imported *oxide.Snapshot
+	snapshotID := plan.SourceSnapshotID.ValueString()
+	switch {
+	case !plan.SourceFile.IsNull():
+		snapshot, err := importFile(
+			ctx,
+			r.client,
+			plan.ProjectID.ValueString(),
+			plan.Name.ValueString(),
+			plan.SourceFile.ValueString(),
+			plan.SourceFileSHA256.ValueString(),
+		)
+		if err != nil {
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+				"Error importing source file",
+				err,
+			))
+			return
+		}
+		imported = snapshot
+		snapshotID = snapshot.Id
+	case !plan.SourceURL.IsNull():
+		snapshot, err := importURL(
+			ctx,
+			r.client,
+			r.downloadClient,
+			plan.ProjectID.ValueString(),
+			plan.Name.ValueString(),
+			plan.SourceURL.ValueString(),
+			plan.SourceURLSHA256.ValueString(),
+		)
+		if err != nil {
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+				"Error importing source URL",
+				err,
+			))
+			return
+		}
+		imported = snapshot
+		snapshotID = snapshot.Id
+	}
+
 	params.Body.Source = oxide.ImageSource{Value: &oxide.ImageSourceSnapshot{
-		Id: plan.SourceSnapshotID.ValueString(),
+		Id: snapshotID,
 	}}
 
 	image, err := r.client.ImageCreate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error creating image",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -249,6 +452,44 @@ func (r *Resource) Create(
 		map[string]any{"success": true},
 	)
 
+	// The snapshot of an imported file and its disk are only needed to create
+	// the image.
+	if imported != nil {
+		if err := r.client.SnapshotDelete(ctx, oxide.SnapshotDeleteParams{
+			Snapshot: oxide.NameOrId(imported.Id),
+		}); err != nil && !shared.Is404(err) {
+			resp.Diagnostics.AddWarning(
+				"Unable to delete imported snapshot",
+				shared.APIErrorDetail(err),
+			)
+		}
+		if err := r.client.DiskDelete(ctx, oxide.DiskDeleteParams{
+			Disk: oxide.NameOrId(imported.DiskId),
+		}); err != nil && !shared.Is404(err) {
+			resp.Diagnostics.AddWarning(
+				"Unable to delete import disk",
+				shared.APIErrorDetail(err),
+			)
+		}
+	}
+
+	// Images are always created in a project.
+	if plan.Visibility.ValueString() == visibilitySilo {
+		promoted, err := r.client.ImagePromote(ctx, oxide.ImagePromoteParams{
+			Image: oxide.NameOrId(image.Id),
+		})
+		if err != nil {
+			// Save the image so it's replaced instead of orphaned.
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+				"Error promoting image",
+				err,
+			))
+			plan.Visibility = types.StringValue(visibilityProject)
+		} else {
+			image = promoted
+		}
+	}
+
 	// Map response body to schema and populate Computed attribute values
 	plan.ID = types.StringValue(image.Id)
 	plan.Size = types.Int64Value(int64(image.Size))
@@ -282,7 +523,7 @@ func (r *Resource) Create(
 		}
 		digest, diags := types.ObjectValueFrom(ctx, attributeTypes, dm)
 		resp.Diagnostics.Append(diags...)
-		if resp.Diagnostics.HasError() {
+		if diags.HasError() {
 			return
 		}
 		plan.Digest = digest
@@ -290,9 +531,6 @@ func (r *Resource) Create(
 
 	// Save plan into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
 }
 
 // Read refreshes the Terraform state with the latest data.
@@ -327,10 +565,10 @@ func (r *Resource) Read(
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read image:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -355,8 +593,11 @@ func (r *Resource) Read(
 
 	// Only set ProjectID if it exists to avoid unintentional drift.
 	// Some images with silo visibility may not have project IDs, and could be imported.
+	// The project ID of a promoted image is kept so it can be demoted again.
+	state.Visibility = types.StringValue(visibilitySilo)
 	if image.ProjectId != "" {
 		state.ProjectID = types.StringValue(image.ProjectId)
+		state.Visibility = types.StringValue(visibilityProject)
 	}
 
 	// Parse DigestResourceModel into types.Object
@@ -400,9 +641,77 @@ func (r *Resource) Update(
 	req resource.UpdateRequest,
 	resp *resource.UpdateResponse,
 ) {
-	resp.Diagnostics.AddError(
-		"Error updating image",
-		"the oxide API currently does not support updating images")
+	var plan ResourceModel
+	var state ResourceModel
+
+	// Read Terraform plan data into the plan model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Read Terraform prior state data into the state model to retrieve the
+	// computed attributes, since every other attribute requires a replacement.
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	plan.ID = state.ID
+	plan.BlockSize = state.BlockSize
+	plan.Digest = state.Digest
+	plan.Size = state.Size
+	plan.TimeCreated = state.TimeCreated
+	plan.TimeModified = state.TimeModified
+
+	if !plan.Visibility.Equal(state.Visibility) {
+		var image *oxide.Image
+		var err error
+		switch plan.Visibility.ValueString() {
+		case visibilitySilo:
+			image, err = r.client.ImagePromote(ctx, oxide.ImagePromoteParams{
+				Image: oxide.NameOrId(state.ID.ValueString()),
+			})
+		case visibilityProject:
+			image, err = r.client.ImageDemote(ctx, oxide.ImageDemoteParams{
+				Image:   oxide.NameOrId(state.ID.ValueString()),
+				Project: oxide.NameOrId(plan.ProjectID.ValueString()),
+			})
+		}
+		if err != nil {
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+				fmt.Sprintf("Error changing image visibility to %s", plan.Visibility.ValueString()),
+				err,
+			))
+			return
+		}
+
+		tflog.Trace(
+			ctx,
+			fmt.Sprintf(
+				"changed visibility of image with ID %v to %v",
+				image.Id,
+				plan.Visibility.ValueString(),
+			),
+			map[string]any{"success": true},
+		)
+
+		plan.TimeModified = types.StringValue(image.TimeModified.String())
+	}
+
+	// Save plan into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
@@ -431,10 +740,10 @@ func (r *Resource) Delete(
 		Image: oxide.NameOrId(state.ID.ValueString()),
 	}); err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Unable to read image:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
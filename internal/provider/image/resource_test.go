@@ -27,6 +27,7 @@ type resourceConfig struct {
 	SupportBlockName  string
 	DiskBlockName     string
 	SnapshotBlockName string
+	Visibility        string
 }
 
 // TODO: Use a fetched snapshot ID when the snapshot data source is implemented
@@ -67,6 +68,9 @@ var resourceConfigTpl = `
    source_snapshot_id = oxide_snapshot.{{.SnapshotBlockName}}.id
    os                 = "alpine"
    version            = "propolis-blob"
+{{- if .Visibility}}
+   visibility         = "{{.Visibility}}"
+{{- end}}
    timeouts = {
     read   = "1m"
     create = "3m"
@@ -111,6 +115,62 @@ func TestAccCloudResourceImage_full(t *testing.T) {
 	})
 }
 
+func TestAccCloudResourceImage_visibility(t *testing.T) {
+	imageName := sharedtest.NewResourceName()
+	blockName := sharedtest.NewBlockName("image")
+	resourceName := fmt.Sprintf("oxide_image.%s", blockName)
+	cfg := resourceConfig{
+		BlockName:         blockName,
+		ImageName:         imageName,
+		DiskName:          sharedtest.NewResourceName(),
+		SnapshotName:      sharedtest.NewResourceName(),
+		SupportBlockName:  sharedtest.NewBlockName("support"),
+		DiskBlockName:     sharedtest.NewBlockName("support"),
+		SnapshotBlockName: sharedtest.NewBlockName("support"),
+		Visibility:        "silo",
+	}
+
+	// Demote the image back to the project.
+	cfgDemote := cfg
+	cfgDemote.Visibility = "project"
+
+	var imageID string
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { sharedtest.PreCheck(t) },
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		CheckDestroy:             testAccResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: sharedtest.ParsedAccConfig(t, cfg, resourceConfigTpl),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkResource(resourceName, imageName),
+					resource.TestCheckResourceAttr(resourceName, "visibility", "silo"),
+					func(s *terraform.State) error {
+						imageID = s.RootModule().Resources[resourceName].Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				Config: sharedtest.ParsedAccConfig(t, cfgDemote, resourceConfigTpl),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkResource(resourceName, imageName),
+					resource.TestCheckResourceAttr(resourceName, "visibility", "project"),
+					func(s *terraform.State) error {
+						id := s.RootModule().Resources[resourceName].Primary.ID
+						if id != imageID {
+							return fmt.Errorf("expected image %s to be updated in place, got %s",
+								imageID, id)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
 func checkResource(resourceName, imageName string) resource.TestCheckFunc {
 	return resource.ComposeAggregateTestCheckFunc([]resource.TestCheckFunc{
 		resource.TestCheckResourceAttrSet(resourceName, "id"),
@@ -36,10 +36,14 @@ type DataSource struct {
 }
 
 type DataSourceModel struct {
-	ID        types.String           `tfsdk:"id"`
-	ProjectID types.String           `tfsdk:"project_id"`
-	Timeouts  timeouts.Value         `tfsdk:"timeouts"`
-	Images    []ImageDataSourceModel `tfsdk:"images"`
+	ID         types.String           `tfsdk:"id"`
+	MostRecent types.Bool             `tfsdk:"most_recent"`
+	NameRegex  types.String           `tfsdk:"name_regex"`
+	OS         types.String           `tfsdk:"os"`
+	ProjectID  types.String           `tfsdk:"project_id"`
+	Version    types.String           `tfsdk:"version"`
+	Timeouts   timeouts.Value         `tfsdk:"timeouts"`
+	Images     []ImageDataSourceModel `tfsdk:"images"`
 }
 
 type ImageDataSourceModel struct {
@@ -78,7 +82,7 @@ func (d *DataSource) Configure(
 		return
 	}
 
-	d.client = req.ProviderData.(*oxide.Client)
+	d.client = req.ProviderData.(*shared.ProviderData).Client
 }
 
 func (d *DataSource) Schema(
@@ -88,7 +92,7 @@ func (d *DataSource) Schema(
 ) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: `
-Retrieve a list of all images belonging to a silo or project.
+Retrieve a list of all images belonging to a silo or project, optionally filtered by name, OS or version.
 `,
 		Attributes: map[string]schema.Attribute{
 			"project_id": schema.StringAttribute{
@@ -98,6 +102,25 @@ Retrieve a list of all images belonging to a silo or project.
 					oxidevalidator.IsUUID(),
 				},
 			},
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Regular expression the image names must match.",
+				Validators: []validator.String{
+					oxidevalidator.IsRegexp(),
+				},
+			},
+			"os": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return images with this OS distribution.",
+			},
+			"version": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return images with this OS version.",
+			},
+			"most_recent": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Only return the most recently created of the matching images.",
+			},
 			"id": schema.StringAttribute{
 				Computed: true,
 			},
@@ -196,10 +219,10 @@ func (d *DataSource) Read(
 	}
 	images, err := d.client.ImageList(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read images:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -212,8 +235,13 @@ func (d *DataSource) Read(
 	// Set a unique ID for the datasource payload
 	state.ID = types.StringValue(uuid.New().String())
 
+	matches := shared.NewImageFilter(state.NameRegex, state.OS, state.Version).Apply(images.Items)
+	if state.MostRecent.ValueBool() && len(matches) > 0 {
+		matches = []oxide.Image{shared.MostRecentImage(matches)}
+	}
+
 	// Map response body to model
-	for _, image := range images.Items {
+	for _, image := range matches {
 		imageState := ImageDataSourceModel{
 			BlockSize:    types.Int64Value(int64(image.BlockSize)),
 			Description:  types.StringValue(image.Description),
@@ -0,0 +1,447 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package instance
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/oxidecomputer/oxide.go/oxide"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/shared"
+	oxidevalidator "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/validator"
+)
+
+var (
+	_ datasource.DataSource              = (*DataSource)(nil)
+	_ datasource.DataSourceWithConfigure = (*DataSource)(nil)
+)
+
+// NewDataSource is a helper function to simplify the provider implementation.
+func NewDataSource() datasource.DataSource {
+	return &DataSource{}
+}
+
+// DataSource is the data source implementation.
+type DataSource struct {
+	client           *oxide.Client
+	defaultProjectID string
+}
+
+// DataSourceModel are the attributes that are supported on this data source.
+type DataSourceModel struct {
+	AffinityGroups            types.Set                `tfsdk:"affinity_groups"`
+	AntiAffinityGroups        types.Set                `tfsdk:"anti_affinity_groups"`
+	AttachedNetworkInterfaces types.Map                `tfsdk:"attached_network_interfaces"`
+	AutoRestartPolicy         types.String             `tfsdk:"auto_restart_policy"`
+	BootDiskID                types.String             `tfsdk:"boot_disk_id"`
+	CPUPlatform               types.String             `tfsdk:"cpu_platform"`
+	Description               types.String             `tfsdk:"description"`
+	DiskAttachments           types.Set                `tfsdk:"disk_attachments"`
+	EnableJumboFrames         types.Bool               `tfsdk:"enable_jumbo_frames"`
+	ExternalIPs               *ExternalIPResourceModel `tfsdk:"external_ips"`
+	Hostname                  types.String             `tfsdk:"hostname"`
+	ID                        types.String             `tfsdk:"id"`
+	Memory                    types.Int64              `tfsdk:"memory"`
+	Name                      types.String             `tfsdk:"name"`
+	NCPUs                     types.Int64              `tfsdk:"ncpus"`
+	ProjectID                 types.String             `tfsdk:"project_id"`
+	ProjectName               types.String             `tfsdk:"project_name"`
+	RunState                  types.String             `tfsdk:"run_state"`
+	SSHPublicKeys             types.Set                `tfsdk:"ssh_public_keys"`
+	TimeCreated               types.String             `tfsdk:"time_created"`
+	TimeModified              types.String             `tfsdk:"time_modified"`
+	Timeouts                  timeouts.Value           `tfsdk:"timeouts"`
+}
+
+// Metadata sets the resource type name.
+func (d *DataSource) Metadata(
+	ctx context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = "oxide_instance"
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *DataSource) Configure(
+	_ context.Context,
+	req datasource.ConfigureRequest,
+	_ *datasource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData := req.ProviderData.(*shared.ProviderData)
+	d.client = providerData.Client
+	d.defaultProjectID = providerData.DefaultProjectID
+}
+
+// Schema defines the schema for the data source.
+func (d *DataSource) Schema(
+	ctx context.Context,
+	req datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `
+Retrieve information about a specified instance, either by its ID or by its name and project.
+`,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Unique, immutable, system-controlled identifier of the instance. Exactly one of `id` or `name` must be set.",
+				Validators: []validator.String{
+					oxidevalidator.IsUUID(),
+					stringvalidator.ExactlyOneOf(path.MatchRoot("name")),
+				},
+			},
+			"name": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Name of the instance. Exactly one of `id` or `name` must be set.",
+			},
+			"project_name": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Name of the project that contains the instance. Defaults to the provider's `default_project`. Conflicts with `id`.",
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("id")),
+				},
+			},
+			"project_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "ID of the project that contains the instance.",
+			},
+			"description": schema.StringAttribute{
+				Computed:    true,
+				Description: "Human-readable free-form text about the instance.",
+			},
+			"hostname": schema.StringAttribute{
+				Computed:    true,
+				Description: "RFC1035-compliant hostname for the instance.",
+			},
+			"memory": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The amount of RAM (in bytes) allocated to the instance.",
+			},
+			"ncpus": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The number of vCPUs allocated to the instance.",
+			},
+			"auto_restart_policy": schema.StringAttribute{
+				Computed:    true,
+				Description: "The auto-restart policy for this instance.",
+			},
+			"cpu_platform": schema.StringAttribute{
+				Computed:    true,
+				Description: "The CPU platform required by this instance, if any.",
+			},
+			"enable_jumbo_frames": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether jumbo frames are enabled on the instance's primary network interface.",
+			},
+			"run_state": schema.StringAttribute{
+				Computed:    true,
+				Description: "Running state of the instance (e.g., running, stopped, starting, etc.).",
+			},
+			"boot_disk_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "ID of the disk the instance is booted from, if any.",
+			},
+			"disk_attachments": schema.SetAttribute{
+				Computed:    true,
+				Description: "IDs of the disks attached to the instance.",
+				ElementType: types.StringType,
+			},
+			"affinity_groups": schema.SetAttribute{
+				Computed:    true,
+				Description: "IDs of the affinity groups the instance is a member of.",
+				ElementType: types.StringType,
+			},
+			"anti_affinity_groups": schema.SetAttribute{
+				Computed:    true,
+				Description: "IDs of the anti-affinity groups the instance is a member of.",
+				ElementType: types.StringType,
+			},
+			"ssh_public_keys": schema.SetAttribute{
+				Computed:    true,
+				Description: "IDs of the SSH public keys transferred to the instance via cloud-init during instance creation.",
+				ElementType: types.StringType,
+			},
+			"attached_network_interfaces": schema.MapNestedAttribute{
+				Computed:    true,
+				Description: "Network interfaces attached to the instance.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "Unique, immutable, system-controlled identifier of the instance network interface.",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the instance network interface.",
+						},
+						"description": schema.StringAttribute{
+							Computed:    true,
+							Description: "Description of the instance network interface.",
+						},
+						"subnet_id": schema.StringAttribute{
+							Computed:    true,
+							Description: "ID of the VPC subnet to which the instance network interface belongs.",
+						},
+						"vpc_id": schema.StringAttribute{
+							Computed:    true,
+							Description: "ID of the VPC to which the instance network interface belongs.",
+						},
+						"instance_id": schema.StringAttribute{
+							Computed:    true,
+							Description: "ID of the instance to which the network interface belongs.",
+						},
+						"primary": schema.BoolAttribute{
+							Computed:    true,
+							Description: "True if this interface is the primary for the instance to which it's attached.",
+						},
+						"mac_address": schema.StringAttribute{
+							Computed:    true,
+							Description: "MAC address assigned to the instance network interface.",
+						},
+						"ip_stack": schema.SingleNestedAttribute{
+							Computed:    true,
+							Description: "The VPC-private IP stack for this interface.",
+							Attributes: map[string]schema.Attribute{
+								"v4": schema.SingleNestedAttribute{
+									Computed:    true,
+									Description: "VPC-private IPv4 stack for the instance network interface.",
+									Attributes: map[string]schema.Attribute{
+										"ip": schema.StringAttribute{
+											Computed:    true,
+											Description: "VPC-private IPv4 address for the instance network interface.",
+										},
+									},
+								},
+								"v6": schema.SingleNestedAttribute{
+									Computed:    true,
+									Description: "VPC-private IPv6 stack for the instance network interface.",
+									Attributes: map[string]schema.Attribute{
+										"ip": schema.StringAttribute{
+											Computed:    true,
+											Description: "VPC-private IPv6 address for the instance network interface.",
+										},
+									},
+								},
+							},
+						},
+						"transit_ips": schema.SetAttribute{
+							Computed:    true,
+							Description: "Additional IP networks this interface may send and receive traffic on.",
+							ElementType: types.StringType,
+						},
+						"time_created": schema.StringAttribute{
+							Computed:    true,
+							Description: "Timestamp of when this instance network interface was created.",
+						},
+						"time_modified": schema.StringAttribute{
+							Computed:    true,
+							Description: "Timestamp of when this instance network interface was last modified.",
+						},
+					},
+				},
+			},
+			"external_ips": schema.SingleNestedAttribute{
+				Computed:    true,
+				Description: "External IP addresses attached to the instance.",
+				Attributes: map[string]schema.Attribute{
+					"ephemeral": schema.SetNestedAttribute{
+						Computed:    true,
+						Description: "External ephemeral IPs attached to the instance.",
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"pool_id": schema.StringAttribute{
+									Computed:    true,
+									Description: "ID of the IP pool the ephemeral IP was allocated from.",
+								},
+								"ip_version": schema.StringAttribute{
+									Computed:    true,
+									Description: "IP version of the ephemeral IP.",
+								},
+							},
+						},
+					},
+					"floating": schema.SetNestedAttribute{
+						Computed:    true,
+						Description: "External floating IPs attached to the instance.",
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"id": schema.StringAttribute{
+									Computed:    true,
+									Description: "The external floating IP ID.",
+								},
+							},
+						},
+					},
+				},
+			},
+			"time_created": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp of when this instance was created.",
+			},
+			"time_modified": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp of when this instance was last modified.",
+			},
+			"timeouts": timeouts.Attributes(ctx),
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *DataSource) Read(
+	ctx context.Context,
+	req datasource.ReadRequest,
+	resp *datasource.ReadResponse,
+) {
+	var state DataSourceModel
+
+	// Read Terraform configuration data into the model.
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	params := oxide.InstanceViewParams{
+		Instance: oxide.NameOrId(state.ID.ValueString()),
+	}
+	if state.ID.IsNull() {
+		project, diags := shared.ProjectOrDefault(
+			state.ProjectName,
+			"project_name",
+			d.defaultProjectID,
+		)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		params = oxide.InstanceViewParams{
+			Instance: oxide.NameOrId(state.Name.ValueString()),
+			Project:  project,
+		}
+	}
+	instance, err := d.client.InstanceView(ctx, params)
+	if err != nil {
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+			"Unable to read instance:",
+			err,
+		))
+		return
+	}
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("read instance with ID: %v", instance.Id),
+		map[string]any{"success": true},
+	)
+
+	state.ID = types.StringValue(instance.Id)
+	state.Name = types.StringValue(string(instance.Name))
+	state.Description = types.StringValue(instance.Description)
+	state.EnableJumboFrames = types.BoolPointerValue(instance.EnableJumboFrames)
+	state.Hostname = types.StringValue(string(instance.Hostname))
+	state.Memory = types.Int64Value(int64(instance.Memory))
+	state.NCPUs = types.Int64Value(int64(instance.Ncpus))
+	state.ProjectID = types.StringValue(instance.ProjectId)
+	state.RunState = types.StringValue(string(instance.RunState))
+	state.TimeCreated = types.StringValue(instance.TimeCreated.String())
+	state.TimeModified = types.StringValue(instance.TimeModified.String())
+
+	// Only set optional instance properties if they are not empty.
+	if instance.BootDiskId != "" {
+		state.BootDiskID = types.StringValue(instance.BootDiskId)
+	}
+	if instance.AutoRestartPolicy != "" {
+		state.AutoRestartPolicy = types.StringValue(string(instance.AutoRestartPolicy))
+	}
+	if instance.CpuPlatform != "" {
+		state.CPUPlatform = types.StringValue(string(instance.CpuPlatform))
+	}
+
+	// The helpers shared with the resource only need the instance ID.
+	model := ResourceModel{ID: state.ID}
+
+	state.ExternalIPs, diags = newAttachedExternalIPResourceModel(ctx, d.client, model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.SSHPublicKeys, diags = newAssociatedSSHKeysOnCreateSet(ctx, d.client, instance.Id)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.AffinityGroups, diags = newAssociatedAffinityGroupsOnCreateSet(
+		ctx,
+		d.client,
+		instance.Id,
+	)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.AntiAffinityGroups, diags = newAssociatedAntiAffinityGroupsOnCreateSet(
+		ctx,
+		d.client,
+		instance.Id,
+	)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.DiskAttachments, diags = newAttachedDisksSet(ctx, d.client, instance.Id)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, attachedNICs, diags := newAttachedNetworkInterfacesModel(ctx, d.client, model)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.AttachedNetworkInterfaces, diags = types.MapValueFrom(
+		ctx, AttachedNICType, attachedNICs,
+	)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save retrieved state into Terraform state.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,127 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package instance_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/sharedtest"
+)
+
+type dataSourceConfig struct {
+	InstanceName string
+	DiskName     string
+}
+
+var dataSourceConfigTpl = `
+data "oxide_project" "test" {
+  name = "tf-acc-test"
+}
+
+data "oxide_vpc_subnet" "default" {
+  project_name = data.oxide_project.test.name
+  vpc_name     = "default"
+  name         = "default"
+}
+
+resource "oxide_disk" "test" {
+  project_id  = data.oxide_project.test.id
+  description = "a test disk for data source"
+  name        = "{{.DiskName}}"
+  size        = 1073741824
+  block_size  = 512
+}
+
+resource "oxide_instance" "test" {
+  project_id       = data.oxide_project.test.id
+  description      = "a test instance for data source"
+  name             = "{{.InstanceName}}"
+  hostname         = "terraform-acc-myhost"
+  memory           = 1073741824
+  ncpus            = 1
+  start_on_create  = false
+  boot_disk_id     = oxide_disk.test.id
+  disk_attachments = [oxide_disk.test.id]
+  network_interfaces = [
+    {
+      subnet_id   = data.oxide_vpc_subnet.default.id
+      vpc_id      = data.oxide_vpc_subnet.default.vpc_id
+      description = "a test nic"
+      name        = "net0"
+      ip_config = {
+        v4 = {
+          ip = "auto"
+        }
+      }
+    },
+  ]
+}
+
+data "oxide_instance" "by_name" {
+  project_name = data.oxide_project.test.name
+  name         = oxide_instance.test.name
+  timeouts = {
+    read = "1m"
+  }
+}
+
+data "oxide_instance" "by_id" {
+  id = oxide_instance.test.id
+}
+`
+
+func TestAccCloudDataSourceInstance_full(t *testing.T) {
+	instanceName := sharedtest.NewResourceName()
+	config := sharedtest.ParsedAccConfig(t,
+		dataSourceConfig{
+			InstanceName: instanceName,
+			DiskName:     sharedtest.NewResourceName(),
+		},
+		dataSourceConfigTpl,
+	)
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { sharedtest.PreCheck(t) },
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		CheckDestroy:             testAccResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkDataSource("data.oxide_instance.by_name", instanceName),
+					checkDataSource("data.oxide_instance.by_id", instanceName),
+					resource.TestCheckResourceAttr(
+						"data.oxide_instance.by_name", "timeouts.read", "1m",
+					),
+				),
+			},
+		},
+	})
+}
+
+func checkDataSource(dataName, instanceName string) resource.TestCheckFunc {
+	return resource.ComposeAggregateTestCheckFunc([]resource.TestCheckFunc{
+		resource.TestCheckResourceAttrPair(dataName, "id", "oxide_instance.test", "id"),
+		resource.TestCheckResourceAttr(dataName, "name", instanceName),
+		resource.TestCheckResourceAttr(dataName, "description", "a test instance for data source"),
+		resource.TestCheckResourceAttr(dataName, "hostname", "terraform-acc-myhost"),
+		resource.TestCheckResourceAttr(dataName, "memory", "1073741824"),
+		resource.TestCheckResourceAttr(dataName, "ncpus", "1"),
+		resource.TestCheckResourceAttr(dataName, "run_state", "stopped"),
+		resource.TestCheckResourceAttrPair(dataName, "boot_disk_id", "oxide_disk.test", "id"),
+		resource.TestCheckResourceAttr(dataName, "disk_attachments.#", "1"),
+		resource.TestCheckResourceAttr(dataName, "attached_network_interfaces.%", "1"),
+		resource.TestCheckResourceAttrSet(dataName, "attached_network_interfaces.net0.id"),
+		resource.TestCheckResourceAttr(dataName, "attached_network_interfaces.net0.primary", "true"),
+		resource.TestCheckResourceAttrSet(
+			dataName, "attached_network_interfaces.net0.ip_stack.v4.ip",
+		),
+		resource.TestCheckResourceAttrSet(dataName, "project_id"),
+		resource.TestCheckResourceAttrSet(dataName, "time_created"),
+		resource.TestCheckResourceAttrSet(dataName, "time_modified"),
+	}...)
+}
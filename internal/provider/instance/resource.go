@@ -9,10 +9,15 @@ import (
 	"crypto/md5"
 	"fmt"
 	"io"
+	"net/netip"
+	"regexp"
 	"slices"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-nettypes/cidrtypes"
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/objectvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
@@ -24,8 +29,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -40,9 +48,11 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                 = (*Resource)(nil)
-	_ resource.ResourceWithConfigure    = (*Resource)(nil)
-	_ resource.ResourceWithUpgradeState = (*Resource)(nil)
+	_ resource.Resource                     = (*Resource)(nil)
+	_ resource.ResourceWithConfigure        = (*Resource)(nil)
+	_ resource.ResourceWithUpgradeState     = (*Resource)(nil)
+	_ resource.ResourceWithModifyPlan       = (*Resource)(nil)
+	_ resource.ResourceWithConfigValidators = (*Resource)(nil)
 )
 
 // NewResource is a helper function to simplify the provider implementation.
@@ -52,20 +62,27 @@ func NewResource() resource.Resource {
 
 // Resource is the resource implementation.
 type Resource struct {
-	client *oxide.Client
+	client           *oxide.Client
+	defaultProjectID string
 }
 
 type ResourceModel struct {
+	AffinityGroups            types.Set                `tfsdk:"affinity_groups"`
 	AntiAffinityGroups        types.Set                `tfsdk:"anti_affinity_groups"`
 	AutoRestartPolicy         types.String             `tfsdk:"auto_restart_policy"`
+	BootDisk                  *BootDiskResourceModel   `tfsdk:"boot_disk"`
 	BootDiskID                types.String             `tfsdk:"boot_disk_id"`
 	CPUPlatform               types.String             `tfsdk:"cpu_platform"`
 	Description               types.String             `tfsdk:"description"`
+	DesiredState              types.String             `tfsdk:"desired_state"`
 	DiskAttachments           types.Set                `tfsdk:"disk_attachments"`
 	EnableJumboFrames         types.Bool               `tfsdk:"enable_jumbo_frames"`
 	ExternalIPs               *ExternalIPResourceModel `tfsdk:"external_ips"`
 	Hostname                  types.String             `tfsdk:"hostname"`
 	ID                        types.String             `tfsdk:"id"`
+	IgnoreUnownedAntiAffinity types.Bool               `tfsdk:"ignore_unowned_anti_affinity_groups"`
+	IgnoreUnownedDisks        types.Bool               `tfsdk:"ignore_unowned_disk_attachments"`
+	IgnoreUnownedNICs         types.Bool               `tfsdk:"ignore_unowned_network_interfaces"`
 	Memory                    types.Int64              `tfsdk:"memory"`
 	Name                      types.String             `tfsdk:"name"`
 	NetworkInterfaces         []NICResourceModel       `tfsdk:"network_interfaces"`
@@ -78,6 +95,20 @@ type ResourceModel struct {
 	TimeModified              types.String             `tfsdk:"time_modified"`
 	Timeouts                  timeouts.Value           `tfsdk:"timeouts"`
 	UserData                  types.String             `tfsdk:"user_data"`
+	WaitFor                   *WaitForResourceModel    `tfsdk:"wait_for"`
+}
+
+type WaitForResourceModel struct {
+	SerialConsoleRegex types.String `tfsdk:"serial_console_regex"`
+}
+
+type BootDiskResourceModel struct {
+	ID            types.String `tfsdk:"id"`
+	Name          types.String `tfsdk:"name"`
+	SourceImageID types.String `tfsdk:"source_image_id"`
+	Size          types.Int64  `tfsdk:"size"`
+	DiskType      types.String `tfsdk:"disk_type"`
+	KeepOnDestroy types.Bool   `tfsdk:"keep_on_destroy"`
 }
 
 type NICResourceModel struct {
@@ -86,8 +117,11 @@ type NICResourceModel struct {
 	SubnetID    types.String          `tfsdk:"subnet_id"`
 	VPCID       types.String          `tfsdk:"vpc_id"`
 	IPConfig    IPConfigResourceModel `tfsdk:"ip_config"`
+	TransitIPs  types.Set             `tfsdk:"transit_ips"`
 }
 
+// Hash identifies a network interface by the attributes that can't be updated
+// in place. Transit IPs are updated in place and aren't part of the hash.
 func (nic NICResourceModel) Hash() string {
 	h := md5.New()
 
@@ -129,6 +163,7 @@ type AttachedNICResourceModel struct {
 	Primary      types.Bool           `tfsdk:"primary"`
 	MAC          types.String         `tfsdk:"mac_address"`
 	IPStack      IPStackResourceModel `tfsdk:"ip_stack"`
+	TransitIPs   types.Set            `tfsdk:"transit_ips"`
 	TimeCreated  types.String         `tfsdk:"time_created"`
 	TimeModified types.String         `tfsdk:"time_modified"`
 }
@@ -188,6 +223,9 @@ var AttachedNICType = types.ObjectType{}.WithAttributeTypes(
 				},
 			},
 		},
+		"transit_ips": types.SetType{
+			ElemType: types.StringType,
+		},
 		"time_created":  types.StringType,
 		"time_modified": types.StringType,
 	},
@@ -212,7 +250,92 @@ func (r *Resource) Configure(
 		return
 	}
 
-	r.client = req.ProviderData.(*oxide.Client)
+	providerData := req.ProviderData.(*shared.ProviderData)
+	r.client = providerData.Client
+	r.defaultProjectID = providerData.DefaultProjectID
+}
+
+// ConfigValidators returns the config validators for the resource.
+func (r *Resource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		&WaitForValidator{},
+	}
+}
+
+// ModifyPlan sets project_id to the provider's default project when it's not
+// set in the configuration, and warns when applying the plan will stop and
+// restart the instance.
+func (r *Resource) ModifyPlan(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	shared.ModifyPlanForDefaultProject(ctx, r.defaultProjectID, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only updates may restart the instance.
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var state ResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var desiredState types.String
+	resp.Diagnostics.Append(
+		resp.Plan.GetAttribute(ctx, path.Root("desired_state"), &desiredState)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	switch state.DesiredState.ValueString() {
+	case "",
+		string(oxide.InstanceStateRunning),
+		string(oxide.InstanceStateStopped),
+		string(oxide.InstanceStateFailed):
+	default:
+		if !desiredState.IsNull() && !desiredState.IsUnknown() &&
+			!desiredState.Equal(state.DesiredState) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("desired_state"),
+				"Unable to change instance run state",
+				fmt.Sprintf(
+					"Instance %s is %s and can't be started or stopped. "+
+						"Apply again once it's running, stopped or failed.",
+					state.Name.ValueString(),
+					state.DesiredState.ValueString(),
+				),
+			)
+			return
+		}
+	}
+
+	// The plan can't be decoded into the model while entire nested objects,
+	// such as network_interfaces, are unknown. The changes are unknown as
+	// well in that case, so there's nothing to warn about yet.
+	var plan ResourceModel
+	if diags := resp.Plan.Get(ctx, &plan); diags.HasError() {
+		return
+	}
+
+	attributes := instanceStopRequiredAttributes(state, plan)
+	if len(attributes) == 0 ||
+		plan.DesiredState.ValueString() == string(oxide.InstanceStateStopped) {
+		return
+	}
+
+	resp.Diagnostics.AddWarning(
+		"Instance will be restarted",
+		fmt.Sprintf(
+			"Instance %s will be stopped and started again to apply changes to: %s.",
+			state.Name.ValueString(),
+			strings.Join(attributes, ", "),
+		),
+	)
 }
 
 // ImportState imports an existing instance resource into Terraform state.
@@ -221,7 +344,24 @@ func (r *Resource) ImportState(
 	req resource.ImportStateRequest,
 	resp *resource.ImportStateResponse,
 ) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	shared.ImportStateByPath(
+		ctx,
+		req,
+		resp,
+		path.Root("id"),
+		"project/instance",
+		r.defaultProjectID,
+		func(ctx context.Context, names []string) (string, error) {
+			instance, err := r.client.InstanceView(ctx, oxide.InstanceViewParams{
+				Project:  oxide.NameOrId(names[0]),
+				Instance: oxide.NameOrId(names[1]),
+			})
+			if err != nil {
+				return "", err
+			}
+			return instance.Id, nil
+		},
+	)
 }
 
 // Schema defines the schema for the resource.
@@ -235,19 +375,22 @@ func (r *Resource) Schema(
 		MarkdownDescription: shared.ReplaceBackticks(`
 This resource manages instances.
 
-!> Updates will stop and start the instance.
+!> Updates to ''memory'', ''ncpus'', ''boot_disk_id'', ''cpu_platform'', ''enable_jumbo_frames'', ''disk_attachments'', ''network_interfaces'', ''affinity_groups'' and ''anti_affinity_groups'' will stop and start the instance. Plans show a warning when this happens. Other updates are applied to the running instance.
 
 -> When setting a boot disk using ''boot_disk_id'', the boot disk ID must also be present in ''disk_attachments''.
+
+-> A boot disk created with ''boot_disk'' is deleted along with the instance unless ''boot_disk.keep_on_destroy'' is set. It must not be listed in ''disk_attachments''.
 `),
 		Attributes: map[string]schema.Attribute{
 			"project_id": schema.StringAttribute{
-				Required:    true,
-				Description: "ID for the project containing this instance.",
+				Optional:    true,
+				Computed:    true,
+				Description: "ID for the project containing this instance. Defaults to the provider's `default_project`.",
 				Validators: []validator.String{
 					oxidevalidator.IsUUID(),
 				},
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIfConfigured(),
 				},
 			},
 			"name": schema.StringAttribute{
@@ -307,13 +450,22 @@ This resource manages instances.
 				Optional:            true,
 				Computed:            true,
 				Default:             booldefault.StaticBool(false),
-				MarkdownDescription: "Whether to enable jumbo frames (8500 byte MTU) on the instance's primary network interface. Enabling this requires the fleet-wide jumbo-frames opt-in to be enabled. Changes only take effect on the next instance restart.",
+				MarkdownDescription: "Whether to enable jumbo frames (8500 byte MTU) on the instance's primary network interface. Enabling this requires the fleet-wide jumbo-frames opt-in to be enabled. A running instance is stopped and started again to apply changes.",
 			},
-			"anti_affinity_groups": schema.SetAttribute{
+			"affinity_groups": schema.SetAttribute{
 				Optional:    true,
-				Description: "IDs of the anti-affinity groups to which this instance should be added.",
+				Description: "IDs of the affinity groups to which this instance should be added.",
 				ElementType: types.StringType,
 			},
+			"anti_affinity_groups": schema.SetAttribute{
+				Optional:            true,
+				MarkdownDescription: "IDs of the anti-affinity groups to which this instance should be added.",
+				ElementType:         types.StringType,
+			},
+			"ignore_unowned_anti_affinity_groups": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Whether to ignore anti-affinity groups the instance is a member of but that aren't listed in `anti_affinity_groups`, such as groups joined with `oxide_anti_affinity_group_member`. When `false` or unset, the instance is removed from those groups.",
+			},
 			"boot_disk_id": schema.StringAttribute{
 				Optional:            true,
 				MarkdownDescription: "ID of the disk the instance should be booted from. Specifying a boot disk is optional but recommended to ensure predictable boot behavior. When provided, this ID must also be present in `disk_attachments`.",
@@ -324,6 +476,84 @@ This resource manages instances.
 					),
 				},
 			},
+			"boot_disk": schema.SingleNestedAttribute{
+				Optional:            true,
+				MarkdownDescription: "A boot disk to create along with the instance. Conflicts with `boot_disk_id`.",
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						Computed:    true,
+						Description: "Unique, immutable, system-controlled identifier of the boot disk.",
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.UseStateForUnknown(),
+						},
+					},
+					"name": schema.StringAttribute{
+						Required:    true,
+						Description: "Name of the boot disk.",
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.RequiresReplace(),
+						},
+					},
+					"source_image_id": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "ID of the image the boot disk is created from. Required when `disk_type` is `distributed`.",
+						Validators: []validator.String{
+							oxidevalidator.IsUUID(),
+						},
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.RequiresReplace(),
+						},
+					},
+					"size": schema.Int64Attribute{
+						Required:    true,
+						Description: "Size of the boot disk in bytes.",
+						PlanModifiers: []planmodifier.Int64{
+							int64planmodifier.RequiresReplace(),
+						},
+					},
+					"disk_type": schema.StringAttribute{
+						Optional:            true,
+						Computed:            true,
+						MarkdownDescription: "Type of the boot disk. Must be one of `distributed` or `local`. Defaults to `distributed`.",
+						Default:             stringdefault.StaticString(string(oxide.DiskBackendTypeDistributed)),
+						Validators: []validator.String{
+							stringvalidator.OneOf(
+								string(oxide.DiskBackendTypeDistributed),
+								string(oxide.DiskBackendTypeLocal),
+							),
+						},
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.RequiresReplace(),
+						},
+					},
+					"keep_on_destroy": schema.BoolAttribute{
+						Optional:            true,
+						Computed:            true,
+						Default:             booldefault.StaticBool(false),
+						MarkdownDescription: "Whether to keep the boot disk when the instance is destroyed. When `false`, the boot disk is deleted along with the instance.",
+					},
+				},
+				Validators: []validator.Object{
+					objectvalidator.ConflictsWith(path.MatchRoot("boot_disk_id")),
+					bootDiskValidator{},
+				},
+				PlanModifiers: []planmodifier.Object{
+					// Changes to the nested attributes are handled by their
+					// own plan modifiers, so keep_on_destroy can be updated
+					// in place.
+					objectplanmodifier.RequiresReplaceIf(
+						func(
+							_ context.Context,
+							req planmodifier.ObjectRequest,
+							resp *objectplanmodifier.RequiresReplaceIfFuncResponse,
+						) {
+							resp.RequiresReplace = req.StateValue.IsNull() != req.PlanValue.IsNull()
+						},
+						"Adding or removing the boot disk requires replacing the instance.",
+						"Adding or removing the boot disk requires replacing the instance.",
+					),
+				},
+			},
 			"start_on_create": schema.BoolAttribute{
 				Optional:    true,
 				Computed:    true,
@@ -333,11 +563,25 @@ This resource manages instances.
 					boolplanmodifier.RequiresReplaceIfConfigured(),
 				},
 			},
+			"desired_state": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The run state the instance should be in. Must be one of `running` or `stopped`. When set, the instance is started or stopped to match this value and changes to its state made outside of Terraform are detected. Takes precedence over `start_on_create`.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						string(oxide.InstanceStateRunning),
+						string(oxide.InstanceStateStopped),
+					),
+				},
+			},
 			"disk_attachments": schema.SetAttribute{
 				Optional:            true,
 				MarkdownDescription: "IDs of the disks to be attached to the instance. The order of this list does not guarantee a boot order for the instance.",
 				ElementType:         types.StringType,
 			},
+			"ignore_unowned_disk_attachments": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Whether to ignore disks that are attached to the instance but not listed in `disk_attachments`, such as disks attached by `oxide_instance_disk_attachment`. When `false` or unset, those disks are detached.",
+			},
 			"ssh_public_keys": schema.SetAttribute{
 				Optional:    true,
 				Description: "An allowlist of SSH public keys to be transferred to the instance via cloud-init during instance creation. If an empty list is provided, no public keys will be transmitted to the instance.",
@@ -357,8 +601,8 @@ This resource manages instances.
 			// added to NICResourceModel.Hash() and
 			// instanceNetworkInterfacesPlanModifier.PlanModifySet().
 			"network_interfaces": schema.SetNestedAttribute{
-				Optional:    true,
-				Description: "The network interfaces to be created for this instance.",
+				Optional:            true,
+				MarkdownDescription: "The network interfaces to be created for this instance.",
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						"name": schema.StringAttribute{
@@ -440,9 +684,21 @@ This resource manages instances.
 								},
 							},
 						},
+						"transit_ips": schema.SetAttribute{
+							Optional:    true,
+							Description: "Additional IP networks, in CIDR notation, this interface may send and receive traffic on. Each network must match an IP version configured in ip_config. Can be updated without stopping the instance.",
+							ElementType: cidrtypes.IPPrefixType{},
+							Validators: []validator.Set{
+								transitIPsValidator{},
+							},
+						},
 					},
 				},
 			},
+			"ignore_unowned_network_interfaces": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Whether to ignore network interfaces of the instance that aren't listed in `network_interfaces`, such as those created by `oxide_instance_network_interface`. When `false` or unset, those network interfaces are deleted.",
+			},
 			"attached_network_interfaces": schema.MapNestedAttribute{
 				Computed:    true,
 				Description: "Network interfaces attached to the instance.",
@@ -506,6 +762,11 @@ This resource manages instances.
 								},
 							},
 						},
+						"transit_ips": schema.SetAttribute{
+							Computed:    true,
+							Description: "Additional IP networks this interface may send and receive traffic on.",
+							ElementType: types.StringType,
+						},
 						"time_created": schema.StringAttribute{
 							Computed:    true,
 							Description: "Timestamp of when this instance network interface was created.",
@@ -588,6 +849,19 @@ Maximum 32 KiB unencoded data.`,
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"wait_for": schema.SingleNestedAttribute{
+				Optional:            true,
+				MarkdownDescription: "Conditions the guest must meet before the instance is considered created. Only applies when the instance is started on create, and can't be set when `desired_state` is `stopped`. Creation fails if they aren't met before the `create` timeout runs out.",
+				Attributes: map[string]schema.Attribute{
+					"serial_console_regex": schema.StringAttribute{
+						Required:            true,
+						MarkdownDescription: "Regular expression the serial console output must match, such as a message printed by cloud-init once it's done. It is matched against the most recent output as a whole, so use the `(?m)` flag to match `^` and `$` at line boundaries.",
+						Validators: []validator.String{
+							oxidevalidator.IsRegexp(),
+						},
+					},
+				},
+			},
 			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
 				Create: true,
 				Read:   true,
@@ -633,6 +907,7 @@ func (r *Resource) UpgradeState(ctx context.Context) map[int64]resource.StateUpg
 						Name:        oldNIC.Name,
 						SubnetID:    oldNIC.SubnetID,
 						VPCID:       oldNIC.VPCID,
+						TransitIPs:  types.SetNull(cidrtypes.IPPrefixType{}),
 					}
 
 					// ip_config was optional in schema v1, but it is now
@@ -686,26 +961,32 @@ func (r *Resource) UpgradeState(ctx context.Context) map[int64]resource.StateUpg
 				}
 
 				newState := ResourceModel{
-					AntiAffinityGroups:        oldState.AntiAffinityGroups,
-					AutoRestartPolicy:         oldState.AutoRestartPolicy,
-					BootDiskID:                oldState.BootDiskID,
-					Description:               oldState.Description,
-					DiskAttachments:           oldState.DiskAttachments,
-					ExternalIPs:               newExtIPs,
-					Hostname:                  oldState.Hostname,
-					ID:                        oldState.ID,
-					Memory:                    oldState.Memory,
-					Name:                      oldState.Name,
-					NetworkInterfaces:         newNICs,
-					AttachedNetworkInterfaces: oldState.AttachedNetworkInterfaces,
-					NCPUs:                     oldState.NCPUs,
-					ProjectID:                 oldState.ProjectID,
-					SSHPublicKeys:             oldState.SSHPublicKeys,
-					StartOnCreate:             oldState.StartOnCreate,
-					TimeCreated:               oldState.TimeCreated,
-					TimeModified:              oldState.TimeModified,
-					Timeouts:                  oldState.Timeouts,
-					UserData:                  oldState.UserData,
+					AffinityGroups:     types.SetNull(types.StringType),
+					AntiAffinityGroups: oldState.AntiAffinityGroups,
+					AutoRestartPolicy:  oldState.AutoRestartPolicy,
+					BootDiskID:         oldState.BootDiskID,
+					Description:        oldState.Description,
+					DiskAttachments:    oldState.DiskAttachments,
+					ExternalIPs:        newExtIPs,
+					Hostname:           oldState.Hostname,
+					ID:                 oldState.ID,
+					Memory:             oldState.Memory,
+					Name:               oldState.Name,
+					NetworkInterfaces:  newNICs,
+					NCPUs:              oldState.NCPUs,
+					ProjectID:          oldState.ProjectID,
+					SSHPublicKeys:      oldState.SSHPublicKeys,
+					StartOnCreate:      oldState.StartOnCreate,
+					TimeCreated:        oldState.TimeCreated,
+					TimeModified:       oldState.TimeModified,
+					Timeouts:           oldState.Timeouts,
+					UserData:           oldState.UserData,
+
+					// The attributes of attached network interfaces changed
+					// since schema v1. This is a computed attribute, so leave
+					// it as a null map that is going to be populated when the
+					// state is refreshed.
+					AttachedNetworkInterfaces: types.MapNull(AttachedNICType),
 				}
 
 				resp.Diagnostics.Append(resp.State.Set(ctx, newState)...)
@@ -742,6 +1023,7 @@ func (r *Resource) UpgradeState(ctx context.Context) map[int64]resource.StateUpg
 								IP: types.StringValue(string(oxide.Ipv4AssignmentTypeAuto)),
 							},
 						},
+						TransitIPs: types.SetNull(cidrtypes.IPPrefixType{}),
 					}
 					newNICs = append(newNICs, newNIC)
 				}
@@ -772,6 +1054,7 @@ func (r *Resource) UpgradeState(ctx context.Context) map[int64]resource.StateUpg
 				}
 
 				newState := ResourceModel{
+					AffinityGroups:     types.SetNull(types.StringType),
 					AntiAffinityGroups: oldState.AntiAffinityGroups,
 					AutoRestartPolicy:  oldState.AutoRestartPolicy,
 					BootDiskID:         oldState.BootDiskID,
@@ -841,6 +1124,13 @@ func (r *Resource) Create(
 		},
 	}
 
+	// The desired state takes precedence over start_on_create.
+	if !plan.DesiredState.IsNull() {
+		params.Body.Start = oxide.NewPointer(
+			plan.DesiredState.ValueString() == string(oxide.InstanceStateRunning),
+		)
+	}
+
 	// Add auto-restart policy if any.
 	if !plan.AutoRestartPolicy.IsNull() {
 		params.Body.AutoRestartPolicy = oxide.InstanceAutoRestartPolicy(
@@ -885,10 +1175,10 @@ func (r *Resource) Create(
 		}
 		diskView, err := r.client.DiskView(ctx, diskParams)
 		if err != nil {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Error retrieving boot disk information",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 		params.Body.BootDisk = oxide.InstanceDiskAttachment{
@@ -898,6 +1188,11 @@ func (r *Resource) Create(
 		}
 	}
 
+	// Create a boot disk along with the instance if requested.
+	if plan.BootDisk != nil {
+		params.Body.BootDisk = NewBootDiskCreate(plan.BootDisk, plan.Name.ValueString())
+	}
+
 	sshKeys, diags := shared.NewNameOrIdList(plan.SSHPublicKeys)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -905,6 +1200,13 @@ func (r *Resource) Create(
 	}
 	params.Body.SshPublicKeys = sshKeys
 
+	affinityGroupIDs, diags := shared.NewNameOrIdList(plan.AffinityGroups)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	params.Body.AffinityGroups = affinityGroupIDs
+
 	antiAffinityGroupIDs, diags := shared.NewNameOrIdList(plan.AntiAffinityGroups)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -926,7 +1228,7 @@ func (r *Resource) Create(
 	externalIPs := newExternalIPsOnCreate(plan.ExternalIPs)
 	params.Body.ExternalIps = externalIPs
 
-	nics, diags := newNetworkInterfaceAttachment(ctx, r.client, plan.NetworkInterfaces)
+	nics, diags := NewNetworkInterfaceAttachment(ctx, r.client, plan.NetworkInterfaces)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -935,10 +1237,10 @@ func (r *Resource) Create(
 
 	instance, err := r.client.InstanceCreate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error creating instance",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -948,7 +1250,20 @@ func (r *Resource) Create(
 		map[string]any{"success": true},
 	)
 
+	// The guest can only be waited for once the instance is running.
+	waitForGuest := plan.WaitFor != nil && params.Body.Start != nil && *params.Body.Start
+	if plan.DesiredState.ValueString() == string(oxide.InstanceStateRunning) || waitForGuest {
+		resp.Diagnostics.Append(
+			WaitForInstanceStart(ctx, r.client, createTimeout, instance.Id)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	// Map response body to schema and populate Computed attribute values
+	if plan.BootDisk != nil {
+		plan.BootDisk.ID = types.StringValue(instance.BootDiskId)
+	}
 	plan.EnableJumboFrames = types.BoolPointerValue(instance.EnableJumboFrames)
 	plan.ID = types.StringValue(instance.Id)
 	plan.Hostname = types.StringValue(instance.Hostname)
@@ -967,6 +1282,13 @@ func (r *Resource) Create(
 		plan.ExternalIPs.Ephemeral[i].IPVersion = ip.IPVersion
 	}
 
+	// Transit IPs can only be set once the network interfaces exist.
+	resp.Diagnostics.Append(
+		updateNICTransitIPs(ctx, r.client, instance.Id, plan.NetworkInterfaces)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Populate Computed attribute values about network interfaces.
 	_, attachedNICs, diags := newAttachedNetworkInterfacesModel(ctx, r.client, plan)
 	if diags.HasError() {
@@ -987,6 +1309,15 @@ func (r *Resource) Create(
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	// Wait for the guest after saving the state, so an instance that never
+	// becomes ready is tainted instead of being left out of the state.
+	if waitForGuest {
+		resp.Diagnostics.Append(waitForSerialConsole(
+			ctx, r.client, createTimeout, instance.Id,
+			plan.WaitFor.SerialConsoleRegex.ValueString(),
+		)...)
+	}
 }
 
 // Read refreshes the Terraform state with the latest data.
@@ -1021,10 +1352,10 @@ func (r *Resource) Read(
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read instance:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -1034,7 +1365,8 @@ func (r *Resource) Read(
 		map[string]any{"success": true},
 	)
 
-	if instance.BootDiskId != "" {
+	// A boot disk created through boot_disk is tracked there instead.
+	if instance.BootDiskId != "" && state.BootDisk == nil {
 		state.BootDiskID = types.StringValue(instance.BootDiskId)
 	}
 	if instance.AutoRestartPolicy != "" {
@@ -1054,6 +1386,12 @@ func (r *Resource) Read(
 	state.TimeCreated = types.StringValue(instance.TimeCreated.String())
 	state.TimeModified = types.StringValue(instance.TimeModified.String())
 
+	// Report the actual run state so changes made outside of Terraform are
+	// planned back to the desired state.
+	if !state.DesiredState.IsNull() {
+		state.DesiredState = types.StringValue(desiredStateFromRunState(instance.RunState))
+	}
+
 	externalIPs, diags := newAttachedExternalIPResourceModel(ctx, r.client, state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -1074,6 +1412,21 @@ func (r *Resource) Read(
 		state.SSHPublicKeys = keySet
 	}
 
+	affinityGroupSet, diags := newAssociatedAffinityGroupsOnCreateSet(
+		ctx,
+		r.client,
+		state.ID.ValueString(),
+	)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// Only set the affinity group list if there are any associated groups, or
+	// if the instance was removed from the configured groups.
+	if len(affinityGroupSet.Elements()) > 0 || !state.AffinityGroups.IsNull() {
+		state.AffinityGroups = affinityGroupSet
+	}
+
 	antiAffinityGroupSet, diags := newAssociatedAntiAffinityGroupsOnCreateSet(
 		ctx,
 		r.client,
@@ -1083,8 +1436,12 @@ func (r *Resource) Read(
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	// Only set the anti-affinity group list if there are any associated groups
-	if len(antiAffinityGroupSet.Elements()) > 0 {
+	if state.IgnoreUnownedAntiAffinity.ValueBool() {
+		antiAffinityGroupSet = ownedSet(antiAffinityGroupSet, state.AntiAffinityGroups)
+	}
+	// Only set the anti-affinity group list if there are any associated groups,
+	// or if the instance was removed from the configured groups.
+	if len(antiAffinityGroupSet.Elements()) > 0 || !state.AntiAffinityGroups.IsNull() {
 		state.AntiAffinityGroups = antiAffinityGroupSet
 	}
 
@@ -1093,8 +1450,15 @@ func (r *Resource) Read(
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	// Only set the disk list if there are disk attachments
-	if len(diskSet.Elements()) > 0 {
+	if state.BootDisk != nil {
+		diskSet = setWithout(diskSet, state.BootDisk.ID)
+	}
+	if state.IgnoreUnownedDisks.ValueBool() {
+		diskSet = ownedSet(diskSet, state.DiskAttachments)
+	}
+	// Only set the disk list if there are disk attachments, or if the
+	// configured disks were detached.
+	if len(diskSet.Elements()) > 0 || !state.DiskAttachments.IsNull() {
 		state.DiskAttachments = diskSet
 	}
 
@@ -1154,31 +1518,54 @@ func (r *Resource) Update(
 	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
 	defer cancel()
 
-	// The instance must be stopped for all updates
-	stopParams := oxide.InstanceStopParams{
+	// Keep resources that stop the instance, such as
+	// oxide_instance_network_interface, from starting it during the update.
+	defer lockInstance(state.ID.ValueString())()
+
+	current, err := r.client.InstanceView(ctx, oxide.InstanceViewParams{
 		Instance: oxide.NameOrId(state.ID.ValueString()),
-	}
-	_, err := r.client.InstanceStop(ctx, stopParams)
+	})
 	if err != nil {
-		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
-				"Unable to stop instance:",
-				"API error: "+err.Error(),
-			)
-			return
-		}
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+			"Unable to read instance:",
+			err,
+		))
+		return
 	}
 
-	diags = waitForInstanceStop(ctx, r.client, updateTimeout, state.ID.ValueString())
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
+	// Only stop the instance when one of the changes can't be applied while
+	// it's running, or when it's meant to be stopped.
+	desiredState := plan.DesiredState.ValueString()
+	needsStop := len(instanceStopRequiredAttributes(state, plan)) > 0 ||
+		desiredState == string(oxide.InstanceStateStopped)
+	stop, start := instanceUpdateTransitions(current.RunState, desiredState, needsStop)
+
+	if stop {
+		stopParams := oxide.InstanceStopParams{
+			Instance: oxide.NameOrId(state.ID.ValueString()),
+		}
+		_, err = r.client.InstanceStop(ctx, stopParams)
+		if err != nil {
+			if !shared.Is404(err) {
+				resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+					"Unable to stop instance:",
+					err,
+				))
+				return
+			}
+		}
+
+		diags = WaitForInstanceStop(ctx, r.client, updateTimeout, state.ID.ValueString())
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		tflog.Trace(
+			ctx,
+			fmt.Sprintf("stopped instance with ID: %v", state.ID.ValueString()),
+			map[string]any{"success": true},
+		)
 	}
-	tflog.Trace(
-		ctx,
-		fmt.Sprintf("stopped instance with ID: %v", state.ID.ValueString()),
-		map[string]any{"success": true},
-	)
 
 	// Update disk attachments
 	//
@@ -1189,7 +1576,7 @@ func (r *Resource) Update(
 
 	// Check plan and if it has an ID that the state doesn't then attach it
 	disksToAttach := shared.SliceDiff(planDisks, stateDisks)
-	resp.Diagnostics.Append(attachDisks(ctx, r.client, disksToAttach, state.ID.ValueString())...)
+	resp.Diagnostics.Append(AttachDisks(ctx, r.client, disksToAttach, state.ID.ValueString())...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -1226,12 +1613,17 @@ func (r *Resource) Update(
 		if !plan.BootDiskID.IsNull() {
 			params.Body.BootDisk = (*oxide.NameOrId)(plan.BootDiskID.ValueStringPointer())
 		}
+		// Keep the boot disk created through boot_disk, otherwise the update
+		// unsets it.
+		if state.BootDisk != nil {
+			params.Body.BootDisk = (*oxide.NameOrId)(state.BootDisk.ID.ValueStringPointer())
+		}
 		instance, err := r.client.InstanceUpdate(ctx, params)
 		if err != nil {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Unable to read instance:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 
@@ -1247,7 +1639,7 @@ func (r *Resource) Update(
 	// We only detach disks once we have made changes to the boot disk (if any)
 	// in case we need to remove the previous boot disk
 	disksToDetach := shared.SliceDiff(stateDisks, planDisks)
-	resp.Diagnostics.Append(detachDisks(ctx, r.client, disksToDetach, state.ID.ValueString())...)
+	resp.Diagnostics.Append(DetachDisks(ctx, r.client, disksToDetach, state.ID.ValueString())...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -1298,7 +1690,7 @@ func (r *Resource) Update(
 			attachedNICToDelete = append(attachedNICToDelete, attachedNIC)
 		}
 	}
-	resp.Diagnostics.Append(deleteNICs(ctx, r.client, attachedNICToDelete)...)
+	resp.Diagnostics.Append(DeleteNICs(ctx, r.client, attachedNICToDelete)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -1316,6 +1708,25 @@ func (r *Resource) Update(
 		return
 	}
 
+	// Update transit IPs of new network interfaces and of existing ones where
+	// they changed. This doesn't require the instance to be stopped.
+	stateTransitIPs := make(map[string]types.Set)
+	for _, nic := range state.NetworkInterfaces {
+		stateTransitIPs[nic.Hash()] = nic.TransitIPs
+	}
+	var nicsToUpdate []NICResourceModel
+	for _, nic := range plan.NetworkInterfaces {
+		transitIPs, ok := stateTransitIPs[nic.Hash()]
+		if !ok || !transitIPs.Equal(nic.TransitIPs) {
+			nicsToUpdate = append(nicsToUpdate, nic)
+		}
+	}
+	resp.Diagnostics.Append(
+		updateNICTransitIPs(ctx, r.client, state.ID.ValueString(), nicsToUpdate)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Add new external IPs.
 	resp.Diagnostics.Append(
 		attachExternalIPs(
@@ -1329,6 +1740,31 @@ func (r *Resource) Update(
 		return
 	}
 
+	// Update affinity groups
+	planAffinityGroups := plan.AffinityGroups.Elements()
+	stateAffinityGroups := state.AffinityGroups.Elements()
+
+	// Check plan and if it has an ID that the state doesn't then add it
+	affinityGroupsToAdd := shared.SliceDiff(planAffinityGroups, stateAffinityGroups)
+	resp.Diagnostics.Append(
+		addAffinityGroups(ctx, r.client, affinityGroupsToAdd, state.ID.ValueString())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Check state and if it has an ID that the plan doesn't then remove it
+	affinityGroupsToRemove := shared.SliceDiff(stateAffinityGroups, planAffinityGroups)
+	resp.Diagnostics.Append(
+		removeAffinityGroups(
+			ctx,
+			r.client,
+			affinityGroupsToRemove,
+			state.ID.ValueString(),
+		)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Update anti-affinity groups
 	planAntiAffinityGroups := plan.AntiAffinityGroups.Elements()
 	stateAntiAffinityGroups := state.AntiAffinityGroups.Elements()
@@ -1336,7 +1772,7 @@ func (r *Resource) Update(
 	// Check plan and if it has an ID that the state doesn't then add it
 	antiAffinityGroupsToAdd := shared.SliceDiff(planAntiAffinityGroups, stateAntiAffinityGroups)
 	resp.Diagnostics.Append(
-		addAntiAffinityGroups(ctx, r.client, antiAffinityGroupsToAdd, state.ID.ValueString())...)
+		AddAntiAffinityGroups(ctx, r.client, antiAffinityGroupsToAdd, state.ID.ValueString())...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -1344,7 +1780,7 @@ func (r *Resource) Update(
 	// Check state and if it has an ID that the plan doesn't then remove it
 	antiAffinityGroupsToRemove := shared.SliceDiff(stateAntiAffinityGroups, planAntiAffinityGroups)
 	resp.Diagnostics.Append(
-		removeAntiAffinityGroups(
+		RemoveAntiAffinityGroups(
 			ctx,
 			r.client,
 			antiAffinityGroupsToRemove,
@@ -1354,14 +1790,24 @@ func (r *Resource) Update(
 		return
 	}
 
-	startParams := oxide.InstanceStartParams{Instance: oxide.NameOrId(state.ID.ValueString())}
-	_, err = r.client.InstanceStart(ctx, startParams)
-	if err != nil {
-		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
-				"Unable to start instance:",
-				"API error: "+err.Error(),
-			)
+	if start {
+		startParams := oxide.InstanceStartParams{Instance: oxide.NameOrId(state.ID.ValueString())}
+		_, err = r.client.InstanceStart(ctx, startParams)
+		if err != nil {
+			if !shared.Is404(err) {
+				resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+					"Unable to start instance:",
+					err,
+				))
+				return
+			}
+		}
+	}
+
+	if desiredState == string(oxide.InstanceStateRunning) {
+		diags = WaitForInstanceStart(ctx, r.client, updateTimeout, state.ID.ValueString())
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
 			return
 		}
 	}
@@ -1372,10 +1818,10 @@ func (r *Resource) Update(
 	}
 	instance, err := r.client.InstanceView(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read instance:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -1410,8 +1856,14 @@ func (r *Resource) Update(
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	if plan.BootDisk != nil {
+		diskSet = setWithout(diskSet, plan.BootDisk.ID)
+	}
+	if plan.IgnoreUnownedDisks.ValueBool() {
+		diskSet = ownedSet(diskSet, plan.DiskAttachments)
+	}
 	// Only set the disk list if there are disk attachments
-	if len(diskSet.Elements()) > 0 {
+	if len(diskSet.Elements()) > 0 || !plan.DiskAttachments.IsNull() {
 		plan.DiskAttachments = diskSet
 	}
 
@@ -1470,15 +1922,15 @@ func (r *Resource) Delete(
 	_, err := r.client.InstanceStop(ctx, params)
 	if err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Unable to stop instance:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
 
-	diags = waitForInstanceStop(ctx, r.client, deleteTimeout, state.ID.ValueString())
+	diags = WaitForInstanceStop(ctx, r.client, deleteTimeout, state.ID.ValueString())
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -1494,10 +1946,10 @@ func (r *Resource) Delete(
 	}
 	if err := r.client.InstanceDelete(ctx, params2); err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Unable to delete instance:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
@@ -1506,10 +1958,34 @@ func (r *Resource) Delete(
 		fmt.Sprintf("deleted instance with ID: %v", state.ID.ValueString()),
 		map[string]any{"success": true},
 	)
-}
 
-func waitForInstanceStop(
-	ctx context.Context,
+	// The boot disk is detached when the instance is deleted, so it can be
+	// deleted now unless it should be kept.
+	if state.BootDisk == nil || state.BootDisk.KeepOnDestroy.ValueBool() {
+		return
+	}
+	diskParams := oxide.DiskDeleteParams{
+		Disk: oxide.NameOrId(state.BootDisk.ID.ValueString()),
+	}
+	if err := r.client.DiskDelete(ctx, diskParams); err != nil {
+		if !shared.Is404(err) {
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+				"Unable to delete boot disk:",
+				err,
+			))
+			return
+		}
+	}
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("deleted boot disk with ID: %v", state.BootDisk.ID.ValueString()),
+		map[string]any{"success": true},
+	)
+}
+
+// WaitForInstanceStop waits until the instance is stopped.
+func WaitForInstanceStop(
+	ctx context.Context,
 	client *oxide.Client,
 	timeout time.Duration,
 	instanceID string,
@@ -1539,7 +2015,7 @@ func waitForInstanceStop(
 			if err != nil {
 				if !shared.Is404(err) {
 					return nil, "nil", fmt.Errorf(
-						"while polling for the status of instance %v: %v",
+						"while polling for the status of instance %v: %w",
 						instanceID,
 						err,
 					)
@@ -1556,10 +2032,10 @@ func waitForInstanceStop(
 	}
 	if _, err := stateConfig.WaitForStateContext(ctx); err != nil {
 		if !shared.Is404(err) {
-			diags.AddError(
+			diags.Append(shared.APIErrorDiagnostic(
 				"Error stopping instance",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 		}
 		return diags
 	}
@@ -1567,6 +2043,383 @@ func waitForInstanceStop(
 	return nil
 }
 
+const (
+	// serialConsoleWaitBytes is how much of the most recent serial console
+	// output is matched against wait_for.
+	serialConsoleWaitBytes = 65536
+
+	// serialConsoleTailBytes is how much serial console output is shown when
+	// the guest isn't ready in time.
+	serialConsoleTailBytes = 2048
+)
+
+// waitForSerialConsole polls the serial console of an instance until its most
+// recent output matches pattern. When it doesn't match in time, the tail of
+// the output is included in the diagnostic to help find out why.
+func waitForSerialConsole(
+	ctx context.Context,
+	client *oxide.Client,
+	timeout time.Duration,
+	instanceID string,
+	pattern string,
+) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("wait_for").AtName("serial_console_regex"),
+			"Invalid regular expression",
+			err.Error(),
+		)
+		return diags
+	}
+
+	var output []byte
+	stateConfig := retry.StateChangeConf{
+		PollInterval: 5 * time.Second,
+		Delay:        time.Second,
+		Pending:      []string{"waiting"},
+		Target:       []string{"ready"},
+		Timeout:      timeout,
+		Refresh: func() (any, string, error) {
+			tflog.Info(ctx, fmt.Sprintf("checking serial console of instance: %v", instanceID))
+			params := oxide.InstanceSerialConsoleParams{
+				Instance:   oxide.NameOrId(instanceID),
+				MostRecent: oxide.NewPointer(serialConsoleWaitBytes),
+			}
+			console, err := client.InstanceSerialConsole(ctx, params)
+			if err != nil {
+				return nil, "nil", fmt.Errorf(
+					"while polling the serial console of instance %v: %w",
+					instanceID,
+					err,
+				)
+			}
+
+			output = make([]byte, len(console.Data))
+			for i, b := range console.Data {
+				output[i] = byte(b)
+			}
+			if re.Match(output) {
+				return console, "ready", nil
+			}
+			return console, "waiting", nil
+		},
+	}
+	if _, err := stateConfig.WaitForStateContext(ctx); err != nil {
+		tail := output
+		if len(tail) > serialConsoleTailBytes {
+			tail = tail[len(tail)-serialConsoleTailBytes:]
+		}
+		// The tail can start in the middle of a multi-byte character, and
+		// guests can write any bytes to the serial console.
+		tail = []byte(strings.ToValidUTF8(string(tail), "\uFFFD"))
+		diags.AddError(
+			"Error waiting for instance to be ready",
+			fmt.Sprintf(
+				"The serial console output of instance %v didn't match %q: %v\n\n"+
+					"Most recent serial console output:\n%s",
+				instanceID, pattern, err, tail,
+			),
+		)
+		return diags
+	}
+
+	return nil
+}
+
+// WaitForValidator validates that wait_for is not set when desired_state is
+// "stopped", since a stopped guest never becomes ready.
+type WaitForValidator struct{}
+
+func (v *WaitForValidator) Description(_ context.Context) string {
+	return `Validates that wait_for is not set when desired_state is "stopped".`
+}
+
+func (v *WaitForValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v *WaitForValidator) ValidateResource(
+	ctx context.Context,
+	req resource.ValidateConfigRequest,
+	resp *resource.ValidateConfigResponse,
+) {
+	// Read single attributes, since the whole configuration can't be decoded
+	// into the model while nested objects are unknown.
+	var desiredState types.String
+	resp.Diagnostics.Append(
+		req.Config.GetAttribute(ctx, path.Root("desired_state"), &desiredState)...)
+	var waitFor types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("wait_for"), &waitFor)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if waitFor.IsNull() || desiredState.ValueString() != string(oxide.InstanceStateStopped) {
+		return
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		path.Root("wait_for"),
+		"Invalid configuration",
+		`"wait_for" cannot be set when desired_state is "stopped".`,
+	)
+}
+
+// instanceStopRequiredAttributes returns the attributes that differ between
+// state and plan and that can only be updated while the instance is stopped.
+// Other changes, such as attaching external IPs or changing the auto-restart
+// policy, are applied to the running instance.
+func instanceStopRequiredAttributes(state, plan ResourceModel) []string {
+	var attributes []string
+
+	if !state.Memory.Equal(plan.Memory) {
+		attributes = append(attributes, "memory")
+	}
+	if !state.NCPUs.Equal(plan.NCPUs) {
+		attributes = append(attributes, "ncpus")
+	}
+	if !state.BootDiskID.Equal(plan.BootDiskID) {
+		attributes = append(attributes, "boot_disk_id")
+	}
+	if !state.CPUPlatform.Equal(plan.CPUPlatform) {
+		attributes = append(attributes, "cpu_platform")
+	}
+	if !state.EnableJumboFrames.Equal(plan.EnableJumboFrames) {
+		attributes = append(attributes, "enable_jumbo_frames")
+	}
+	if !state.DiskAttachments.Equal(plan.DiskAttachments) {
+		attributes = append(attributes, "disk_attachments")
+	}
+	if !state.AffinityGroups.Equal(plan.AffinityGroups) {
+		attributes = append(attributes, "affinity_groups")
+	}
+	if !state.AntiAffinityGroups.Equal(plan.AntiAffinityGroups) {
+		attributes = append(attributes, "anti_affinity_groups")
+	}
+
+	nicHash := func(e NICResourceModel) any {
+		return e.Hash()
+	}
+	if len(shared.SliceDiffByID(state.NetworkInterfaces, plan.NetworkInterfaces, nicHash)) > 0 ||
+		len(shared.SliceDiffByID(plan.NetworkInterfaces, state.NetworkInterfaces, nicHash)) > 0 {
+		attributes = append(attributes, "network_interfaces")
+	}
+
+	return attributes
+}
+
+// WaitForInstanceStart waits until the instance is running.
+func WaitForInstanceStart(
+	ctx context.Context,
+	client *oxide.Client,
+	timeout time.Duration,
+	instanceID string,
+) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	stateConfig := retry.StateChangeConf{
+		PollInterval: time.Second,
+		Delay:        time.Second,
+		Pending: []string{
+			string(oxide.InstanceStateCreating),
+			string(oxide.InstanceStateStarting),
+			string(oxide.InstanceStateRebooting),
+			string(oxide.InstanceStateMigrating),
+			string(oxide.InstanceStateRepairing),
+		},
+		Target:  []string{string(oxide.InstanceStateRunning)},
+		Timeout: timeout,
+		Refresh: func() (any, string, error) {
+			tflog.Info(ctx, fmt.Sprintf("checking on state of instance: %v", instanceID))
+			params := oxide.InstanceViewParams{
+				Instance: oxide.NameOrId(instanceID),
+			}
+			instance, err := client.InstanceView(ctx, params)
+			if err != nil {
+				return nil, "nil", fmt.Errorf(
+					"while polling for the status of instance %v: %w",
+					instanceID,
+					err,
+				)
+			}
+			tflog.Trace(
+				ctx,
+				fmt.Sprintf("read instance with ID: %v", instanceID),
+				map[string]any{"success": true},
+			)
+			return instance, string(instance.RunState), nil
+		},
+	}
+	if _, err := stateConfig.WaitForStateContext(ctx); err != nil {
+		diags.Append(shared.APIErrorDiagnostic(
+			"Error starting instance",
+			err,
+		))
+		return diags
+	}
+
+	return nil
+}
+
+// instanceLocks holds a *sync.Mutex per instance ID. Terraform applies the
+// resources of an instance in parallel, so neither WithInstanceStopped nor
+// Update may start an instance while another resource expects it to be
+// stopped.
+var instanceLocks sync.Map
+
+// lockInstance locks the instance with the given ID and returns the function
+// that unlocks it.
+func lockInstance(instanceID string) func() {
+	mu, _ := instanceLocks.LoadOrStore(instanceID, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
+
+// WithInstanceStopped calls fn while the instance is stopped, for changes the
+// API only allows on stopped instances. A running instance is stopped before
+// calling fn and started again afterwards, even if fn fails, and it only
+// returns once the instance is running again. Calls for the same instance are
+// serialized.
+func WithInstanceStopped(
+	ctx context.Context,
+	client *oxide.Client,
+	timeout time.Duration,
+	instanceID string,
+	fn func() diag.Diagnostics,
+) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	defer lockInstance(instanceID)()
+
+	instance, err := client.InstanceView(ctx, oxide.InstanceViewParams{
+		Instance: oxide.NameOrId(instanceID),
+	})
+	if err != nil {
+		// There's nothing to stop if the instance doesn't exist, and fn
+		// reports the error if it needs the instance.
+		if shared.Is404(err) {
+			return fn()
+		}
+		diags.Append(shared.APIErrorDiagnostic(
+			"Unable to read instance:",
+			err,
+		))
+		return diags
+	}
+
+	if instance.RunState == oxide.InstanceStateStopped {
+		return fn()
+	}
+
+	_, err = client.InstanceStop(ctx, oxide.InstanceStopParams{
+		Instance: oxide.NameOrId(instanceID),
+	})
+	if err != nil {
+		diags.Append(shared.APIErrorDiagnostic(
+			"Unable to stop instance:",
+			err,
+		))
+		return diags
+	}
+
+	diags.Append(WaitForInstanceStop(ctx, client, timeout, instanceID)...)
+	if diags.HasError() {
+		return diags
+	}
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("stopped instance with ID: %v", instanceID),
+		map[string]any{"success": true},
+	)
+
+	diags.Append(fn()...)
+
+	_, err = client.InstanceStart(ctx, oxide.InstanceStartParams{
+		Instance: oxide.NameOrId(instanceID),
+	})
+	if err != nil {
+		diags.Append(shared.APIErrorDiagnostic(
+			"Unable to start instance:",
+			err,
+		))
+		return diags
+	}
+
+	diags.Append(WaitForInstanceStart(ctx, client, timeout, instanceID)...)
+	if diags.HasError() {
+		return diags
+	}
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("started instance with ID: %v", instanceID),
+		map[string]any{"success": true},
+	)
+
+	return diags
+}
+
+// StopRequiredNotes returns the notes for the description of a resource that
+// manages part of an instance with WithInstanceStopped. changes describes what
+// the API only allows while the instance is stopped, managed what the resource
+// manages, attribute the oxide_instance attribute that also manages it, and
+// otherwise what oxide_instance does to it unless it ignores unowned ones.
+func StopRequiredNotes(changes, managed, attribute, otherwise string) string {
+	return shared.ReplaceBackticks(fmt.Sprintf(`
+!> The API only allows %[1]s while the instance is stopped. A running instance is stopped and started again to apply these changes.
+
+-> %[2]s managed by this resource should not also be listed in the ''%[3]s'' attribute of ''oxide_instance'', and that instance must set ''ignore_unowned_%[3]s = true''. Otherwise %[4]s.
+`, changes, managed, attribute, otherwise))
+}
+
+// instanceUpdateTransitions returns whether an instance in runState must be
+// stopped before an update and started after it, so that changes that need a
+// stopped instance can be applied and the instance ends up in desiredState.
+// A failed instance is treated like a stopped one, except that it's stopped
+// when desiredState is stopped, since it would be reported as failed
+// otherwise.
+func instanceUpdateTransitions(
+	runState oxide.InstanceState,
+	desiredState string,
+	needsStop bool,
+) (stop bool, start bool) {
+	failed := runState == oxide.InstanceStateFailed
+	stopped := runState == oxide.InstanceStateStopped || failed
+
+	stop = (needsStop && !stopped) ||
+		(failed && desiredState == string(oxide.InstanceStateStopped))
+
+	// Start the instance again if it was stopped for the update, or if it's
+	// meant to be running, but leave it stopped if that's its desired state.
+	start = (stop && desiredState != string(oxide.InstanceStateStopped)) ||
+		(stopped && desiredState == string(oxide.InstanceStateRunning))
+
+	return stop, start
+}
+
+// desiredStateFromRunState maps the run state of an instance to the value of
+// desired_state it satisfies. Transient states are mapped to the state the
+// instance is transitioning to, while states such as failed are returned as
+// is so they show up as a difference in the plan. Failed instances are then
+// started or stopped by Update, and ModifyPlan rejects changes to instances
+// in any other state.
+func desiredStateFromRunState(runState oxide.InstanceState) string {
+	switch runState {
+	case oxide.InstanceStateStarting,
+		oxide.InstanceStateRunning,
+		oxide.InstanceStateRebooting,
+		oxide.InstanceStateMigrating:
+		return string(oxide.InstanceStateRunning)
+	case oxide.InstanceStateStopping,
+		oxide.InstanceStateStopped:
+		return string(oxide.InstanceStateStopped)
+	default:
+		return string(runState)
+	}
+}
+
 func newAttachedDisksSet(
 	ctx context.Context,
 	client *oxide.Client,
@@ -1580,10 +2433,10 @@ func newAttachedDisksSet(
 	}
 	disks, err := client.InstanceDiskList(ctx, params)
 	if err != nil {
-		diags.AddError(
+		diags.Append(shared.APIErrorDiagnostic(
 			"Unable to list attached disks:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return types.SetNull(types.StringType), diags
 	}
 
@@ -1601,6 +2454,32 @@ func newAttachedDisksSet(
 	return diskSet, nil
 }
 
+// ownedSet returns the elements of set that are also in owned. It's used when
+// disks or groups managed by other resources are ignored.
+func ownedSet(set types.Set, owned types.Set) types.Set {
+	d := []attr.Value{}
+	for _, v := range set.Elements() {
+		if slices.ContainsFunc(owned.Elements(), v.Equal) {
+			d = append(d, v)
+		}
+	}
+
+	return types.SetValueMust(types.StringType, d)
+}
+
+// setWithout returns the elements of set other than v. It's used to leave the
+// boot disk created through boot_disk out of disk_attachments.
+func setWithout(set types.Set, v attr.Value) types.Set {
+	d := []attr.Value{}
+	for _, e := range set.Elements() {
+		if !e.Equal(v) {
+			d = append(d, e)
+		}
+	}
+
+	return types.SetValueMust(types.StringType, d)
+}
+
 func newAssociatedSSHKeysOnCreateSet(
 	ctx context.Context,
 	client *oxide.Client,
@@ -1614,10 +2493,10 @@ func newAssociatedSSHKeysOnCreateSet(
 	}
 	keys, err := client.InstanceSshPublicKeyList(ctx, params)
 	if err != nil {
-		diags.AddError(
+		diags.Append(shared.APIErrorDiagnostic(
 			"Unable to list associated SSH keys:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return types.SetNull(types.StringType), diags
 	}
 
@@ -1635,6 +2514,40 @@ func newAssociatedSSHKeysOnCreateSet(
 	return keySet, nil
 }
 
+func newAssociatedAffinityGroupsOnCreateSet(
+	ctx context.Context,
+	client *oxide.Client,
+	instanceID string,
+) (types.Set, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	params := oxide.InstanceAffinityGroupListParams{
+		Limit:    oxide.NewPointer(1000000000),
+		Instance: oxide.NameOrId(instanceID),
+	}
+	groups, err := client.InstanceAffinityGroupList(ctx, params)
+	if err != nil {
+		diags.Append(shared.APIErrorDiagnostic(
+			"Unable to list associated affinity groups:",
+			err,
+		))
+		return types.SetNull(types.StringType), diags
+	}
+
+	d := []attr.Value{}
+	for _, group := range groups.Items {
+		id := types.StringValue(group.Id)
+		d = append(d, id)
+	}
+	groupSet, setDiags := types.SetValue(types.StringType, d)
+	diags.Append(setDiags...)
+	if diags.HasError() {
+		return types.SetNull(types.StringType), diags
+	}
+
+	return groupSet, diags
+}
+
 func newAssociatedAntiAffinityGroupsOnCreateSet(
 	ctx context.Context,
 	client *oxide.Client,
@@ -1648,10 +2561,10 @@ func newAssociatedAntiAffinityGroupsOnCreateSet(
 	}
 	groups, err := client.InstanceAntiAffinityGroupList(ctx, params)
 	if err != nil {
-		diags.AddError(
+		diags.Append(shared.APIErrorDiagnostic(
 			"Unable to list associated anti-affinity groups:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return types.SetNull(types.StringType), diags
 	}
 
@@ -1669,7 +2582,9 @@ func newAssociatedAntiAffinityGroupsOnCreateSet(
 	return groupSet, nil
 }
 
-func newNetworkInterfaceAttachment(
+// NewNetworkInterfaceAttachment returns the network interfaces to create along
+// with an instance.
+func NewNetworkInterfaceAttachment(
 	ctx context.Context,
 	client *oxide.Client,
 	model []NICResourceModel,
@@ -1721,9 +2636,9 @@ func newAttachedNetworkInterfacesModel(
 	// Store network interfaces from state or plan in a map for quick retrieval
 	// of write-only attribute values that are preserved from the state or plan
 	// instead of read from the API.
-	stateIPConfigs := make(map[string]IPConfigResourceModel)
+	stateNICs := make(map[string]NICResourceModel)
 	for _, nic := range state.NetworkInterfaces {
-		stateIPConfigs[nic.Name.ValueString()] = nic.IPConfig
+		stateNICs[nic.Name.ValueString()] = nic
 	}
 
 	params := oxide.InstanceNetworkInterfaceListParams{
@@ -1732,32 +2647,57 @@ func newAttachedNetworkInterfacesModel(
 	}
 	nics, err := client.InstanceNetworkInterfaceList(ctx, params)
 	if err != nil {
-		diags.AddError(
+		diags.Append(shared.APIErrorDiagnostic(
 			"Unable to read instance network interfaces:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return []NICResourceModel{}, nil, diags
 	}
 
+	// Network interfaces that aren't in state, such as those managed by
+	// oxide_instance_network_interface, are only left out of
+	// network_interfaces when the instance ignores them.
+	ignoreUnowned := state.IgnoreUnownedNICs.ValueBool()
+
 	nicSet := []NICResourceModel{}
 	attachedNICs := make(map[string]AttachedNICResourceModel)
 	for _, nic := range nics.Items {
-		ipStack, err := newAttachedNetworkInterfacesIPStackResourceModel(nic.IpStack)
+		ipStack, err := NewAttachedNetworkInterfacesIPStackResourceModel(nic.IpStack)
 		if err != nil {
-			diags.AddError(
+			diags.Append(shared.APIErrorDiagnostic(
 				"Unable to read instance network interfaces:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return []NICResourceModel{}, nil, diags
 		}
 
-		nicSet = append(nicSet, NICResourceModel{
-			Description: types.StringValue(nic.Description),
-			IPConfig:    stateIPConfigs[string(nic.Name)],
-			Name:        types.StringValue(string(nic.Name)),
-			SubnetID:    types.StringValue(nic.SubnetId),
-			VPCID:       types.StringValue(nic.VpcId),
-		})
+		transitIPs := NewTransitIPs(nic.IpStack)
+		attachedTransitIPs, diags := types.SetValueFrom(ctx, types.StringType, transitIPs)
+		if diags.HasError() {
+			return []NICResourceModel{}, nil, diags
+		}
+
+		if stateNIC, ok := stateNICs[string(nic.Name)]; ok || !ignoreUnowned {
+			// Only set the transit IPs if there are any to avoid drift.
+			nicTransitIPs := types.SetNull(cidrtypes.IPPrefixType{})
+			if len(transitIPs) > 0 || !stateNIC.TransitIPs.IsNull() {
+				nicTransitIPs, diags = types.SetValueFrom(
+					ctx, cidrtypes.IPPrefixType{}, transitIPs,
+				)
+				if diags.HasError() {
+					return []NICResourceModel{}, nil, diags
+				}
+			}
+
+			nicSet = append(nicSet, NICResourceModel{
+				Description: types.StringValue(nic.Description),
+				IPConfig:    stateNIC.IPConfig,
+				Name:        types.StringValue(string(nic.Name)),
+				SubnetID:    types.StringValue(nic.SubnetId),
+				VPCID:       types.StringValue(nic.VpcId),
+				TransitIPs:  nicTransitIPs,
+			})
+		}
 
 		attachedNICs[string(nic.Name)] = AttachedNICResourceModel{
 			ID:           types.StringValue(nic.Id),
@@ -1769,6 +2709,7 @@ func newAttachedNetworkInterfacesModel(
 			Primary:      types.BoolPointerValue(nic.Primary),
 			MAC:          types.StringValue(string(nic.Mac)),
 			IPStack:      ipStack,
+			TransitIPs:   attachedTransitIPs,
 			TimeCreated:  types.StringValue(nic.TimeCreated.String()),
 			TimeModified: types.StringValue(nic.TimeModified.String()),
 		}
@@ -1780,9 +2721,9 @@ func newAttachedNetworkInterfacesModel(
 	return nicSet, attachedNICs, nil
 }
 
-// newAttachedNetworkInterfacesIPStackResourceModel parses a network interface IP stack
-// from the API to a resource model.
-func newAttachedNetworkInterfacesIPStackResourceModel(
+// NewAttachedNetworkInterfacesIPStackResourceModel parses a network interface
+// IP stack from the API to a resource model.
+func NewAttachedNetworkInterfacesIPStackResourceModel(
 	stack oxide.PrivateIpStack,
 ) (IPStackResourceModel, error) {
 	switch s := stack.Value.(type) {
@@ -1836,10 +2777,10 @@ func newAttachedExternalIPResourceModel(
 		},
 	)
 	if err != nil {
-		diags.AddError(
+		diags.Append(shared.APIErrorDiagnostic(
 			"Unable to list instance external ips:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return nil, diags
 	}
 
@@ -1897,10 +2838,10 @@ func retrieveVPCandSubnetNames(ctx context.Context, client *oxide.Client, vpcID,
 	}
 	vpc, err := client.VpcView(ctx, params)
 	if err != nil {
-		diags.AddError(
+		diags.Append(shared.APIErrorDiagnostic(
 			"Unable to read information about corresponding VPC:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return vpcAndSubnetNames{}, diags
 	}
 	tflog.Trace(ctx, fmt.Sprintf("read VPC with ID: %v", vpcID), map[string]any{"success": true})
@@ -1910,10 +2851,10 @@ func retrieveVPCandSubnetNames(ctx context.Context, client *oxide.Client, vpcID,
 	}
 	subnet, err := client.VpcSubnetView(ctx, params2)
 	if err != nil {
-		diags.AddError(
+		diags.Append(shared.APIErrorDiagnostic(
 			"Unable to read information about corresponding subnet:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return vpcAndSubnetNames{}, diags
 	}
 	tflog.Trace(ctx, fmt.Sprintf("read subnet with ID: %v", subnetID),
@@ -1947,10 +2888,10 @@ func newDiskAttachmentsOnCreate(
 		}
 		disk, err := client.DiskView(ctx, params)
 		if err != nil {
-			diags.AddError(
+			diags.Append(shared.APIErrorDiagnostic(
 				"Error retrieving disk information",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return []oxide.InstanceDiskAttachment{}, diags
 		}
 
@@ -1965,6 +2906,34 @@ func newDiskAttachmentsOnCreate(
 	return disks, diags
 }
 
+// NewBootDiskCreate returns the disk attachment that creates the boot disk
+// along with the instance.
+func NewBootDiskCreate(
+	bootDisk *BootDiskResourceModel,
+	instanceName string,
+) oxide.InstanceDiskAttachment {
+	var diskBackend oxide.DiskBackend
+	switch oxide.DiskBackendType(bootDisk.DiskType.ValueString()) {
+	case oxide.DiskBackendTypeLocal:
+		diskBackend = oxide.DiskBackend{Value: &oxide.DiskBackendLocal{}}
+	default:
+		diskBackend = oxide.DiskBackend{Value: &oxide.DiskBackendDistributed{
+			DiskSource: oxide.DiskSource{Value: &oxide.DiskSourceImage{
+				ImageId: bootDisk.SourceImageID.ValueString(),
+			}},
+		}}
+	}
+
+	return oxide.InstanceDiskAttachment{
+		Value: &oxide.InstanceDiskAttachmentCreate{
+			Description: fmt.Sprintf("Boot disk for instance %s", instanceName),
+			DiskBackend: diskBackend,
+			Name:        oxide.Name(bootDisk.Name.ValueString()),
+			Size:        oxide.ByteCount(bootDisk.Size.ValueInt64()),
+		},
+	}
+}
+
 func diskAttachmentName(d oxide.InstanceDiskAttachment) oxide.Name {
 	switch v := d.Value.(type) {
 	case *oxide.InstanceDiskAttachmentAttach:
@@ -2088,64 +3057,227 @@ func newIPStackCreateV6(stack *IPConfigV6ResourceModel) oxide.PrivateIpv6StackCr
 		}
 	}
 
-	return oxide.PrivateIpv6StackCreate{
-		Ip: oxide.Ipv6Assignment{
-			Value: &oxide.Ipv6AssignmentExplicit{Value: ip},
+	return oxide.PrivateIpv6StackCreate{
+		Ip: oxide.Ipv6Assignment{
+			Value: &oxide.Ipv6AssignmentExplicit{Value: ip},
+		},
+	}
+}
+
+func newIPStackCreateDualStack(
+	stackV4 *IPConfigV4ResourceModel,
+	stackV6 *IPConfigV6ResourceModel,
+) oxide.PrivateIpStackCreateDualStackValue {
+	return oxide.PrivateIpStackCreateDualStackValue{
+		V4: newIPStackCreateV4(stackV4),
+		V6: newIPStackCreateV6(stackV6),
+	}
+}
+
+func createNICs(
+	ctx context.Context,
+	client *oxide.Client,
+	models []NICResourceModel,
+	instanceID string,
+) diag.Diagnostics {
+	for _, model := range models {
+		if _, diags := CreateNIC(ctx, client, model, instanceID); diags.HasError() {
+			return diags
+		}
+	}
+
+	return nil
+}
+
+// CreateNIC creates a network interface for the instance. The instance must
+// be stopped.
+func CreateNIC(
+	ctx context.Context,
+	client *oxide.Client,
+	model NICResourceModel,
+	instanceID string,
+) (*oxide.InstanceNetworkInterface, diag.Diagnostics) {
+	names, diags := retrieveVPCandSubnetNames(ctx, client, model.VPCID.ValueString(),
+		model.SubnetID.ValueString())
+	diags.Append(diags...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	params := oxide.InstanceNetworkInterfaceCreateParams{
+		Instance: oxide.NameOrId(instanceID),
+		Body: &oxide.InstanceNetworkInterfaceCreate{
+			Description: model.Description.ValueString(),
+			Name:        oxide.Name(model.Name.ValueString()),
+			SubnetName:  oxide.Name(names.subnet),
+			VpcName:     oxide.Name(names.vpc),
+			IpConfig:    newIPStackCreate(model),
 		},
 	}
-}
 
-func newIPStackCreateDualStack(
-	stackV4 *IPConfigV4ResourceModel,
-	stackV6 *IPConfigV6ResourceModel,
-) oxide.PrivateIpStackCreateDualStackValue {
-	return oxide.PrivateIpStackCreateDualStackValue{
-		V4: newIPStackCreateV4(stackV4),
-		V6: newIPStackCreateV6(stackV6),
+	nic, err := client.InstanceNetworkInterfaceCreate(ctx, params)
+	if err != nil {
+		diags.Append(shared.APIErrorDiagnostic(
+			"Error creating instance network interface",
+			err,
+		))
+		return nil, diags
 	}
+	tflog.Trace(ctx, fmt.Sprintf("created instance network interface with ID: %v", nic.Id),
+		map[string]any{"success": true})
+
+	return nic, nil
 }
 
-func createNICs(
+// updateNICTransitIPs sets the transit IPs of the instance's network
+// interfaces to the ones in models, matching network interfaces by name.
+// Network interfaces without transit IPs in models or in the API are skipped.
+func updateNICTransitIPs(
 	ctx context.Context,
 	client *oxide.Client,
-	models []NICResourceModel,
 	instanceID string,
+	models []NICResourceModel,
 ) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if len(models) == 0 {
+		return nil
+	}
+
+	nics, err := client.InstanceNetworkInterfaceListAllPages(
+		ctx,
+		oxide.InstanceNetworkInterfaceListParams{
+			Instance: oxide.NameOrId(instanceID),
+		},
+	)
+	if err != nil {
+		diags.Append(shared.APIErrorDiagnostic(
+			"Unable to read instance network interfaces:",
+			err,
+		))
+		return diags
+	}
+
+	nicsByName := make(map[string]oxide.InstanceNetworkInterface)
+	for _, nic := range nics {
+		nicsByName[string(nic.Name)] = nic
+	}
+
 	for _, model := range models {
-		names, diags := retrieveVPCandSubnetNames(ctx, client, model.VPCID.ValueString(),
-			model.SubnetID.ValueString())
-		diags.Append(diags...)
+		nic, ok := nicsByName[model.Name.ValueString()]
+		if !ok {
+			continue
+		}
+
+		transitIPs, diags := NewTransitIPsUpdate(ctx, model.TransitIPs)
 		if diags.HasError() {
 			return diags
 		}
+		if len(transitIPs) == 0 && len(NewTransitIPs(nic.IpStack)) == 0 {
+			continue
+		}
 
-		params := oxide.InstanceNetworkInterfaceCreateParams{
-			Instance: oxide.NameOrId(instanceID),
-			Body: &oxide.InstanceNetworkInterfaceCreate{
-				Description: model.Description.ValueString(),
-				Name:        oxide.Name(model.Name.ValueString()),
-				SubnetName:  oxide.Name(names.subnet),
-				VpcName:     oxide.Name(names.vpc),
-				IpConfig:    newIPStackCreate(model),
-			},
+		_, diags = UpdateNIC(ctx, client, nic.Id, oxide.InstanceNetworkInterfaceUpdate{
+			Name:        nic.Name,
+			Description: nic.Description,
+			TransitIps:  transitIPs,
+		})
+		if diags.HasError() {
+			return diags
 		}
+	}
+
+	return nil
+}
 
-		nic, err := client.InstanceNetworkInterfaceCreate(ctx, params)
+// UpdateNIC updates a network interface.
+func UpdateNIC(
+	ctx context.Context,
+	client *oxide.Client,
+	nicID string,
+	body oxide.InstanceNetworkInterfaceUpdate,
+) (*oxide.InstanceNetworkInterface, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	nic, err := client.InstanceNetworkInterfaceUpdate(
+		ctx,
+		oxide.InstanceNetworkInterfaceUpdateParams{
+			Interface: oxide.NameOrId(nicID),
+			Body:      &body,
+		},
+	)
+	if err != nil {
+		diags.Append(shared.APIErrorDiagnostic(
+			"Error updating instance network interface",
+			err,
+		))
+		return nil, diags
+	}
+	tflog.Trace(ctx, fmt.Sprintf("updated instance network interface with ID: %v", nic.Id),
+		map[string]any{"success": true})
+
+	return nic, nil
+}
+
+// NewTransitIPsUpdate parses the transit IPs of a network interface for the
+// API. Transit IPs are replaced as a whole, so the full set is always sent.
+func NewTransitIPsUpdate(
+	ctx context.Context,
+	set types.Set,
+) ([]oxide.IpNet, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var prefixes []cidrtypes.IPPrefix
+	diags.Append(set.ElementsAs(ctx, &prefixes, false)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	transitIPs := make([]oxide.IpNet, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		ipNet, err := oxide.NewIpNet(prefix.ValueString())
 		if err != nil {
 			diags.AddError(
-				"Error creating instance network interface",
-				"API error: "+err.Error(),
+				"Invalid transit IP",
+				fmt.Sprintf("Unable to parse %s: %v", prefix.ValueString(), err),
 			)
-			return diags
+			return nil, diags
 		}
-		tflog.Trace(ctx, fmt.Sprintf("created instance network interface with ID: %v", nic.Id),
-			map[string]any{"success": true})
+		transitIPs = append(transitIPs, ipNet)
 	}
 
-	return nil
+	return transitIPs, nil
+}
+
+// NewTransitIPs returns the transit IPs of all address families in the IP
+// stack of a network interface.
+func NewTransitIPs(stack oxide.PrivateIpStack) []string {
+	transitIPs := []string{}
+
+	switch s := stack.Value.(type) {
+	case *oxide.PrivateIpStackV4:
+		for _, ip := range s.Value.TransitIps {
+			transitIPs = append(transitIPs, string(ip))
+		}
+	case *oxide.PrivateIpStackV6:
+		for _, ip := range s.Value.TransitIps {
+			transitIPs = append(transitIPs, string(ip))
+		}
+	case *oxide.PrivateIpStackDualStack:
+		for _, ip := range s.Value.V4.TransitIps {
+			transitIPs = append(transitIPs, string(ip))
+		}
+		for _, ip := range s.Value.V6.TransitIps {
+			transitIPs = append(transitIPs, string(ip))
+		}
+	}
+
+	return transitIPs
 }
 
-func deleteNICs(
+// DeleteNICs deletes network interfaces of an instance. The instance must be
+// stopped.
+func DeleteNICs(
 	ctx context.Context,
 	client *oxide.Client,
 	models []AttachedNICResourceModel,
@@ -2169,10 +3301,10 @@ func deleteNICs(
 		}
 		if err := client.InstanceNetworkInterfaceDelete(ctx, params); err != nil {
 			if !shared.Is404(err) {
-				diags.AddError(
+				diags.Append(shared.APIErrorDiagnostic(
 					"Error deleting instance network interface:",
-					"API error: "+err.Error(),
-				)
+					err,
+				))
 				// TODO: Should this be a return or a continue?
 				return diags
 			}
@@ -2252,10 +3384,10 @@ func attachEphemeralIPs(
 		}
 
 		if _, err := client.InstanceEphemeralIpAttach(ctx, params); err != nil {
-			diags.AddError(
+			diags.Append(shared.APIErrorDiagnostic(
 				fmt.Sprintf("Error attaching ephemeral external IP to instance %s", instanceID),
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			continue
 		}
 
@@ -2301,10 +3433,10 @@ func attachFloatingIPs(
 		}
 
 		if _, err := client.FloatingIpAttach(ctx, params); err != nil {
-			diags.AddError(
+			diags.Append(shared.APIErrorDiagnostic(
 				fmt.Sprintf("Error attaching floating external IP with ID %s", ip.ID.ValueString()),
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 
 			return diags
 		}
@@ -2368,14 +3500,14 @@ func detachEphemeralIPs(
 		}
 
 		if err := client.InstanceEphemeralIpDetach(ctx, params); err != nil {
-			diags.AddError(
+			diags.Append(shared.APIErrorDiagnostic(
 				fmt.Sprintf(
 					"Error detaching ephemeral external IP%s from instance %s",
 					ip.IPVersion.ValueString(),
 					instanceID,
 				),
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			continue
 		}
 
@@ -2420,10 +3552,10 @@ func detachFloatingIPs(
 		}
 
 		if _, err := client.FloatingIpDetach(ctx, params); err != nil {
-			diags.AddError(
+			diags.Append(shared.APIErrorDiagnostic(
 				fmt.Sprintf("Error detaching floating external IP with ID %s", ip.ID.ValueString()),
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			continue
 		}
 
@@ -2440,7 +3572,8 @@ func detachFloatingIPs(
 	return diags
 }
 
-func attachDisks(
+// AttachDisks attaches disks to an instance. The instance must be stopped.
+func AttachDisks(
 	ctx context.Context,
 	client *oxide.Client,
 	disks []attr.Value,
@@ -2466,10 +3599,10 @@ func attachDisks(
 		}
 		_, err = client.InstanceDiskAttach(ctx, params)
 		if err != nil {
-			diags.AddError(
+			diags.Append(shared.APIErrorDiagnostic(
 				"Error attaching disk",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			// TODO: Should this return here or should I continue trying to attach the other disks?
 			return diags
 		}
@@ -2483,7 +3616,8 @@ func attachDisks(
 	return nil
 }
 
-func detachDisks(
+// DetachDisks detaches disks from an instance. The instance must be stopped.
+func DetachDisks(
 	ctx context.Context,
 	client *oxide.Client,
 	disks []attr.Value,
@@ -2509,10 +3643,10 @@ func detachDisks(
 		}
 		_, err = client.InstanceDiskDetach(ctx, params)
 		if err != nil {
-			diags.AddError(
+			diags.Append(shared.APIErrorDiagnostic(
 				"Error detaching disk",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			// TODO: Should this return here or should I continue trying to detach the other disks?
 			return diags
 		}
@@ -2526,7 +3660,53 @@ func detachDisks(
 	return nil
 }
 
-func addAntiAffinityGroups(
+func addAffinityGroups(
+	ctx context.Context,
+	client *oxide.Client,
+	groups []attr.Value,
+	instanceID string,
+) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, v := range groups {
+		id, err := strconv.Unquote(v.String())
+		if err != nil {
+			diags.AddError(
+				"Error adding affinity group to instance",
+				"affinity group ID parse error: "+err.Error(),
+			)
+			return diags
+		}
+
+		params := oxide.AffinityGroupMemberInstanceAddParams{
+			Instance:      oxide.NameOrId(instanceID),
+			AffinityGroup: oxide.NameOrId(id),
+		}
+		_, err = client.AffinityGroupMemberInstanceAdd(ctx, params)
+		if err != nil {
+			diags.Append(shared.APIErrorDiagnostic(
+				"Error adding affinity group to instance",
+				err,
+			))
+			return diags
+		}
+		tflog.Trace(
+			ctx,
+			fmt.Sprintf(
+				"added affinity group with ID: %v to instance with ID: %v",
+				id,
+				instanceID,
+			),
+			map[string]any{"success": true},
+		)
+	}
+
+	return nil
+}
+
+// AddAntiAffinityGroups adds an instance to anti-affinity groups. The instance
+// must be stopped.
+func AddAntiAffinityGroups(
 	ctx context.Context,
 	client *oxide.Client,
 	groups []attr.Value,
@@ -2550,10 +3730,10 @@ func addAntiAffinityGroups(
 		}
 		_, err = client.AntiAffinityGroupMemberInstanceAdd(ctx, params)
 		if err != nil {
-			diags.AddError(
+			diags.Append(shared.APIErrorDiagnostic(
 				"Error adding anti-affinity group to instance",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return diags
 		}
 		tflog.Trace(
@@ -2570,7 +3750,58 @@ func addAntiAffinityGroups(
 	return nil
 }
 
-func removeAntiAffinityGroups(
+func removeAffinityGroups(
+	ctx context.Context,
+	client *oxide.Client,
+	groups []attr.Value,
+	instanceID string,
+) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, v := range groups {
+		id, err := strconv.Unquote(v.String())
+		if err != nil {
+			diags.AddError(
+				"Error removing affinity group from instance",
+				"affinity group ID parse error: "+err.Error(),
+			)
+			return diags
+		}
+
+		params := oxide.AffinityGroupMemberInstanceDeleteParams{
+			Instance:      oxide.NameOrId(instanceID),
+			AffinityGroup: oxide.NameOrId(id),
+		}
+		err = client.AffinityGroupMemberInstanceDelete(ctx, params)
+		if err != nil {
+			// If the affinity group doesn't exist anymore, it means
+			// the instance isn't part of it. Move on to the next group.
+			if shared.Is404(err) {
+				continue
+			}
+			diags.Append(shared.APIErrorDiagnostic(
+				"Error removing affinity group from instance",
+				err,
+			))
+			return diags
+		}
+		tflog.Trace(
+			ctx,
+			fmt.Sprintf(
+				"removed affinity group with ID %v to instance with ID %v",
+				id,
+				instanceID,
+			),
+			map[string]any{"success": true},
+		)
+	}
+
+	return nil
+}
+
+// RemoveAntiAffinityGroups removes an instance from anti-affinity groups. The
+// instance must be stopped.
+func RemoveAntiAffinityGroups(
 	ctx context.Context,
 	client *oxide.Client,
 	groups []attr.Value,
@@ -2595,14 +3826,14 @@ func removeAntiAffinityGroups(
 		err = client.AntiAffinityGroupMemberInstanceDelete(ctx, params)
 		if err != nil {
 			// If the anti-affinity group doesn't exist anymore, it means
-			// the instance isn't part of it. We can just return.
+			// the instance isn't part of it. Move on to the next group.
 			if shared.Is404(err) {
-				return nil
+				continue
 			}
-			diags.AddError(
+			diags.Append(shared.APIErrorDiagnostic(
 				"Error removing anti-affinity group from instance",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return diags
 		}
 		tflog.Trace(
@@ -2678,6 +3909,12 @@ func (v ipConfigValidator) ValidateString(
 	}
 }
 
+// IPConfigValidator returns a validator that checks that a string is an
+// address of the given IP version or auto.
+func IPConfigValidator(ipVersion oxide.IpVersion) validator.String {
+	return ipConfigValidator{ipVersion}
+}
+
 // instanceIPConfigValidator is a custom validator that validates the ip_config
 // attribute of network_interfaces.
 type instanceIPConfigValidator struct{}
@@ -2721,6 +3958,137 @@ func (f instanceIPConfigValidator) ValidateObject(
 	}
 }
 
+// NetworkInterfaceIPConfigValidator returns a validator that checks that the
+// ip_config of a network interface configures at least one IP version.
+func NetworkInterfaceIPConfigValidator() validator.Object {
+	return instanceIPConfigValidator{}
+}
+
+// bootDiskValidator validates that the image source of boot_disk matches its
+// disk type. Local disks can't be created from an image, while distributed
+// boot disks need one.
+type bootDiskValidator struct{}
+
+var _ validator.Object = bootDiskValidator{}
+
+func (v bootDiskValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+func (v bootDiskValidator) MarkdownDescription(_ context.Context) string {
+	return "source_image_id must be set if, and only if, disk_type is distributed"
+}
+
+func (v bootDiskValidator) ValidateObject(
+	ctx context.Context,
+	req validator.ObjectRequest,
+	resp *validator.ObjectResponse,
+) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	var bootDisk *BootDiskResourceModel
+	diags := req.ConfigValue.As(ctx, &bootDisk, basetypes.ObjectAsOptions{
+		UnhandledNullAsEmpty:    true,
+		UnhandledUnknownAsEmpty: true,
+	})
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if bootDisk.DiskType.IsUnknown() || bootDisk.SourceImageID.IsUnknown() {
+		return
+	}
+
+	// disk_type defaults to distributed when it's not configured.
+	local := bootDisk.DiskType.ValueString() == string(oxide.DiskBackendTypeLocal)
+	switch {
+	case local && !bootDisk.SourceImageID.IsNull():
+		resp.Diagnostics.AddAttributeError(
+			req.Path.AtName("source_image_id"),
+			"Invalid boot disk configuration",
+			`"source_image_id" cannot be set when disk_type is "local".`,
+		)
+	case !local && bootDisk.SourceImageID.IsNull():
+		resp.Diagnostics.AddAttributeError(
+			req.Path.AtName("source_image_id"),
+			"Invalid boot disk configuration",
+			`"source_image_id" must be set when disk_type is "distributed".`,
+		)
+	}
+}
+
+// transitIPsValidator validates that each transit IP of a network interface
+// matches an IP version set in the ip_config attribute next to it.
+type transitIPsValidator struct{}
+
+var _ validator.Set = transitIPsValidator{}
+
+func (v transitIPsValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+func (v transitIPsValidator) MarkdownDescription(_ context.Context) string {
+	return "each transit IP must match an IP version configured in `ip_config`."
+}
+
+func (v transitIPsValidator) ValidateSet(
+	ctx context.Context,
+	req validator.SetRequest,
+	resp *validator.SetResponse,
+) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	var ipConfig types.Object
+	ipConfigPath := req.Path.ParentPath().AtName("ip_config")
+	diags := req.Config.GetAttribute(ctx, ipConfigPath, &ipConfig)
+	if diags.HasError() || ipConfig.IsNull() || ipConfig.IsUnknown() {
+		return
+	}
+	hasV4 := !ipConfig.Attributes()["v4"].IsNull()
+	hasV6 := !ipConfig.Attributes()["v6"].IsNull()
+
+	var prefixes []cidrtypes.IPPrefix
+	resp.Diagnostics.Append(req.ConfigValue.ElementsAs(ctx, &prefixes, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, prefix := range prefixes {
+		if prefix.IsNull() || prefix.IsUnknown() {
+			continue
+		}
+
+		// Invalid prefixes are reported by the attribute type.
+		p, err := netip.ParsePrefix(prefix.ValueString())
+		if err != nil {
+			continue
+		}
+
+		if (p.Addr().Is4() && !hasV4) || (p.Addr().Is6() && !hasV6) {
+			resp.Diagnostics.AddAttributeError(
+				req.Path,
+				"Invalid transit IP",
+				fmt.Sprintf(
+					"Transit IP %s doesn't match an IP version configured in %s.",
+					prefix.ValueString(),
+					ipConfigPath,
+				),
+			)
+		}
+	}
+}
+
+// TransitIPsValidator returns a validator that checks that the transit IPs of a
+// network interface match the IP versions configured in its ip_config.
+func TransitIPsValidator() validator.Set {
+	return transitIPsValidator{}
+}
+
 // Ensure the concrete validator satisfies the [validator.Set] interface.
 var _ validator.Object = instanceExternalIPValidator{}
 
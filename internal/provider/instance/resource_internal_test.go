@@ -0,0 +1,85 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package instance
+
+import (
+	"testing"
+
+	"github.com/oxidecomputer/oxide.go/oxide"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstanceUpdateTransitions(t *testing.T) {
+	tests := []struct {
+		name         string
+		runState     oxide.InstanceState
+		desiredState string
+		needsStop    bool
+		stop         bool
+		start        bool
+	}{
+		{
+			name:     "running without changes that need a stop",
+			runState: oxide.InstanceStateRunning,
+		},
+		{
+			name:      "running with changes that need a stop",
+			runState:  oxide.InstanceStateRunning,
+			needsStop: true,
+			stop:      true,
+			start:     true,
+		},
+		{
+			name:         "running to stopped",
+			runState:     oxide.InstanceStateRunning,
+			desiredState: "stopped",
+			needsStop:    true,
+			stop:         true,
+		},
+		{
+			name:      "stopped with changes that need a stop",
+			runState:  oxide.InstanceStateStopped,
+			needsStop: true,
+		},
+		{
+			name:         "stopped to running",
+			runState:     oxide.InstanceStateStopped,
+			desiredState: "running",
+			start:        true,
+		},
+		{
+			name:         "failed to running",
+			runState:     oxide.InstanceStateFailed,
+			desiredState: "running",
+			start:        true,
+		},
+		{
+			name:         "failed to running with changes that need a stop",
+			runState:     oxide.InstanceStateFailed,
+			desiredState: "running",
+			needsStop:    true,
+			start:        true,
+		},
+		{
+			name:         "failed to stopped",
+			runState:     oxide.InstanceStateFailed,
+			desiredState: "stopped",
+			needsStop:    true,
+			stop:         true,
+		},
+		{
+			name:      "failed without desired state",
+			runState:  oxide.InstanceStateFailed,
+			needsStop: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stop, start := instanceUpdateTransitions(tt.runState, tt.desiredState, tt.needsStop)
+			require.Equal(t, tt.stop, stop, "stop")
+			require.Equal(t, tt.start, start, "start")
+		})
+	}
+}
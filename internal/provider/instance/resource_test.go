@@ -10,10 +10,12 @@ import (
 	"math/rand/v2"
 	"os"
 	"reflect"
+	"regexp"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/oxidecomputer/oxide.go/oxide"
 
@@ -219,6 +221,13 @@ resource "oxide_instance" "{{.BlockName}}" {
 				ImportStateVerify:       true,
 				ImportStateVerifyIgnore: []string{"start_on_create"},
 			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateId:           "tf-acc-test/" + instanceName,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"start_on_create"},
+			},
 			{
 				Config: config2,
 				Check:  checkResourceFull(resourceName2, instanceName2, instanceNicName),
@@ -1499,6 +1508,600 @@ resource "oxide_instance" "{{.BlockName}}" {
 	})
 }
 
+func TestAccCloudResourceInstance_affinityGroups(t *testing.T) {
+	type resourceInstanceAffinityGroupsConfig struct {
+		BlockName          string
+		InstanceName       string
+		AffinityGroupName  string
+		AffinityGroupName2 string
+		SupportBlockName   string
+		AffinityGroups     string
+	}
+
+	resourceInstanceAffinityGroupsConfigTpl := `
+data "oxide_project" "{{.SupportBlockName}}" {
+  name = "tf-acc-test"
+}
+
+resource "oxide_affinity_group" "one" {
+  project_id  = data.oxide_project.{{.SupportBlockName}}.id
+  description = "a test affinity group"
+  name        = "{{.AffinityGroupName}}"
+  policy      = "allow"
+}
+
+resource "oxide_affinity_group" "two" {
+  project_id  = data.oxide_project.{{.SupportBlockName}}.id
+  description = "a test affinity group"
+  name        = "{{.AffinityGroupName2}}"
+  policy      = "allow"
+}
+
+resource "oxide_instance" "{{.BlockName}}" {
+  affinity_groups = [{{.AffinityGroups}}]
+  project_id      = data.oxide_project.{{.SupportBlockName}}.id
+  description     = "a test instance"
+  name            = "{{.InstanceName}}"
+  hostname        = "terraform-acc-myhost"
+  memory          = 1073741824
+  ncpus           = 1
+  start_on_create = false
+}
+`
+
+	blockName := sharedtest.NewBlockName("instance-affinity-groups")
+	resourceName := fmt.Sprintf("oxide_instance.%s", blockName)
+	cfg := resourceInstanceAffinityGroupsConfig{
+		BlockName:          blockName,
+		InstanceName:       sharedtest.NewResourceName(),
+		AffinityGroupName:  sharedtest.NewResourceName(),
+		AffinityGroupName2: sharedtest.NewResourceName(),
+		SupportBlockName:   sharedtest.NewBlockName("support-instance-affinity-groups"),
+		AffinityGroups:     "oxide_affinity_group.one.id",
+	}
+
+	cfgUpdate := cfg
+	cfgUpdate.AffinityGroups = "oxide_affinity_group.one.id, oxide_affinity_group.two.id"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { sharedtest.PreCheck(t) },
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		CheckDestroy:             testAccResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: sharedtest.ParsedAccConfig(t, cfg, resourceInstanceAffinityGroupsConfigTpl),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "affinity_groups.#", "1"),
+					resource.TestCheckTypeSetElemAttrPair(
+						resourceName, "affinity_groups.*", "oxide_affinity_group.one", "id",
+					),
+				),
+			},
+			// Add another affinity group
+			{
+				Config: sharedtest.ParsedAccConfig(
+					t, cfgUpdate, resourceInstanceAffinityGroupsConfigTpl,
+				),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "affinity_groups.#", "2"),
+					resource.TestCheckTypeSetElemAttrPair(
+						resourceName, "affinity_groups.*", "oxide_affinity_group.two", "id",
+					),
+				),
+			},
+			// Remove an affinity group
+			{
+				Config: sharedtest.ParsedAccConfig(t, cfg, resourceInstanceAffinityGroupsConfigTpl),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "affinity_groups.#", "1"),
+					resource.TestCheckTypeSetElemAttrPair(
+						resourceName, "affinity_groups.*", "oxide_affinity_group.one", "id",
+					),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				// start-on-create cannot be imported as it is only present at create time
+				ImportStateVerifyIgnore: []string{"start_on_create"},
+			},
+		},
+	})
+}
+
+func TestAccCloudResourceInstance_desiredState(t *testing.T) {
+	type resourceInstanceDesiredStateConfig struct {
+		BlockName        string
+		InstanceName     string
+		SupportBlockName string
+		DesiredState     string
+	}
+
+	resourceInstanceDesiredStateConfigTpl := `
+data "oxide_project" "{{.SupportBlockName}}" {
+  name = "tf-acc-test"
+}
+
+resource "oxide_instance" "{{.BlockName}}" {
+  project_id    = data.oxide_project.{{.SupportBlockName}}.id
+  description   = "a test instance"
+  name          = "{{.InstanceName}}"
+  hostname      = "terraform-acc-myhost"
+  memory        = 1073741824
+  ncpus         = 1
+  desired_state = "{{.DesiredState}}"
+}
+`
+
+	instanceName := sharedtest.NewResourceName()
+	blockName := sharedtest.NewBlockName("instance-desired-state")
+	supportBlockName := sharedtest.NewBlockName("support")
+	resourceName := fmt.Sprintf("oxide_instance.%s", blockName)
+	newConfig := func(desiredState string) string {
+		return sharedtest.ParsedAccConfig(t,
+			resourceInstanceDesiredStateConfig{
+				BlockName:        blockName,
+				InstanceName:     instanceName,
+				SupportBlockName: supportBlockName,
+				DesiredState:     desiredState,
+			},
+			resourceInstanceDesiredStateConfigTpl,
+		)
+	}
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { sharedtest.PreCheck(t) },
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		CheckDestroy:             testAccResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: newConfig("running"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "desired_state", "running"),
+					sharedtest.CheckInstanceRunState(resourceName, oxide.InstanceStateRunning),
+				),
+			},
+			{
+				Config: newConfig("stopped"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "desired_state", "stopped"),
+					sharedtest.CheckInstanceRunState(resourceName, oxide.InstanceStateStopped),
+				),
+			},
+			// Start the instance outside of Terraform and verify the drift is
+			// planned back to the desired state.
+			{
+				PreConfig: func() {
+					client, err := sharedtest.NewTestClient()
+					if err != nil {
+						t.Fatal(err)
+					}
+					_, err = client.InstanceStart(context.Background(), oxide.InstanceStartParams{
+						Project:  oxide.NameOrId("tf-acc-test"),
+						Instance: oxide.NameOrId(instanceName),
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: newConfig("stopped"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "desired_state", "stopped"),
+					sharedtest.CheckInstanceRunState(resourceName, oxide.InstanceStateStopped),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudResourceInstance_liveUpdate(t *testing.T) {
+	type resourceInstanceLiveUpdateConfig struct {
+		BlockName         string
+		InstanceName      string
+		SupportBlockName  string
+		AutoRestartPolicy string
+	}
+
+	resourceInstanceLiveUpdateConfigTpl := `
+data "oxide_project" "{{.SupportBlockName}}" {
+  name = "tf-acc-test"
+}
+
+resource "oxide_instance" "{{.BlockName}}" {
+  project_id          = data.oxide_project.{{.SupportBlockName}}.id
+  description         = "a test instance"
+  name                = "{{.InstanceName}}"
+  hostname            = "terraform-acc-myhost"
+  memory              = 1073741824
+  ncpus               = 1
+  desired_state       = "running"
+  auto_restart_policy = "{{.AutoRestartPolicy}}"
+}
+`
+
+	instanceName := sharedtest.NewResourceName()
+	blockName := sharedtest.NewBlockName("instance-live-update")
+	supportBlockName := sharedtest.NewBlockName("support")
+	resourceName := fmt.Sprintf("oxide_instance.%s", blockName)
+	newConfig := func(autoRestartPolicy string) string {
+		return sharedtest.ParsedAccConfig(t,
+			resourceInstanceLiveUpdateConfig{
+				BlockName:         blockName,
+				InstanceName:      instanceName,
+				SupportBlockName:  supportBlockName,
+				AutoRestartPolicy: autoRestartPolicy,
+			},
+			resourceInstanceLiveUpdateConfigTpl,
+		)
+	}
+
+	// The time the run state last changed must be the same after an update
+	// that doesn't require the instance to be stopped.
+	var timeRunStateUpdated time.Time
+	captureTimeRunStateUpdated := func(s *terraform.State) error {
+		instance, err := testAccInstanceView(s, resourceName)
+		if err != nil {
+			return err
+		}
+		timeRunStateUpdated = *instance.TimeRunStateUpdated
+		return nil
+	}
+	checkTimeRunStateUpdated := func(s *terraform.State) error {
+		instance, err := testAccInstanceView(s, resourceName)
+		if err != nil {
+			return err
+		}
+		if !instance.TimeRunStateUpdated.Equal(timeRunStateUpdated) {
+			return fmt.Errorf("instance was restarted during update")
+		}
+		return nil
+	}
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { sharedtest.PreCheck(t) },
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		CheckDestroy:             testAccResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: newConfig("best_effort"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						resourceName, "auto_restart_policy", "best_effort",
+					),
+					sharedtest.CheckInstanceRunState(resourceName, oxide.InstanceStateRunning),
+					captureTimeRunStateUpdated,
+				),
+			},
+			{
+				Config: newConfig("never"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "auto_restart_policy", "never"),
+					sharedtest.CheckInstanceRunState(resourceName, oxide.InstanceStateRunning),
+					checkTimeRunStateUpdated,
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudResourceInstance_transitIPs(t *testing.T) {
+	type resourceInstanceTransitIPsConfig struct {
+		BlockName        string
+		InstanceName     string
+		SupportBlockName string
+		TransitIPs       []string
+	}
+
+	resourceInstanceTransitIPsConfigTpl := `
+data "oxide_project" "{{.SupportBlockName}}" {
+  name = "tf-acc-test"
+}
+
+data "oxide_vpc_subnet" "{{.SupportBlockName}}" {
+  project_name = data.oxide_project.{{.SupportBlockName}}.name
+  vpc_name     = "default"
+  name         = "default"
+}
+
+resource "oxide_instance" "{{.BlockName}}" {
+  project_id    = data.oxide_project.{{.SupportBlockName}}.id
+  description   = "a test instance"
+  name          = "{{.InstanceName}}"
+  hostname      = "terraform-acc-myhost"
+  memory        = 1073741824
+  ncpus         = 1
+  desired_state = "running"
+  network_interfaces = [
+    {
+      subnet_id   = data.oxide_vpc_subnet.{{.SupportBlockName}}.id
+      vpc_id      = data.oxide_vpc_subnet.{{.SupportBlockName}}.vpc_id
+      description = "a router nic"
+      name        = "net0"
+      ip_config = {
+        v4 = {
+          ip = "auto"
+        }
+      }
+      transit_ips = [{{range $i, $ip := .TransitIPs}}{{if $i}}, {{end}}"{{$ip}}"{{end}}]
+    },
+  ]
+}
+`
+
+	instanceName := sharedtest.NewResourceName()
+	blockName := sharedtest.NewBlockName("instance-transit-ips")
+	supportBlockName := sharedtest.NewBlockName("support")
+	resourceName := fmt.Sprintf("oxide_instance.%s", blockName)
+	newConfig := func(transitIPs ...string) string {
+		return sharedtest.ParsedAccConfig(t,
+			resourceInstanceTransitIPsConfig{
+				BlockName:        blockName,
+				InstanceName:     instanceName,
+				SupportBlockName: supportBlockName,
+				TransitIPs:       transitIPs,
+			},
+			resourceInstanceTransitIPsConfigTpl,
+		)
+	}
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { sharedtest.PreCheck(t) },
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		CheckDestroy:             testAccResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				// The network interface has no IPv6 configuration.
+				Config:      newConfig("fd00::/64"),
+				ExpectError: regexp.MustCompile(`Invalid transit IP`),
+			},
+			{
+				Config: newConfig("192.168.0.0/24"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						resourceName, "network_interfaces.0.transit_ips.#", "1",
+					),
+					resource.TestCheckTypeSetElemAttr(
+						resourceName, "network_interfaces.0.transit_ips.*", "192.168.0.0/24",
+					),
+					resource.TestCheckTypeSetElemAttr(
+						resourceName,
+						"attached_network_interfaces.net0.transit_ips.*",
+						"192.168.0.0/24",
+					),
+				),
+			},
+			{
+				Config: newConfig("192.168.0.0/24", "10.10.0.0/16"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(
+							resourceName,
+							plancheck.ResourceActionUpdate,
+						),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						resourceName, "network_interfaces.0.transit_ips.#", "2",
+					),
+					resource.TestCheckTypeSetElemAttr(
+						resourceName, "network_interfaces.0.transit_ips.*", "10.10.0.0/16",
+					),
+					resource.TestCheckResourceAttr(
+						resourceName, "attached_network_interfaces.net0.transit_ips.#", "2",
+					),
+					sharedtest.CheckInstanceRunState(resourceName, oxide.InstanceStateRunning),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudResourceInstance_bootDisk(t *testing.T) {
+	type resourceInstanceBootDiskConfig struct {
+		BlockName        string
+		InstanceName     string
+		DiskName         string
+		SupportBlockName string
+		Memory           int64
+	}
+
+	resourceInstanceBootDiskConfigTpl := `
+data "oxide_project" "{{.SupportBlockName}}" {
+  name = "tf-acc-test"
+}
+
+data "oxide_image" "{{.SupportBlockName}}" {
+  name = "alpine-project"
+}
+
+resource "oxide_instance" "{{.BlockName}}" {
+  project_id    = data.oxide_project.{{.SupportBlockName}}.id
+  description   = "a test instance"
+  name          = "{{.InstanceName}}"
+  hostname      = "terraform-acc-myhost"
+  memory        = {{.Memory}}
+  ncpus         = 1
+  desired_state = "running"
+  boot_disk = {
+    name            = "{{.DiskName}}"
+    source_image_id = data.oxide_image.{{.SupportBlockName}}.id
+    size            = 1073741824
+  }
+}
+`
+
+	blockName := sharedtest.NewBlockName("instance-boot-disk")
+	supportBlockName := sharedtest.NewBlockName("support")
+	resourceName := fmt.Sprintf("oxide_instance.%s", blockName)
+	cfg := resourceInstanceBootDiskConfig{
+		BlockName:        blockName,
+		InstanceName:     sharedtest.NewResourceName(),
+		DiskName:         sharedtest.NewResourceName(),
+		SupportBlockName: supportBlockName,
+		Memory:           1073741824,
+	}
+
+	// Resizing the instance must keep the boot disk.
+	cfgUpdate := cfg
+	cfgUpdate.Memory = 2147483648
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { sharedtest.PreCheck(t) },
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		CheckDestroy: resource.ComposeAggregateTestCheckFunc(
+			testAccResourceDestroy,
+			testAccBootDiskDestroy,
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: sharedtest.ParsedAccConfig(t, cfg, resourceInstanceBootDiskConfigTpl),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "boot_disk.id"),
+					resource.TestCheckResourceAttr(resourceName, "boot_disk.name", cfg.DiskName),
+					resource.TestCheckResourceAttr(resourceName, "boot_disk.disk_type", "distributed"),
+					resource.TestCheckResourceAttr(resourceName, "boot_disk.keep_on_destroy", "false"),
+					resource.TestCheckNoResourceAttr(resourceName, "boot_disk_id"),
+					resource.TestCheckNoResourceAttr(resourceName, "disk_attachments"),
+					sharedtest.CheckInstanceRunState(resourceName, oxide.InstanceStateRunning),
+				),
+			},
+			{
+				// The boot disk must not show up in disk_attachments.
+				Config:   sharedtest.ParsedAccConfig(t, cfg, resourceInstanceBootDiskConfigTpl),
+				PlanOnly: true,
+			},
+			{
+				Config: sharedtest.ParsedAccConfig(t, cfgUpdate, resourceInstanceBootDiskConfigTpl),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(
+							resourceName,
+							plancheck.ResourceActionUpdate,
+						),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "memory", "2147483648"),
+					resource.TestCheckResourceAttrSet(resourceName, "boot_disk.id"),
+					resource.TestCheckNoResourceAttr(resourceName, "boot_disk_id"),
+					resource.TestCheckNoResourceAttr(resourceName, "disk_attachments"),
+					func(s *terraform.State) error {
+						instance, err := testAccInstanceView(s, resourceName)
+						if err != nil {
+							return err
+						}
+						want := s.RootModule().Resources[resourceName].Primary.Attributes["boot_disk.id"]
+						if instance.BootDiskId != want {
+							return fmt.Errorf(
+								"expected boot disk %s, got %s", want, instance.BootDiskId,
+							)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudResourceInstance_waitFor(t *testing.T) {
+	type resourceInstanceWaitForConfig struct {
+		BlockName        string
+		InstanceName     string
+		DiskName         string
+		SupportBlockName string
+		DesiredState     string
+		Regex            string
+		CreateTimeout    string
+	}
+
+	resourceInstanceWaitForConfigTpl := `
+data "oxide_project" "{{.SupportBlockName}}" {
+  name = "tf-acc-test"
+}
+
+data "oxide_image" "{{.SupportBlockName}}" {
+  name = "alpine-project"
+}
+
+resource "oxide_instance" "{{.BlockName}}" {
+  project_id    = data.oxide_project.{{.SupportBlockName}}.id
+  description   = "a test instance"
+  name          = "{{.InstanceName}}"
+  hostname      = "terraform-acc-myhost"
+  memory        = 1073741824
+  ncpus         = 1
+  desired_state = "{{.DesiredState}}"
+  boot_disk = {
+    name            = "{{.DiskName}}"
+    source_image_id = data.oxide_image.{{.SupportBlockName}}.id
+    size            = 1073741824
+  }
+  wait_for = {
+    serial_console_regex = "{{.Regex}}"
+  }
+  timeouts = {
+    create = "{{.CreateTimeout}}"
+  }
+}
+`
+
+	blockName := sharedtest.NewBlockName("instance-wait-for")
+	resourceName := fmt.Sprintf("oxide_instance.%s", blockName)
+	cfg := resourceInstanceWaitForConfig{
+		BlockName:        blockName,
+		InstanceName:     sharedtest.NewResourceName(),
+		DiskName:         sharedtest.NewResourceName(),
+		SupportBlockName: sharedtest.NewBlockName("support"),
+		DesiredState:     "running",
+		Regex:            "login:",
+		CreateTimeout:    "10m",
+	}
+
+	// A stopped guest never becomes ready.
+	cfgStopped := cfg
+	cfgStopped.DesiredState = "stopped"
+
+	// The guest never prints this, so the wait times out.
+	cfgNeverReady := cfg
+	cfgNeverReady.Regex = "this is never printed"
+	cfgNeverReady.CreateTimeout = "2m"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { sharedtest.PreCheck(t) },
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		CheckDestroy: resource.ComposeAggregateTestCheckFunc(
+			testAccResourceDestroy,
+			testAccBootDiskDestroy,
+		),
+		Steps: []resource.TestStep{
+			{
+				Config:      sharedtest.ParsedAccConfig(t, cfgStopped, resourceInstanceWaitForConfigTpl),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`"wait_for" cannot be set`),
+			},
+			{
+				Config: sharedtest.ParsedAccConfig(
+					t, cfgNeverReady, resourceInstanceWaitForConfigTpl,
+				),
+				ExpectError: regexp.MustCompile(`Most recent serial console output`),
+			},
+			{
+				// The tainted instance is replaced.
+				Config: sharedtest.ParsedAccConfig(t, cfg, resourceInstanceWaitForConfigTpl),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						resourceName, "wait_for.serial_console_regex", "login:",
+					),
+					sharedtest.CheckInstanceRunState(resourceName, oxide.InstanceStateRunning),
+				),
+			},
+		},
+	})
+}
+
 func checkResource(resourceName, instanceName string) resource.TestCheckFunc {
 	return resource.ComposeAggregateTestCheckFunc([]resource.TestCheckFunc{
 		resource.TestCheckResourceAttrSet(resourceName, "id"),
@@ -1947,6 +2550,24 @@ func testResourceNetworkInterface(
 	return resource.ComposeAggregateTestCheckFunc(funcs...)
 }
 
+// testAccInstanceView reads the instance managed by resourceName from the
+// Oxide API.
+func testAccInstanceView(s *terraform.State, resourceName string) (*oxide.Instance, error) {
+	rs, ok := s.RootModule().Resources[resourceName]
+	if !ok {
+		return nil, fmt.Errorf("resource not found: %s", resourceName)
+	}
+
+	client, err := sharedtest.NewTestClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.InstanceView(context.Background(), oxide.InstanceViewParams{
+		Instance: oxide.NameOrId(rs.Primary.ID),
+	})
+}
+
 func testAccResourceDestroy(s *terraform.State) error {
 	client, err := sharedtest.NewTestClient()
 	if err != nil {
@@ -1978,6 +2599,38 @@ func testAccResourceDestroy(s *terraform.State) error {
 	return nil
 }
 
+func testAccBootDiskDestroy(s *terraform.State) error {
+	client, err := sharedtest.NewTestClient()
+	if err != nil {
+		return err
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "oxide_instance" || rs.Primary.Attributes["boot_disk.id"] == "" {
+			continue
+		}
+		if rs.Primary.Attributes["boot_disk.keep_on_destroy"] == "true" {
+			continue
+		}
+
+		ctx := context.Background()
+		ctx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+
+		params := oxide.DiskViewParams{
+			Disk: oxide.NameOrId(rs.Primary.Attributes["boot_disk.id"]),
+		}
+		res, err := client.DiskView(ctx, params)
+		if err != nil && shared.Is404(err) {
+			continue
+		}
+
+		return fmt.Errorf("boot disk (%v) still exists", &res.Name)
+	}
+
+	return nil
+}
+
 func TestFilterBootDiskFromDisks(t *testing.T) {
 	bootDisk := oxide.InstanceDiskAttachment{
 		Value: &oxide.InstanceDiskAttachmentAttach{Name: "testboot01"},
@@ -0,0 +1,347 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package instancediskattachment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/oxidecomputer/oxide.go/oxide"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/instance"
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/shared"
+	oxidevalidator "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/validator"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = (*Resource)(nil)
+	_ resource.ResourceWithConfigure   = (*Resource)(nil)
+	_ resource.ResourceWithImportState = (*Resource)(nil)
+)
+
+// NewResource is a helper function to simplify the provider
+// implementation.
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+// Resource is the resource implementation.
+type Resource struct {
+	client *oxide.Client
+}
+
+type ResourceModel struct {
+	ID         types.String   `tfsdk:"id"`
+	InstanceID types.String   `tfsdk:"instance_id"`
+	DiskID     types.String   `tfsdk:"disk_id"`
+	Timeouts   timeouts.Value `tfsdk:"timeouts"`
+}
+
+// Metadata returns the resource type name.
+func (r *Resource) Metadata(
+	_ context.Context,
+	req resource.MetadataRequest,
+	resp *resource.MetadataResponse,
+) {
+	resp.TypeName = "oxide_instance_disk_attachment"
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *Resource) Configure(
+	_ context.Context,
+	req resource.ConfigureRequest,
+	_ *resource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*shared.ProviderData).Client
+}
+
+// ImportState imports an existing disk attachment into Terraform state.
+func (r *Resource) ImportState(
+	ctx context.Context,
+	req resource.ImportStateRequest,
+	resp *resource.ImportStateResponse,
+) {
+	idParts := strings.Split(req.ID, "/")
+	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("Expected import ID format: instance_id/disk_id, got: %s", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(
+		resp.State.SetAttribute(ctx, path.Root("instance_id"), idParts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("disk_id"), idParts[1])...)
+}
+
+// Schema defines the schema for the resource.
+func (r *Resource) Schema(
+	ctx context.Context,
+	_ resource.SchemaRequest,
+	resp *resource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `
+This resource manages the attachment of a disk to an instance.
+` + instance.StopRequiredNotes(
+			"attaching and detaching disks",
+			"Disks",
+			"disk_attachments",
+			"it detaches them",
+		),
+		Attributes: map[string]schema.Attribute{
+			// Disk attachments don't have their own IDs, so the ID is made
+			// of the instance and disk IDs.
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier for the attachment, in the format `instance_id/disk_id`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"instance_id": schema.StringAttribute{
+				Required:    true,
+				Description: "ID of the instance to attach the disk to.",
+				Validators: []validator.String{
+					oxidevalidator.IsUUID(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"disk_id": schema.StringAttribute{
+				Required:    true,
+				Description: "ID of the disk to attach.",
+				Validators: []validator.String{
+					oxidevalidator.IsUUID(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *Resource) Create(
+	ctx context.Context,
+	req resource.CreateRequest,
+	resp *resource.CreateResponse,
+) {
+	var plan ResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	instanceID := plan.InstanceID.ValueString()
+	resp.Diagnostics.Append(instance.WithInstanceStopped(ctx, r.client, createTimeout, instanceID,
+		func() diag.Diagnostics {
+			return instance.AttachDisks(ctx, r.client, []attr.Value{plan.DiskID}, instanceID)
+		},
+	)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.ID = types.StringValue(
+		fmt.Sprintf("%s/%s", plan.InstanceID.ValueString(), plan.DiskID.ValueString()),
+	)
+
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("created disk attachment with ID: %v", plan.ID.ValueString()),
+		map[string]any{"success": true},
+	)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *Resource) Read(
+	ctx context.Context,
+	req resource.ReadRequest,
+	resp *resource.ReadResponse,
+) {
+	var state ResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	attached, err := diskAttachedTo(ctx, r.client, state.DiskID.ValueString(),
+		state.InstanceID.ValueString())
+	if err != nil {
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+			"Unable to read disk attachment:",
+			err,
+		))
+		return
+	}
+
+	// Remove the attachment from state if the disk is gone, detached or
+	// attached to a different instance.
+	if !attached {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("read disk attachment with ID: %v", state.ID.ValueString()),
+		map[string]any{"success": true},
+	)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+// Only timeouts can change in-place; both IDs trigger replacement.
+func (r *Resource) Update(
+	ctx context.Context,
+	req resource.UpdateRequest,
+	resp *resource.UpdateResponse,
+) {
+	var plan ResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *Resource) Delete(
+	ctx context.Context,
+	req resource.DeleteRequest,
+	resp *resource.DeleteResponse,
+) {
+	var state ResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	// Only detach if the disk is still attached to the expected instance, so
+	// the instance isn't stopped for nothing.
+	instanceID := state.InstanceID.ValueString()
+	attached, err := diskAttachedTo(ctx, r.client, state.DiskID.ValueString(), instanceID)
+	if err != nil {
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+			"Error reading disk during delete:",
+			err,
+		))
+		return
+	}
+	if !attached {
+		return
+	}
+
+	resp.Diagnostics.Append(instance.WithInstanceStopped(ctx, r.client, deleteTimeout, instanceID,
+		func() diag.Diagnostics {
+			return instance.DetachDisks(ctx, r.client, []attr.Value{state.DiskID}, instanceID)
+		},
+	)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("deleted disk attachment with ID: %v", state.ID.ValueString()),
+		map[string]any{"success": true},
+	)
+}
+
+// diskAttachedTo reports whether the disk is attached, or being attached, to
+// the instance. A disk that doesn't exist isn't attached.
+func diskAttachedTo(
+	ctx context.Context,
+	client *oxide.Client,
+	diskID string,
+	instanceID string,
+) (bool, error) {
+	disk, err := client.DiskView(ctx, oxide.DiskViewParams{
+		Disk: oxide.NameOrId(diskID),
+	})
+	if err != nil {
+		if shared.Is404(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	switch v := disk.State.Value.(type) {
+	case *oxide.DiskStateAttached:
+		return v.Instance == instanceID, nil
+	case *oxide.DiskStateAttaching:
+		return v.Instance == instanceID, nil
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,180 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package instancediskattachment_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/oxidecomputer/oxide.go/oxide"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/shared"
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/sharedtest"
+)
+
+type diskAttachmentResourceConfig struct {
+	BlockName    string
+	InstanceName string
+	BootDiskName string
+	DataDiskName string
+	DataDisk     string
+}
+
+var diskAttachmentResourceConfigTpl = `
+data "oxide_project" "test" {
+  name = "tf-acc-test"
+}
+
+resource "oxide_disk" "boot" {
+  project_id  = data.oxide_project.test.id
+  description = "a boot disk"
+  name        = "{{.BootDiskName}}"
+  size        = 1073741824
+  block_size  = 512
+}
+
+resource "oxide_disk" "data" {
+  project_id  = data.oxide_project.test.id
+  description = "a data disk"
+  name        = "{{.DataDiskName}}-a"
+  size        = 1073741824
+  block_size  = 512
+}
+
+resource "oxide_disk" "data2" {
+  project_id  = data.oxide_project.test.id
+  description = "another data disk"
+  name        = "{{.DataDiskName}}-b"
+  size        = 1073741824
+  block_size  = 512
+}
+
+resource "oxide_instance" "test" {
+  project_id       = data.oxide_project.test.id
+  description      = "a test instance"
+  name             = "{{.InstanceName}}"
+  hostname         = "terraform-acc-myhost"
+  memory           = 1073741824
+  ncpus            = 1
+  desired_state    = "running"
+  boot_disk_id     = oxide_disk.boot.id
+  disk_attachments = [oxide_disk.boot.id]
+
+  ignore_unowned_disk_attachments = true
+}
+
+resource "oxide_instance_disk_attachment" "{{.BlockName}}" {
+  instance_id = oxide_instance.test.id
+  disk_id     = oxide_disk.{{.DataDisk}}.id
+  timeouts = {
+    create = "5m"
+    delete = "5m"
+  }
+}
+`
+
+func TestAccCloudResourceInstanceDiskAttachment_full(t *testing.T) {
+	blockName := sharedtest.NewBlockName("instance-disk-attachment")
+	resourceName := fmt.Sprintf("oxide_instance_disk_attachment.%s", blockName)
+	cfg := diskAttachmentResourceConfig{
+		BlockName:    blockName,
+		InstanceName: sharedtest.NewResourceName(),
+		BootDiskName: sharedtest.NewResourceName(),
+		DataDiskName: sharedtest.NewResourceName(),
+		DataDisk:     "data",
+	}
+
+	// Swap the data disk without touching the instance.
+	cfgSwap := cfg
+	cfgSwap.DataDisk = "data2"
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { sharedtest.PreCheck(t) },
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		CheckDestroy:             testAccResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: sharedtest.ParsedAccConfig(t, cfg, diskAttachmentResourceConfigTpl),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkDiskAttachmentResource(resourceName, "oxide_disk.data"),
+					// The instance doesn't manage the attached data disk.
+					resource.TestCheckResourceAttr(
+						"oxide_instance.test", "disk_attachments.#", "1",
+					),
+					sharedtest.CheckInstanceRunState("oxide_instance.test", oxide.InstanceStateRunning),
+				),
+			},
+			{
+				Config: sharedtest.ParsedAccConfig(t, cfgSwap, diskAttachmentResourceConfigTpl),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkDiskAttachmentResource(resourceName, "oxide_disk.data2"),
+					resource.TestCheckResourceAttr(
+						"oxide_instance.test", "disk_attachments.#", "1",
+					),
+					sharedtest.CheckInstanceRunState("oxide_instance.test", oxide.InstanceStateRunning),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeouts"},
+			},
+		},
+	})
+}
+
+func checkDiskAttachmentResource(resourceName, diskName string) resource.TestCheckFunc {
+	return resource.ComposeAggregateTestCheckFunc([]resource.TestCheckFunc{
+		resource.TestCheckResourceAttrPair(resourceName, "instance_id", "oxide_instance.test", "id"),
+		resource.TestCheckResourceAttrPair(resourceName, "disk_id", diskName, "id"),
+		func(s *terraform.State) error {
+			rs := s.RootModule().Resources[resourceName]
+			want := rs.Primary.Attributes["instance_id"] + "/" + rs.Primary.Attributes["disk_id"]
+			if rs.Primary.ID != want {
+				return fmt.Errorf("expected ID %s, got %s", want, rs.Primary.ID)
+			}
+			return nil
+		},
+	}...)
+}
+
+func testAccResourceDestroy(s *terraform.State) error {
+	client, err := sharedtest.NewTestClient()
+	if err != nil {
+		return err
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "oxide_instance_disk_attachment" {
+			continue
+		}
+
+		ctx := context.Background()
+		ctx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+
+		params := oxide.DiskViewParams{
+			Disk: oxide.NameOrId(rs.Primary.Attributes["disk_id"]),
+		}
+		res, err := client.DiskView(ctx, params)
+		if err != nil && shared.Is404(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, ok := res.State.Value.(*oxide.DiskStateAttached); ok {
+			return fmt.Errorf("disk (%v) is still attached", res.Name)
+		}
+	}
+
+	return nil
+}
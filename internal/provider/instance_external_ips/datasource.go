@@ -65,7 +65,7 @@ func (d *DataSource) Configure(
 		return
 	}
 
-	d.client = req.ProviderData.(*oxide.Client)
+	d.client = req.ProviderData.(*shared.ProviderData).Client
 }
 
 func (d *DataSource) Schema(
@@ -134,10 +134,10 @@ func (d *DataSource) Read(
 	}
 	ips, err := d.client.InstanceExternalIpList(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read external ips:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
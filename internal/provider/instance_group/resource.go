@@ -0,0 +1,1056 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package instancegroup
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/oxidecomputer/oxide.go/oxide"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/instance"
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/shared"
+	oxidevalidator "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/validator"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource               = (*Resource)(nil)
+	_ resource.ResourceWithConfigure  = (*Resource)(nil)
+	_ resource.ResourceWithModifyPlan = (*Resource)(nil)
+)
+
+// NewResource is a helper function to simplify the provider implementation.
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+// Resource is the resource implementation.
+type Resource struct {
+	client           *oxide.Client
+	defaultProjectID string
+}
+
+type ResourceModel struct {
+	ID                  types.String                `tfsdk:"id"`
+	ProjectID           types.String                `tfsdk:"project_id"`
+	Name                types.String                `tfsdk:"name"`
+	Description         types.String                `tfsdk:"description"`
+	Size                types.Int64                 `tfsdk:"size"`
+	Template            TemplateResourceModel       `tfsdk:"template"`
+	RollingUpdate       *RollingUpdateResourceModel `tfsdk:"rolling_update"`
+	AntiAffinityGroupID types.String                `tfsdk:"anti_affinity_group_id"`
+	AntiAffinityPolicy  types.String                `tfsdk:"anti_affinity_policy"`
+	Instances           types.List                  `tfsdk:"instances"`
+	Timeouts            timeouts.Value              `tfsdk:"timeouts"`
+}
+
+type TemplateResourceModel struct {
+	NCPUs             types.Int64                   `tfsdk:"ncpus"`
+	Memory            types.Int64                   `tfsdk:"memory"`
+	BootDisk          TemplateBootDiskResourceModel `tfsdk:"boot_disk"`
+	NetworkInterfaces []TemplateNICResourceModel    `tfsdk:"network_interfaces"`
+	SSHPublicKeys     types.Set                     `tfsdk:"ssh_public_keys"`
+	UserData          types.String                  `tfsdk:"user_data"`
+}
+
+type TemplateBootDiskResourceModel struct {
+	SourceImageID types.String `tfsdk:"source_image_id"`
+	Size          types.Int64  `tfsdk:"size"`
+}
+
+type TemplateNICResourceModel struct {
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+	SubnetID    types.String `tfsdk:"subnet_id"`
+	VPCID       types.String `tfsdk:"vpc_id"`
+}
+
+type RollingUpdateResourceModel struct {
+	MaxUnavailable types.Int64 `tfsdk:"max_unavailable"`
+}
+
+type MemberResourceModel struct {
+	ID   types.String `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
+}
+
+var MemberType = types.ObjectType{}.WithAttributeTypes(
+	map[string]attr.Type{
+		"id":   types.StringType,
+		"name": types.StringType,
+	},
+)
+
+// Metadata returns the resource type name.
+func (r *Resource) Metadata(
+	_ context.Context,
+	req resource.MetadataRequest,
+	resp *resource.MetadataResponse,
+) {
+	resp.TypeName = "oxide_instance_group"
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *Resource) Configure(
+	_ context.Context,
+	req resource.ConfigureRequest,
+	_ *resource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData := req.ProviderData.(*shared.ProviderData)
+	r.client = providerData.Client
+	r.defaultProjectID = providerData.DefaultProjectID
+}
+
+// ModifyPlan sets project_id to the provider's default project when it's not
+// set in the configuration, and warns when applying the plan will replace the
+// members of the group.
+func (r *Resource) ModifyPlan(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	shared.ModifyPlanForDefaultProject(ctx, r.defaultProjectID, req, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only updates may replace members.
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var state ResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The plan can't be decoded into the model while entire nested objects
+	// are unknown, in which case there's nothing to warn about yet.
+	var plan ResourceModel
+	if diags := resp.Plan.Get(ctx, &plan); diags.HasError() {
+		return
+	}
+
+	if state.Template.Equal(plan.Template) {
+		return
+	}
+
+	resp.Diagnostics.AddWarning(
+		"Instance group members will be replaced",
+		fmt.Sprintf(
+			"The members of instance group %s will be replaced, %d at a time, to apply "+
+				"changes to the template.",
+			state.Name.ValueString(),
+			plan.maxUnavailable(),
+		),
+	)
+}
+
+// Schema defines the schema for the resource.
+func (r *Resource) Schema(
+	ctx context.Context,
+	_ resource.SchemaRequest,
+	resp *resource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: shared.ReplaceBackticks(`
+This resource manages a group of identical instances created from a template.
+
+Members are named after the group, such as ''web-0'' and ''web-1'' for a group named ''web'', and are added to an anti-affinity group created along with the group to spread them across sleds. Each member boots from a disk created from the template image, which is deleted along with the member.
+
+Changes to the template are rolled out by replacing members, at most ''rolling_update.max_unavailable'' at a time. Each replacement must be running before the next members are replaced. Members record a hash of their template in their description, so if a rollout fails, the next apply only replaces the members that are still on a previous template.
+
+-> The template of running members can't be read from the API, so changes made to members outside of Terraform are not detected. Members that are deleted are recreated by the next apply.
+`),
+		Attributes: map[string]schema.Attribute{
+			// Instance groups aren't an API resource, so the group is identified
+			// by its anti-affinity group.
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier of the instance group. This is the ID of its anti-affinity group.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"project_id": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "ID of the project that will contain the instance group. Defaults to the provider's `default_project`.",
+				Validators: []validator.String{
+					oxidevalidator.IsUUID(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the instance group. Members are named after the group, followed by their index, so the name can be at most 54 characters long.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtMost(maxGroupNameLength),
+				},
+			},
+			"description": schema.StringAttribute{
+				Required:    true,
+				Description: "Description for the instance group.",
+			},
+			"size": schema.Int64Attribute{
+				Required:    true,
+				Description: "Number of instances in the group. At most 1000.",
+				Validators: []validator.Int64{
+					int64validator.Between(0, maxGroupSize),
+				},
+			},
+			"template": schema.SingleNestedAttribute{
+				Required:    true,
+				Description: "Template the members of the group are created from. Changes are rolled out by replacing members.",
+				Attributes: map[string]schema.Attribute{
+					"ncpus": schema.Int64Attribute{
+						Required:    true,
+						Description: "The number of vCPUs to be allocated to each instance.",
+					},
+					"memory": schema.Int64Attribute{
+						Required:    true,
+						Description: "The amount of RAM (in bytes) to be allocated to each instance.",
+					},
+					"boot_disk": schema.SingleNestedAttribute{
+						Required:    true,
+						Description: "Boot disk created for each instance.",
+						Attributes: map[string]schema.Attribute{
+							"source_image_id": schema.StringAttribute{
+								Required:    true,
+								Description: "ID of the image the boot disks are created from.",
+								Validators: []validator.String{
+									oxidevalidator.IsUUID(),
+								},
+							},
+							"size": schema.Int64Attribute{
+								Required:    true,
+								Description: "Size of the boot disks in bytes.",
+							},
+						},
+					},
+					"network_interfaces": schema.ListNestedAttribute{
+						Optional:    true,
+						Description: "Network interfaces created for each instance. Private IP addresses are assigned automatically.",
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"name": schema.StringAttribute{
+									Required:    true,
+									Description: "Name of the network interface.",
+								},
+								"description": schema.StringAttribute{
+									Required:    true,
+									Description: "Description for the network interface.",
+								},
+								"subnet_id": schema.StringAttribute{
+									Required:    true,
+									Description: "ID of the VPC subnet in which to create the network interface.",
+									Validators: []validator.String{
+										oxidevalidator.IsUUID(),
+									},
+								},
+								"vpc_id": schema.StringAttribute{
+									Required:    true,
+									Description: "ID of the VPC in which to create the network interface.",
+									Validators: []validator.String{
+										oxidevalidator.IsUUID(),
+									},
+								},
+							},
+						},
+					},
+					"ssh_public_keys": schema.SetAttribute{
+						Optional:    true,
+						Description: "An allowlist of IDs of the SSH public keys to be transferred to each instance via cloud-init during instance creation.",
+						ElementType: types.StringType,
+					},
+					"user_data": schema.StringAttribute{
+						Optional: true,
+						MarkdownDescription: `
+User data for instance initialization systems (such as cloud-init).
+Must be a Base64-encoded string, as specified in [RFC 4648 § 4](https://datatracker.ietf.org/doc/html/rfc4648#section-4).
+Maximum 32 KiB unencoded data.`,
+					},
+				},
+			},
+			"rolling_update": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "Strategy used to roll out changes to the template. Defaults to replacing one member at a time.",
+				Attributes: map[string]schema.Attribute{
+					"max_unavailable": schema.Int64Attribute{
+						Optional:    true,
+						Computed:    true,
+						Default:     int64default.StaticInt64(1),
+						Description: "Maximum number of members replaced at the same time. Defaults to `1`.",
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+				},
+			},
+			"anti_affinity_group_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "ID of the anti-affinity group the members of the group are added to.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"anti_affinity_policy": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(string(oxide.AffinityPolicyAllow)),
+				Description: "Affinity policy of the anti-affinity group, used to describe what to do when members can't be placed on different sleds. Must be one of `allow` or `fail`. Defaults to `allow`.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						string(oxide.AffinityPolicyAllow),
+						string(oxide.AffinityPolicyFail),
+					),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"instances": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Members of the group, ordered by index.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "ID of the instance.",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the instance.",
+						},
+					},
+				},
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *Resource) Create(
+	ctx context.Context,
+	req resource.CreateRequest,
+	resp *resource.CreateResponse,
+) {
+	var plan ResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	params := oxide.AntiAffinityGroupCreateParams{
+		Project: oxide.NameOrId(plan.ProjectID.ValueString()),
+		Body: &oxide.AntiAffinityGroupCreate{
+			Description: plan.Description.ValueString(),
+			Name:        oxide.Name(plan.Name.ValueString()),
+			// TODO: For now, the only option is "sled", change this into
+			// an attribute when there are more options.
+			FailureDomain: oxide.FailureDomainSled,
+			Policy:        oxide.AffinityPolicy(plan.AntiAffinityPolicy.ValueString()),
+		},
+	}
+	group, err := r.client.AntiAffinityGroupCreate(ctx, params)
+	if err != nil {
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+			"Error creating anti-affinity group for instance group",
+			err,
+		))
+		return
+	}
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("created anti-affinity group for instance group with ID: %v", group.Id),
+		map[string]any{"success": true},
+	)
+
+	plan.ID = types.StringValue(group.Id)
+	plan.AntiAffinityGroupID = types.StringValue(group.Id)
+
+	for i := range plan.Size.ValueInt64() {
+		diags := createGroupMember(ctx, r.client, createTimeout, plan, groupMemberName(plan, i))
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			// Save the members created so far so the group is tainted instead
+			// of being left out of the state.
+			resp.Diagnostics.Append(r.readMembers(ctx, &plan)...)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(r.readMembers(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("created instance group with ID: %v", plan.ID.ValueString()),
+		map[string]any{"success": true},
+	)
+
+	// Save plan into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *Resource) Read(
+	ctx context.Context,
+	req resource.ReadRequest,
+	resp *resource.ReadResponse,
+) {
+	var state ResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	params := oxide.AntiAffinityGroupViewParams{
+		AntiAffinityGroup: oxide.NameOrId(state.AntiAffinityGroupID.ValueString()),
+	}
+	group, err := r.client.AntiAffinityGroupView(ctx, params)
+	if err != nil {
+		if shared.Is404(err) {
+			// Remove resource from state during a refresh
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+			"Unable to read instance group:",
+			err,
+		))
+		return
+	}
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("read instance group with ID: %v", state.ID.ValueString()),
+		map[string]any{"success": true},
+	)
+
+	state.Description = types.StringValue(group.Description)
+	state.ProjectID = types.StringValue(group.ProjectId)
+	state.AntiAffinityPolicy = types.StringValue(string(group.Policy))
+
+	// The size is read from the members, so members that were deleted are
+	// recreated by the next apply.
+	resp.Diagnostics.Append(r.readMembers(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+// Members beyond the new size are deleted first, then the remaining members
+// are replaced if the template changed, and finally missing members are
+// created.
+func (r *Resource) Update(
+	ctx context.Context,
+	req resource.UpdateRequest,
+	resp *resource.UpdateResponse,
+) {
+	var plan ResourceModel
+	var state ResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	groupID := state.AntiAffinityGroupID.ValueString()
+	plan.ID = state.ID
+	plan.AntiAffinityGroupID = state.AntiAffinityGroupID
+
+	if !plan.Description.Equal(state.Description) {
+		params := oxide.AntiAffinityGroupUpdateParams{
+			AntiAffinityGroup: oxide.NameOrId(groupID),
+			Body: &oxide.AntiAffinityGroupUpdate{
+				Description: plan.Description.ValueString(),
+				Name:        oxide.Name(plan.Name.ValueString()),
+			},
+		}
+		if _, err := r.client.AntiAffinityGroupUpdate(ctx, params); err != nil {
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+				"Error updating instance group",
+				err,
+			))
+			return
+		}
+		state.Description = plan.Description
+	}
+
+	// On failure, save the members as they are along with the previous
+	// template. Only members that weren't created from the new template are
+	// replaced, so the next apply picks up where this one stopped.
+	fail := func(diags diag.Diagnostics) {
+		resp.Diagnostics.Append(diags...)
+		resp.Diagnostics.Append(r.readMembers(ctx, &state)...)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	}
+
+	members, diags := listGroupMembers(ctx, r.client, groupID, plan.Name.ValueString())
+	if diags.HasError() {
+		fail(diags)
+		return
+	}
+
+	size := plan.Size.ValueInt64()
+	var kept []groupMember
+	for _, member := range members {
+		if member.index < size {
+			kept = append(kept, member)
+			continue
+		}
+		if diags := deleteGroupMember(ctx, r.client, updateTimeout, member.id); diags.HasError() {
+			fail(diags)
+			return
+		}
+	}
+
+	outdated, diags := outdatedGroupMembers(ctx, r.client, plan, kept)
+	if diags.HasError() {
+		fail(diags)
+		return
+	}
+
+	// Replace members in batches so at most max_unavailable members are
+	// unavailable at any time.
+	for batch := range slices.Chunk(outdated, int(plan.maxUnavailable())) {
+		for _, member := range batch {
+			diags := deleteGroupMember(ctx, r.client, updateTimeout, member.id)
+			if diags.HasError() {
+				fail(diags)
+				return
+			}
+		}
+		for _, member := range batch {
+			diags := createGroupMember(ctx, r.client, updateTimeout, plan, member.name)
+			if diags.HasError() {
+				fail(diags)
+				return
+			}
+		}
+		tflog.Trace(
+			ctx,
+			fmt.Sprintf("replaced %d members of instance group with ID: %v",
+				len(batch), groupID),
+			map[string]any{"success": true},
+		)
+	}
+
+	for i := range size {
+		if slices.ContainsFunc(kept, func(m groupMember) bool { return m.index == i }) {
+			continue
+		}
+		diags := createGroupMember(ctx, r.client, updateTimeout, plan, groupMemberName(plan, i))
+		if diags.HasError() {
+			fail(diags)
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(r.readMembers(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("updated instance group with ID: %v", groupID),
+		map[string]any{"success": true},
+	)
+
+	// Save plan into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *Resource) Delete(
+	ctx context.Context,
+	req resource.DeleteRequest,
+	resp *resource.DeleteResponse,
+) {
+	var state ResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	groupID := state.AntiAffinityGroupID.ValueString()
+	members, diags := listGroupMembers(ctx, r.client, groupID, state.Name.ValueString())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	for _, member := range members {
+		resp.Diagnostics.Append(deleteGroupMember(ctx, r.client, deleteTimeout, member.id)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	params := oxide.AntiAffinityGroupDeleteParams{
+		AntiAffinityGroup: oxide.NameOrId(groupID),
+	}
+	if err := r.client.AntiAffinityGroupDelete(ctx, params); err != nil {
+		if !shared.Is404(err) {
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+				"Error deleting instance group:",
+				err,
+			))
+			return
+		}
+	}
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("deleted instance group with ID: %v", state.ID.ValueString()),
+		map[string]any{"success": true},
+	)
+}
+
+// readMembers reads the members of the group into the model.
+func (r *Resource) readMembers(
+	ctx context.Context,
+	model *ResourceModel,
+) diag.Diagnostics {
+	members, diags := listGroupMembers(
+		ctx, r.client, model.AntiAffinityGroupID.ValueString(), model.Name.ValueString(),
+	)
+	if diags.HasError() {
+		return diags
+	}
+
+	m := make([]MemberResourceModel, len(members))
+	for i, member := range members {
+		m[i] = MemberResourceModel{
+			ID:   types.StringValue(member.id),
+			Name: types.StringValue(member.name),
+		}
+	}
+
+	model.Size = types.Int64Value(int64(len(members)))
+	model.Instances, diags = types.ListValueFrom(ctx, MemberType, m)
+	return diags
+}
+
+// maxUnavailable returns the maximum number of members replaced at the same
+// time.
+func (m ResourceModel) maxUnavailable() int64 {
+	if m.RollingUpdate == nil || m.RollingUpdate.MaxUnavailable.IsNull() ||
+		m.RollingUpdate.MaxUnavailable.IsUnknown() {
+		return 1
+	}
+	return m.RollingUpdate.MaxUnavailable.ValueInt64()
+}
+
+// Equal reports whether both templates create the same instances.
+func (t TemplateResourceModel) Equal(o TemplateResourceModel) bool {
+	return t.NCPUs.Equal(o.NCPUs) &&
+		t.Memory.Equal(o.Memory) &&
+		t.BootDisk.SourceImageID.Equal(o.BootDisk.SourceImageID) &&
+		t.BootDisk.Size.Equal(o.BootDisk.Size) &&
+		slices.Equal(t.NetworkInterfaces, o.NetworkInterfaces) &&
+		t.SSHPublicKeys.Equal(o.SSHPublicKeys) &&
+		t.UserData.Equal(o.UserData)
+}
+
+// hash returns a short hash of the template. It's stored in the description
+// of members, since the template of running members can't be read from the
+// API otherwise.
+func (t TemplateResourceModel) hash() string {
+	h := sha256.New()
+	fmt.Fprintln(h, t.NCPUs.ValueInt64(), t.Memory.ValueInt64())
+	fmt.Fprintln(h, t.BootDisk.SourceImageID.ValueString(), t.BootDisk.Size.ValueInt64())
+	for _, nic := range t.NetworkInterfaces {
+		fmt.Fprintf(h, "%q %q %q %q\n",
+			nic.Name.ValueString(),
+			nic.Description.ValueString(),
+			nic.SubnetID.ValueString(),
+			nic.VPCID.ValueString(),
+		)
+	}
+	var keys []string
+	for _, key := range t.SSHPublicKeys.Elements() {
+		if key, ok := key.(types.String); ok {
+			keys = append(keys, key.ValueString())
+		}
+	}
+	slices.Sort(keys)
+	fmt.Fprintf(h, "%q\n", keys)
+	fmt.Fprintf(h, "%q\n", t.UserData.ValueString())
+
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// Members are named "<name>-<index>" and their boot disks "<name>-<index>-boot".
+// Both must fit in the 63 characters allowed for names and hostnames.
+const (
+	maxGroupSize       = 1000
+	maxGroupNameLength = 63 - len("-999-boot")
+)
+
+// groupMember is a member of an instance group, as listed from its
+// anti-affinity group.
+type groupMember struct {
+	id    string
+	name  string
+	index int64
+}
+
+// groupMemberName returns the name of the member of the group at index.
+func groupMemberName(model ResourceModel, index int64) string {
+	return fmt.Sprintf("%s-%d", model.Name.ValueString(), index)
+}
+
+// groupMemberDescription returns the description of the members created from
+// the template of the group.
+func groupMemberDescription(model ResourceModel) string {
+	return fmt.Sprintf(
+		"Member of instance group %s, template %s",
+		model.Name.ValueString(),
+		model.Template.hash(),
+	)
+}
+
+// outdatedGroupMembers returns the members that weren't created from the
+// template of the group, as told by their description.
+func outdatedGroupMembers(
+	ctx context.Context,
+	client *oxide.Client,
+	model ResourceModel,
+	members []groupMember,
+) ([]groupMember, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if len(members) == 0 {
+		return nil, nil
+	}
+
+	instances, err := client.InstanceListAllPages(ctx, oxide.InstanceListParams{
+		Project: oxide.NameOrId(model.ProjectID.ValueString()),
+		SortBy:  oxide.NameOrIdSortModeNameAscending,
+	})
+	if err != nil {
+		diags.Append(shared.APIErrorDiagnostic(
+			"Unable to list instance group members:",
+			err,
+		))
+		return nil, diags
+	}
+
+	descriptions := make(map[string]string, len(instances))
+	for _, inst := range instances {
+		descriptions[inst.Id] = inst.Description
+	}
+
+	description := groupMemberDescription(model)
+	var outdated []groupMember
+	for _, member := range members {
+		if descriptions[member.id] != description {
+			outdated = append(outdated, member)
+		}
+	}
+
+	return outdated, nil
+}
+
+// listGroupMembers returns the members of the instance group, ordered by
+// index. Instances added to the anti-affinity group by other means aren't
+// members of the instance group.
+func listGroupMembers(
+	ctx context.Context,
+	client *oxide.Client,
+	groupID string,
+	groupName string,
+) ([]groupMember, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	params := oxide.AntiAffinityGroupMemberListParams{
+		AntiAffinityGroup: oxide.NameOrId(groupID),
+	}
+	members, err := client.AntiAffinityGroupMemberListAllPages(ctx, params)
+	if err != nil {
+		diags.Append(shared.APIErrorDiagnostic(
+			"Unable to list instance group members:",
+			err,
+		))
+		return nil, diags
+	}
+
+	var result []groupMember
+	for _, member := range members {
+		m, ok := member.Value.(*oxide.AntiAffinityGroupMemberInstance)
+		if !ok {
+			continue
+		}
+		suffix, ok := strings.CutPrefix(string(m.Value.Name), groupName+"-")
+		if !ok {
+			continue
+		}
+		index, err := strconv.ParseInt(suffix, 10, 64)
+		if err != nil || index < 0 || strconv.FormatInt(index, 10) != suffix {
+			continue
+		}
+		result = append(result, groupMember{
+			id:    m.Value.Id,
+			name:  string(m.Value.Name),
+			index: index,
+		})
+	}
+	slices.SortFunc(result, func(a, b groupMember) int {
+		return cmp.Compare(a.index, b.index)
+	})
+
+	return result, nil
+}
+
+// createGroupMember creates a member of the group from its template and waits
+// for it to be running.
+func createGroupMember(
+	ctx context.Context,
+	client *oxide.Client,
+	timeout time.Duration,
+	model ResourceModel,
+	name string,
+) diag.Diagnostics {
+	template := model.Template
+
+	params := oxide.InstanceCreateParams{
+		Project: oxide.NameOrId(model.ProjectID.ValueString()),
+		Body: &oxide.InstanceCreate{
+			Description: groupMemberDescription(model),
+			Name:        oxide.Name(name),
+			Hostname:    oxide.Hostname(name),
+			Memory:      oxide.ByteCount(template.Memory.ValueInt64()),
+			Ncpus:       oxide.InstanceCpuCount(template.NCPUs.ValueInt64()),
+			Start:       oxide.NewPointer(true),
+			UserData:    template.UserData.ValueString(),
+			AntiAffinityGroups: []oxide.NameOrId{
+				oxide.NameOrId(model.AntiAffinityGroupID.ValueString()),
+			},
+		},
+	}
+
+	params.Body.BootDisk = instance.NewBootDiskCreate(&instance.BootDiskResourceModel{
+		Name:          types.StringValue(name + "-boot"),
+		SourceImageID: template.BootDisk.SourceImageID,
+		Size:          template.BootDisk.Size,
+		DiskType:      types.StringValue(string(oxide.DiskBackendTypeDistributed)),
+	}, name)
+
+	sshKeys, diags := shared.NewNameOrIdList(template.SSHPublicKeys)
+	if diags.HasError() {
+		return diags
+	}
+	params.Body.SshPublicKeys = sshKeys
+
+	nicModels := make([]instance.NICResourceModel, len(template.NetworkInterfaces))
+	for i, nic := range template.NetworkInterfaces {
+		nicModels[i] = instance.NICResourceModel{
+			Name:        nic.Name,
+			Description: nic.Description,
+			SubnetID:    nic.SubnetID,
+			VPCID:       nic.VPCID,
+			IPConfig: instance.IPConfigResourceModel{
+				V4: &instance.IPConfigV4ResourceModel{
+					IP: types.StringValue(string(oxide.Ipv4AssignmentTypeAuto)),
+				},
+			},
+		}
+	}
+	nics, diags := instance.NewNetworkInterfaceAttachment(ctx, client, nicModels)
+	if diags.HasError() {
+		return diags
+	}
+	params.Body.NetworkInterfaces = nics
+
+	member, err := client.InstanceCreate(ctx, params)
+	if err != nil {
+		diags.Append(shared.APIErrorDiagnostic(
+			"Error creating instance group member",
+			err,
+		))
+		return diags
+	}
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("created instance group member with ID: %v", member.Id),
+		map[string]any{"success": true},
+	)
+
+	return instance.WaitForInstanceStart(ctx, client, timeout, member.Id)
+}
+
+// deleteGroupMember stops and deletes a member of the group along with its
+// boot disk.
+func deleteGroupMember(
+	ctx context.Context,
+	client *oxide.Client,
+	timeout time.Duration,
+	instanceID string,
+) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	member, err := client.InstanceView(ctx, oxide.InstanceViewParams{
+		Instance: oxide.NameOrId(instanceID),
+	})
+	if err != nil {
+		if shared.Is404(err) {
+			return nil
+		}
+		diags.Append(shared.APIErrorDiagnostic(
+			"Unable to read instance group member:",
+			err,
+		))
+		return diags
+	}
+
+	params := oxide.InstanceStopParams{
+		Instance: oxide.NameOrId(instanceID),
+	}
+	if _, err := client.InstanceStop(ctx, params); err != nil {
+		if !shared.Is404(err) {
+			diags.Append(shared.APIErrorDiagnostic(
+				"Unable to stop instance group member:",
+				err,
+			))
+			return diags
+		}
+	}
+
+	diags = instance.WaitForInstanceStop(ctx, client, timeout, instanceID)
+	if diags.HasError() {
+		return diags
+	}
+
+	params2 := oxide.InstanceDeleteParams{
+		Instance: oxide.NameOrId(instanceID),
+	}
+	if err := client.InstanceDelete(ctx, params2); err != nil {
+		if !shared.Is404(err) {
+			diags.Append(shared.APIErrorDiagnostic(
+				"Unable to delete instance group member:",
+				err,
+			))
+			return diags
+		}
+	}
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("deleted instance group member with ID: %v", instanceID),
+		map[string]any{"success": true},
+	)
+
+	// The boot disk is detached when the instance is deleted.
+	if member.BootDiskId == "" {
+		return nil
+	}
+	diskParams := oxide.DiskDeleteParams{
+		Disk: oxide.NameOrId(member.BootDiskId),
+	}
+	if err := client.DiskDelete(ctx, diskParams); err != nil {
+		if !shared.Is404(err) {
+			diags.Append(shared.APIErrorDiagnostic(
+				"Unable to delete instance group member boot disk:",
+				err,
+			))
+			return diags
+		}
+	}
+
+	return nil
+}
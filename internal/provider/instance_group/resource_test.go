@@ -0,0 +1,293 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package instancegroup_test
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/oxidecomputer/oxide.go/oxide"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/shared"
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/sharedtest"
+)
+
+type groupResourceConfig struct {
+	BlockName      string
+	GroupName      string
+	Size           int
+	Memory         int64
+	MaxUnavailable int
+}
+
+var groupResourceConfigTpl = `
+data "oxide_project" "test" {
+  name = "tf-acc-test"
+}
+
+data "oxide_image" "test" {
+  name = "alpine-project"
+}
+
+resource "oxide_instance_group" "{{.BlockName}}" {
+  project_id  = data.oxide_project.test.id
+  description = "a test instance group"
+  name        = "{{.GroupName}}"
+  size        = {{.Size}}
+  template = {
+    ncpus  = 1
+    memory = {{.Memory}}
+    boot_disk = {
+      source_image_id = data.oxide_image.test.id
+      size            = 1073741824
+    }
+  }
+  rolling_update = {
+    max_unavailable = {{.MaxUnavailable}}
+  }
+  timeouts = {
+    create = "15m"
+    update = "15m"
+    delete = "15m"
+  }
+}
+`
+
+func TestAccCloudResourceInstanceGroup_full(t *testing.T) {
+	blockName := sharedtest.NewBlockName("instance-group")
+	resourceName := fmt.Sprintf("oxide_instance_group.%s", blockName)
+	groupName := sharedtest.NewResourceName()
+	cfg := groupResourceConfig{
+		BlockName:      blockName,
+		GroupName:      groupName,
+		Size:           2,
+		Memory:         1073741824,
+		MaxUnavailable: 1,
+	}
+
+	// Roll out a new template and grow the group at the same time.
+	cfgUpdate := cfg
+	cfgUpdate.Size = 3
+	cfgUpdate.Memory = 2147483648
+	cfgUpdate.MaxUnavailable = 2
+
+	// Shrink the group.
+	cfgShrink := cfgUpdate
+	cfgShrink.Size = 1
+
+	// Member names wouldn't fit in 63 characters.
+	cfgLongName := cfg
+	cfgLongName.GroupName = groupName + "-toolong"
+
+	// Members already on the template must not be replaced.
+	var memberID string
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { sharedtest.PreCheck(t) },
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		CheckDestroy:             testAccResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      sharedtest.ParsedAccConfig(t, cfgLongName, groupResourceConfigTpl),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value Length`),
+			},
+			{
+				Config: sharedtest.ParsedAccConfig(t, cfg, groupResourceConfigTpl),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkGroupResource(resourceName, groupName, 2),
+					checkGroupMembers(resourceName, oxide.ByteCount(1073741824)),
+				),
+			},
+			{
+				Config: sharedtest.ParsedAccConfig(t, cfgUpdate, groupResourceConfigTpl),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkGroupResource(resourceName, groupName, 3),
+					checkGroupMembers(resourceName, oxide.ByteCount(2147483648)),
+					func(s *terraform.State) error {
+						memberID = s.RootModule().Resources[resourceName].Primary.Attributes["instances.0.id"]
+						return nil
+					},
+				),
+			},
+			{
+				Config: sharedtest.ParsedAccConfig(t, cfgShrink, groupResourceConfigTpl),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkGroupResource(resourceName, groupName, 1),
+					checkGroupMembers(resourceName, oxide.ByteCount(2147483648)),
+					func(s *terraform.State) error {
+						return resource.TestCheckResourceAttr(
+							resourceName, "instances.0.id", memberID,
+						)(s)
+					},
+				),
+			},
+		},
+	})
+}
+
+func checkGroupResource(resourceName, groupName string, size int) resource.TestCheckFunc {
+	checks := []resource.TestCheckFunc{
+		resource.TestCheckResourceAttrSet(resourceName, "id"),
+		resource.TestCheckResourceAttrPair(
+			resourceName, "id", resourceName, "anti_affinity_group_id",
+		),
+		resource.TestCheckResourceAttr(resourceName, "description", "a test instance group"),
+		resource.TestCheckResourceAttr(resourceName, "name", groupName),
+		resource.TestCheckResourceAttr(resourceName, "anti_affinity_policy", "allow"),
+		resource.TestCheckResourceAttr(resourceName, "size", fmt.Sprint(size)),
+		resource.TestCheckResourceAttr(resourceName, "instances.#", fmt.Sprint(size)),
+	}
+	for i := range size {
+		checks = append(checks,
+			resource.TestCheckResourceAttrSet(resourceName, fmt.Sprintf("instances.%d.id", i)),
+			resource.TestCheckResourceAttr(
+				resourceName,
+				fmt.Sprintf("instances.%d.name", i),
+				fmt.Sprintf("%s-%d", groupName, i),
+			),
+		)
+	}
+	return resource.ComposeAggregateTestCheckFunc(checks...)
+}
+
+// checkGroupMembers checks that every member of the group is running with the
+// expected amount of memory, and that they were all created from the same
+// template.
+func checkGroupMembers(resourceName string, memory oxide.ByteCount) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", resourceName)
+		}
+
+		client, err := sharedtest.NewTestClient()
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		size, err := strconv.Atoi(rs.Primary.Attributes["instances.#"])
+		if err != nil {
+			return err
+		}
+		prefix := fmt.Sprintf("Member of instance group %s, template ", rs.Primary.Attributes["name"])
+		var description string
+		for i := range size {
+			id := rs.Primary.Attributes[fmt.Sprintf("instances.%d.id", i)]
+			instance, err := client.InstanceView(ctx, oxide.InstanceViewParams{
+				Instance: oxide.NameOrId(id),
+			})
+			if err != nil {
+				return err
+			}
+			if instance.RunState != oxide.InstanceStateRunning {
+				return fmt.Errorf("instance (%v) is %v", instance.Name, instance.RunState)
+			}
+			if instance.Memory != memory {
+				return fmt.Errorf(
+					"instance (%v) has %v bytes of memory, expected %v",
+					instance.Name, instance.Memory, memory,
+				)
+			}
+			if !strings.HasPrefix(instance.Description, prefix) {
+				return fmt.Errorf(
+					"instance (%v) has description %q, expected a template hash",
+					instance.Name, instance.Description,
+				)
+			}
+			if description != "" && instance.Description != description {
+				return fmt.Errorf(
+					"instance (%v) has description %q, expected %q",
+					instance.Name, instance.Description, description,
+				)
+			}
+			description = instance.Description
+		}
+
+		return nil
+	}
+}
+
+func testAccResourceDestroy(s *terraform.State) error {
+	client, err := sharedtest.NewTestClient()
+	if err != nil {
+		return err
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "oxide_instance_group" {
+			continue
+		}
+
+		ctx := context.Background()
+		ctx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+
+		params := oxide.AntiAffinityGroupViewParams{
+			AntiAffinityGroup: oxide.NameOrId(rs.Primary.Attributes["anti_affinity_group_id"]),
+		}
+		res, err := client.AntiAffinityGroupView(ctx, params)
+		if err != nil && shared.Is404(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		return fmt.Errorf("instance group (%v) still exists", &res.Name)
+	}
+
+	// Members and their boot disks are deleted along with the group.
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "oxide_instance_group" {
+			continue
+		}
+
+		ctx := context.Background()
+		ctx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+
+		prefix := rs.Primary.Attributes["name"] + "-"
+		project := oxide.NameOrId(rs.Primary.Attributes["project_id"])
+
+		instances, err := client.InstanceListAllPages(ctx, oxide.InstanceListParams{
+			Project: project,
+			SortBy:  oxide.NameOrIdSortModeNameAscending,
+		})
+		if err != nil {
+			return err
+		}
+		for _, instance := range instances {
+			if strings.HasPrefix(string(instance.Name), prefix) {
+				return fmt.Errorf("instance group member (%v) still exists", instance.Name)
+			}
+		}
+
+		disks, err := client.DiskListAllPages(ctx, oxide.DiskListParams{
+			Project: project,
+			SortBy:  oxide.NameOrIdSortModeNameAscending,
+		})
+		if err != nil {
+			return err
+		}
+		for _, disk := range disks {
+			if strings.HasPrefix(string(disk.Name), prefix) {
+				return fmt.Errorf("instance group member disk (%v) still exists", disk.Name)
+			}
+		}
+	}
+
+	return nil
+}
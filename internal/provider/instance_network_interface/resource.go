@@ -0,0 +1,767 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package instancenetworkinterface
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-nettypes/cidrtypes"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/oxidecomputer/oxide.go/oxide"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/instance"
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/shared"
+	oxidevalidator "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/validator"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = (*Resource)(nil)
+	_ resource.ResourceWithConfigure   = (*Resource)(nil)
+	_ resource.ResourceWithImportState = (*Resource)(nil)
+	_ resource.ResourceWithModifyPlan  = (*Resource)(nil)
+)
+
+// NewResource is a helper function to simplify the provider
+// implementation.
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+// Resource is the resource implementation.
+type Resource struct {
+	client           *oxide.Client
+	defaultProjectID string
+}
+
+type ResourceModel struct {
+	ID           types.String                    `tfsdk:"id"`
+	InstanceID   types.String                    `tfsdk:"instance_id"`
+	Name         types.String                    `tfsdk:"name"`
+	Description  types.String                    `tfsdk:"description"`
+	SubnetID     types.String                    `tfsdk:"subnet_id"`
+	VPCID        types.String                    `tfsdk:"vpc_id"`
+	IPConfig     *instance.IPConfigResourceModel `tfsdk:"ip_config"`
+	IPStack      types.Object                    `tfsdk:"ip_stack"`
+	Primary      types.Bool                      `tfsdk:"primary"`
+	TransitIPs   types.Set                       `tfsdk:"transit_ips"`
+	MAC          types.String                    `tfsdk:"mac_address"`
+	TimeCreated  types.String                    `tfsdk:"time_created"`
+	TimeModified types.String                    `tfsdk:"time_modified"`
+	Timeouts     timeouts.Value                  `tfsdk:"timeouts"`
+}
+
+// Metadata returns the resource type name.
+func (r *Resource) Metadata(
+	_ context.Context,
+	req resource.MetadataRequest,
+	resp *resource.MetadataResponse,
+) {
+	resp.TypeName = "oxide_instance_network_interface"
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *Resource) Configure(
+	_ context.Context,
+	req resource.ConfigureRequest,
+	_ *resource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData := req.ProviderData.(*shared.ProviderData)
+	r.client = providerData.Client
+	r.defaultProjectID = providerData.DefaultProjectID
+}
+
+// ImportState imports an existing instance network interface into Terraform
+// state.
+func (r *Resource) ImportState(
+	ctx context.Context,
+	req resource.ImportStateRequest,
+	resp *resource.ImportStateResponse,
+) {
+	shared.ImportStateByPath(
+		ctx,
+		req,
+		resp,
+		path.Root("id"),
+		"project/instance/network_interface",
+		r.defaultProjectID,
+		func(ctx context.Context, names []string) (string, error) {
+			nic, err := r.client.InstanceNetworkInterfaceView(
+				ctx,
+				oxide.InstanceNetworkInterfaceViewParams{
+					Project:   oxide.NameOrId(names[0]),
+					Instance:  oxide.NameOrId(names[1]),
+					Interface: oxide.NameOrId(names[2]),
+				},
+			)
+			if err != nil {
+				return "", err
+			}
+			return nic.Id, nil
+		},
+	)
+}
+
+// ModifyPlan rejects primary = false for a network interface that will be the
+// first one of its instance, since the API always makes the first network
+// interface primary.
+func (r *Resource) ModifyPlan(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	// Only new network interfaces can become the first one.
+	if !req.State.Raw.IsNull() || req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var primary types.Bool
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("primary"), &primary)...)
+	var instanceID types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("instance_id"), &instanceID)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The instance may not exist yet when its ID is unknown, in which case
+	// Create checks again.
+	if primary.IsNull() || primary.IsUnknown() || primary.ValueBool() ||
+		instanceID.IsUnknown() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateSecondaryNIC(ctx, r.client, instanceID.ValueString())...)
+}
+
+// Schema defines the schema for the resource.
+func (r *Resource) Schema(
+	ctx context.Context,
+	_ resource.SchemaRequest,
+	resp *resource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `
+This resource manages a single network interface of an instance.
+` + instance.StopRequiredNotes(
+			"creating and deleting network interfaces, and changing the primary interface",
+			"Network interfaces",
+			"network_interfaces",
+			"it deletes them",
+		),
+		Attributes: map[string]schema.Attribute{
+			"instance_id": schema.StringAttribute{
+				Required:    true,
+				Description: "ID of the instance to which the network interface belongs.",
+				Validators: []validator.String{
+					oxidevalidator.IsUUID(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the instance network interface.",
+			},
+			"description": schema.StringAttribute{
+				Required:    true,
+				Description: "Description for the instance network interface.",
+			},
+			"subnet_id": schema.StringAttribute{
+				Required:    true,
+				Description: "ID of the VPC subnet in which to create the instance network interface.",
+				Validators: []validator.String{
+					oxidevalidator.IsUUID(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"vpc_id": schema.StringAttribute{
+				Required:    true,
+				Description: "ID of the VPC in which to create the instance network interface.",
+				Validators: []validator.String{
+					oxidevalidator.IsUUID(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ip_config": schema.SingleNestedAttribute{
+				Required:    true,
+				Description: "The IP stack configuration for this interface.",
+				Validators: []validator.Object{
+					instance.NetworkInterfaceIPConfigValidator(),
+				},
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplaceIf(
+						ipConfigRequiresReplace,
+						"Changing the IP configuration requires a new network interface, unless "+
+							"\"auto\" is changed to the assigned address or the other way around.",
+						"Changing the IP configuration requires a new network interface, unless "+
+							"`auto` is changed to the assigned address or the other way around.",
+					),
+				},
+				Attributes: map[string]schema.Attribute{
+					"v4": schema.SingleNestedAttribute{
+						Optional:    true,
+						Description: "Configuration for the instance network interface's IPv4 addressing.",
+						Attributes: map[string]schema.Attribute{
+							"ip": schema.StringAttribute{
+								Required: true,
+								Validators: []validator.String{
+									instance.IPConfigValidator(oxide.IpVersionV4),
+								},
+								Description: `The IPv4 address for the instance network interface or "auto" to auto-assign one.`,
+							},
+						},
+					},
+					"v6": schema.SingleNestedAttribute{
+						Optional:    true,
+						Description: "Configuration for the instance network interface's IPv6 addressing.",
+						Attributes: map[string]schema.Attribute{
+							"ip": schema.StringAttribute{
+								Required: true,
+								Validators: []validator.String{
+									instance.IPConfigValidator(oxide.IpVersionV6),
+								},
+								Description: `The IPv6 address for the instance network interface or "auto" to auto-assign one.`,
+							},
+						},
+					},
+				},
+			},
+			"primary": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Whether this is the primary interface of the instance. The first interface created for an instance is always the primary, so `primary` can't be `false` for it. Setting this to `true` makes this interface the primary; to make the current primary interface secondary, make another interface primary instead.",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"transit_ips": schema.SetAttribute{
+				Optional:    true,
+				Description: "Additional IP networks, in CIDR notation, this interface may send and receive traffic on. Each network must match an IP version configured in ip_config.",
+				ElementType: cidrtypes.IPPrefixType{},
+				Validators: []validator.Set{
+					instance.TransitIPsValidator(),
+				},
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique, immutable, system-controlled identifier of the instance network interface.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"mac_address": schema.StringAttribute{
+				Computed:    true,
+				Description: "MAC address assigned to the instance network interface.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ip_stack": schema.SingleNestedAttribute{
+				Computed:    true,
+				Description: "The VPC-private IP stack for this interface.",
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.UseStateForUnknown(),
+				},
+				Attributes: map[string]schema.Attribute{
+					"v4": schema.SingleNestedAttribute{
+						Computed:    true,
+						Description: "VPC-private IPv4 stack for the instance network interface.",
+						Attributes: map[string]schema.Attribute{
+							"ip": schema.StringAttribute{
+								Computed:    true,
+								Description: "VPC-private IPv4 address for the instance network interface.",
+							},
+						},
+					},
+					"v6": schema.SingleNestedAttribute{
+						Computed:    true,
+						Description: "VPC-private IPv6 stack for the instance network interface.",
+						Attributes: map[string]schema.Attribute{
+							"ip": schema.StringAttribute{
+								Computed:    true,
+								Description: "VPC-private IPv6 address for the instance network interface.",
+							},
+						},
+					},
+				},
+			},
+			"time_created": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp of when this instance network interface was created.",
+			},
+			"time_modified": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp of when this instance network interface was last modified.",
+			},
+		},
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *Resource) Create(
+	ctx context.Context,
+	req resource.CreateRequest,
+	resp *resource.CreateResponse,
+) {
+	var plan ResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	instanceID := plan.InstanceID.ValueString()
+
+	var nic *oxide.InstanceNetworkInterface
+	resp.Diagnostics.Append(instance.WithInstanceStopped(ctx, r.client, createTimeout, instanceID,
+		func() diag.Diagnostics {
+			// primary is only known to be false when it's configured.
+			if !plan.Primary.IsUnknown() && !plan.Primary.ValueBool() {
+				if diags := validateSecondaryNIC(ctx, r.client, instanceID); diags.HasError() {
+					return diags
+				}
+			}
+
+			var diags diag.Diagnostics
+			nic, diags = instance.CreateNIC(ctx, r.client, instance.NICResourceModel{
+				Name:        plan.Name,
+				Description: plan.Description,
+				SubnetID:    plan.SubnetID,
+				VPCID:       plan.VPCID,
+				IPConfig:    *plan.IPConfig,
+			}, instanceID)
+			if diags.HasError() {
+				return diags
+			}
+
+			// Primary and transit IPs can only be set by updating the
+			// network interface after it's created.
+			makePrimary := plan.Primary.ValueBool() && (nic.Primary == nil || !*nic.Primary)
+			if !makePrimary && len(plan.TransitIPs.Elements()) == 0 {
+				return nil
+			}
+
+			body, diags := plan.newUpdateBody(ctx)
+			if diags.HasError() {
+				return diags
+			}
+			nic, diags = instance.UpdateNIC(ctx, r.client, nic.Id, body)
+			return diags
+		},
+	)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("created instance network interface with ID: %v", nic.Id),
+		map[string]any{"success": true},
+	)
+
+	resp.Diagnostics.Append(plan.setNIC(ctx, nic)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save plan into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *Resource) Read(
+	ctx context.Context,
+	req resource.ReadRequest,
+	resp *resource.ReadResponse,
+) {
+	var state ResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	params := oxide.InstanceNetworkInterfaceViewParams{
+		Interface: oxide.NameOrId(state.ID.ValueString()),
+	}
+	nic, err := r.client.InstanceNetworkInterfaceView(ctx, params)
+	if err != nil {
+		if shared.Is404(err) {
+			// Remove resource from state during a refresh
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+			"Unable to read instance network interface:",
+			err,
+		))
+		return
+	}
+
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("read instance network interface with ID: %v", nic.Id),
+		map[string]any{"success": true},
+	)
+
+	resp.Diagnostics.Append(state.setNIC(ctx, nic)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *Resource) Update(
+	ctx context.Context,
+	req resource.UpdateRequest,
+	resp *resource.UpdateResponse,
+) {
+	var plan ResourceModel
+	var state ResourceModel
+
+	// Read Terraform plan data into the plan model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Read Terraform prior state data into the state model to retrieve ID
+	// which is a computed attribute, so it won't show up in the plan.
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	if state.Primary.ValueBool() && !plan.Primary.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("primary"),
+			"Unable to update instance network interface",
+			"The primary network interface can't be made secondary. Set primary to true on "+
+				"another network interface of the instance instead.",
+		)
+		return
+	}
+
+	var nic *oxide.InstanceNetworkInterface
+	update := func() diag.Diagnostics {
+		body, diags := plan.newUpdateBody(ctx)
+		if diags.HasError() {
+			return diags
+		}
+		nic, diags = instance.UpdateNIC(ctx, r.client, state.ID.ValueString(), body)
+		return diags
+	}
+
+	// Only changing the primary interface requires stopping the instance.
+	if plan.Primary.ValueBool() && !state.Primary.ValueBool() {
+		resp.Diagnostics.Append(instance.WithInstanceStopped(
+			ctx,
+			r.client,
+			updateTimeout,
+			state.InstanceID.ValueString(),
+			update,
+		)...)
+	} else {
+		resp.Diagnostics.Append(update()...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("updated instance network interface with ID: %v", nic.Id),
+		map[string]any{"success": true},
+	)
+
+	resp.Diagnostics.Append(plan.setNIC(ctx, nic)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save plan into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *Resource) Delete(
+	ctx context.Context,
+	req resource.DeleteRequest,
+	resp *resource.DeleteResponse,
+) {
+	var state ResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	resp.Diagnostics.Append(instance.WithInstanceStopped(
+		ctx,
+		r.client,
+		deleteTimeout,
+		state.InstanceID.ValueString(),
+		func() diag.Diagnostics {
+			return instance.DeleteNICs(ctx, r.client, []instance.AttachedNICResourceModel{
+				{ID: state.ID, Primary: state.Primary},
+			})
+		},
+	)...)
+}
+
+// validateSecondaryNIC checks that the instance already has a network
+// interface, so that a new one isn't made primary by the API.
+func validateSecondaryNIC(
+	ctx context.Context,
+	client *oxide.Client,
+	instanceID string,
+) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	nics, err := client.InstanceNetworkInterfaceList(ctx, oxide.InstanceNetworkInterfaceListParams{
+		Instance: oxide.NameOrId(instanceID),
+		Limit:    oxide.NewPointer(1),
+	})
+	if err != nil {
+		// The instance is checked again when the network interface is created.
+		if shared.Is404(err) {
+			return nil
+		}
+		diags.Append(shared.APIErrorDiagnostic(
+			"Unable to list instance network interfaces:",
+			err,
+		))
+		return diags
+	}
+
+	if len(nics.Items) == 0 {
+		diags.AddAttributeError(
+			path.Root("primary"),
+			"Invalid primary network interface",
+			"The first network interface of an instance is always primary, so primary "+
+				"can't be false. Remove primary or set it to true.",
+		)
+	}
+
+	return diags
+}
+
+// setNIC sets the model's attributes from the network interface read from the
+// API. The IP configuration is only set when it's missing, such as after an
+// import. An assigned address can't be told apart from an auto-assigned one,
+// so it's set to "auto", which ipConfigRequiresReplace treats as equal to the
+// address in ip_stack.
+func (m *ResourceModel) setNIC(
+	ctx context.Context,
+	nic *oxide.InstanceNetworkInterface,
+) diag.Diagnostics {
+	ipStack, err := instance.NewAttachedNetworkInterfacesIPStackResourceModel(nic.IpStack)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.Append(shared.APIErrorDiagnostic(
+			"Unable to read instance network interface:",
+			err,
+		))
+		return diags
+	}
+
+	ipStackType := instance.AttachedNICType.AttrTypes["ip_stack"].(types.ObjectType)
+	ipStackObject, diags := types.ObjectValueFrom(ctx, ipStackType.AttrTypes, ipStack)
+	if diags.HasError() {
+		return diags
+	}
+
+	if m.IPConfig == nil {
+		m.IPConfig = &instance.IPConfigResourceModel{}
+		if ipStack.V4 != nil {
+			m.IPConfig.V4 = &instance.IPConfigV4ResourceModel{
+				IP: types.StringValue(string(oxide.Ipv4AssignmentTypeAuto)),
+			}
+		}
+		if ipStack.V6 != nil {
+			m.IPConfig.V6 = &instance.IPConfigV6ResourceModel{
+				IP: types.StringValue(string(oxide.Ipv6AssignmentTypeAuto)),
+			}
+		}
+	}
+
+	// Only set the transit IPs if there are any to avoid drift.
+	transitIPs := instance.NewTransitIPs(nic.IpStack)
+	if len(transitIPs) > 0 || !m.TransitIPs.IsNull() {
+		m.TransitIPs, diags = types.SetValueFrom(ctx, cidrtypes.IPPrefixType{}, transitIPs)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	m.ID = types.StringValue(nic.Id)
+	m.InstanceID = types.StringValue(nic.InstanceId)
+	m.Name = types.StringValue(string(nic.Name))
+	m.Description = types.StringValue(nic.Description)
+	m.SubnetID = types.StringValue(nic.SubnetId)
+	m.VPCID = types.StringValue(nic.VpcId)
+	m.IPStack = ipStackObject
+	m.Primary = types.BoolPointerValue(nic.Primary)
+	m.MAC = types.StringValue(string(nic.Mac))
+	m.TimeCreated = types.StringValue(nic.TimeCreated.String())
+	m.TimeModified = types.StringValue(nic.TimeModified.String())
+
+	return nil
+}
+
+// ipConfigRequiresReplace requires a new network interface when the planned IP
+// configuration selects a different address than the one in state. "auto" and
+// the address assigned to the interface select the same address, so changing
+// between them, such as after an import, is an in-place update.
+func ipConfigRequiresReplace(
+	ctx context.Context,
+	req planmodifier.ObjectRequest,
+	resp *objectplanmodifier.RequiresReplaceIfFuncResponse,
+) {
+	resp.RequiresReplace = true
+	if req.PlanValue.IsUnknown() || req.StateValue.IsNull() {
+		return
+	}
+
+	var plan, state instance.IPConfigResourceModel
+	resp.Diagnostics.Append(req.PlanValue.As(ctx, &plan, basetypes.ObjectAsOptions{})...)
+	resp.Diagnostics.Append(req.StateValue.As(ctx, &state, basetypes.ObjectAsOptions{})...)
+	var ipStack instance.IPStackResourceModel
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("ip_stack"), &ipStack)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if (plan.V4 == nil) != (state.V4 == nil) || (plan.V6 == nil) != (state.V6 == nil) {
+		return
+	}
+	assignedV4, assignedV6 := types.StringNull(), types.StringNull()
+	if ipStack.V4 != nil {
+		assignedV4 = ipStack.V4.IP
+	}
+	if ipStack.V6 != nil {
+		assignedV6 = ipStack.V6.IP
+	}
+	if plan.V4 != nil && !sameIPAssignment(
+		state.V4.IP, plan.V4.IP, assignedV4, string(oxide.Ipv4AssignmentTypeAuto),
+	) {
+		return
+	}
+	if plan.V6 != nil && !sameIPAssignment(
+		state.V6.IP, plan.V6.IP, assignedV6, string(oxide.Ipv6AssignmentTypeAuto),
+	) {
+		return
+	}
+
+	resp.RequiresReplace = false
+}
+
+// sameIPAssignment reports whether the planned IP assignment, an address or
+// auto, is satisfied by the address assigned to the network interface.
+func sameIPAssignment(state, plan, assigned types.String, auto string) bool {
+	if plan.IsUnknown() {
+		return false
+	}
+	return plan.Equal(state) || plan.ValueString() == auto || plan.Equal(assigned)
+}
+
+// newUpdateBody returns the request body to update the network interface to
+// the model. The API rejects making the primary interface secondary, so the
+// primary flag is only sent to make an interface primary, which requires the
+// instance to be stopped.
+func (m ResourceModel) newUpdateBody(
+	ctx context.Context,
+) (oxide.InstanceNetworkInterfaceUpdate, diag.Diagnostics) {
+	transitIPs, diags := instance.NewTransitIPsUpdate(ctx, m.TransitIPs)
+	if diags.HasError() {
+		return oxide.InstanceNetworkInterfaceUpdate{}, diags
+	}
+
+	body := oxide.InstanceNetworkInterfaceUpdate{
+		Name:        oxide.Name(m.Name.ValueString()),
+		Description: m.Description.ValueString(),
+		TransitIps:  transitIPs,
+	}
+	if m.Primary.ValueBool() {
+		body.Primary = oxide.NewPointer(true)
+	}
+
+	return body, nil
+}
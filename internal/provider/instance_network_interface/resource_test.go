@@ -0,0 +1,328 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package instancenetworkinterface_test
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/oxidecomputer/oxide.go/oxide"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/shared"
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/sharedtest"
+)
+
+type networkInterfaceResourceConfig struct {
+	BlockName       string
+	InstanceName    string
+	SubnetName      string
+	SubnetIPv4Block string
+	NicName         string
+	NicDescription  string
+	TransitIPs      []string
+}
+
+var networkInterfaceResourceConfigTpl = `
+data "oxide_project" "test" {
+  name = "tf-acc-test"
+}
+
+data "oxide_vpc_subnet" "default" {
+  project_name = data.oxide_project.test.name
+  vpc_name     = "default"
+  name         = "default"
+}
+
+resource "oxide_vpc_subnet" "other" {
+  vpc_id      = data.oxide_vpc_subnet.default.vpc_id
+  name        = "{{.SubnetName}}"
+  description = "a subnet for a standalone nic"
+  ipv4_block  = "{{.SubnetIPv4Block}}"
+}
+
+resource "oxide_instance" "test" {
+  project_id    = data.oxide_project.test.id
+  description   = "a test instance"
+  name          = "{{.InstanceName}}"
+  hostname      = "terraform-acc-myhost"
+  memory        = 1073741824
+  ncpus         = 1
+  desired_state = "running"
+
+  ignore_unowned_network_interfaces = true
+  network_interfaces = [
+    {
+      subnet_id   = data.oxide_vpc_subnet.default.id
+      vpc_id      = data.oxide_vpc_subnet.default.vpc_id
+      description = "the primary nic"
+      name        = "net0"
+      ip_config = {
+        v4 = {
+          ip = "auto"
+        }
+      }
+    },
+  ]
+}
+
+resource "oxide_instance_network_interface" "{{.BlockName}}" {
+  instance_id = oxide_instance.test.id
+  subnet_id   = oxide_vpc_subnet.other.id
+  vpc_id      = oxide_vpc_subnet.other.vpc_id
+  name        = "{{.NicName}}"
+  description = "{{.NicDescription}}"
+  ip_config = {
+    v4 = {
+      ip = "auto"
+    }
+  }
+{{- if .TransitIPs}}
+  transit_ips = [{{range $i, $ip := .TransitIPs}}{{if $i}}, {{end}}"{{$ip}}"{{end}}]
+{{- end}}
+  timeouts = {
+    create = "5m"
+  }
+}
+`
+
+func TestAccCloudResourceInstanceNetworkInterface_full(t *testing.T) {
+	blockName := sharedtest.NewBlockName("instance-nic")
+	resourceName := fmt.Sprintf("oxide_instance_network_interface.%s", blockName)
+	nicName := sharedtest.NewResourceName()
+	cfg := networkInterfaceResourceConfig{
+		BlockName:       blockName,
+		InstanceName:    sharedtest.NewResourceName(),
+		SubnetName:      sharedtest.NewResourceName(),
+		SubnetIPv4Block: fmt.Sprintf("10.%d.%d.0/24", rand.IntN(255), rand.IntN(255)),
+		NicName:         nicName,
+		NicDescription:  "a standalone nic",
+	}
+
+	cfgUpdate := cfg
+	cfgUpdate.NicDescription = "an updated standalone nic"
+	cfgUpdate.TransitIPs = []string{"192.168.0.0/24"}
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { sharedtest.PreCheck(t) },
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		CheckDestroy:             testAccResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: sharedtest.ParsedAccConfig(t, cfg, networkInterfaceResourceConfigTpl),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkNetworkInterfaceResource(resourceName, nicName),
+					resource.TestCheckResourceAttr(resourceName, "description", "a standalone nic"),
+					resource.TestCheckResourceAttr(resourceName, "primary", "false"),
+					resource.TestCheckResourceAttr(resourceName, "transit_ips.#", "0"),
+					// The instance doesn't manage the standalone nic.
+					resource.TestCheckResourceAttr(
+						"oxide_instance.test", "network_interfaces.#", "1",
+					),
+					resource.TestCheckResourceAttr(
+						"oxide_instance.test", "desired_state", "running",
+					),
+					sharedtest.CheckInstanceRunState("oxide_instance.test", oxide.InstanceStateRunning),
+				),
+			},
+			{
+				Config: sharedtest.ParsedAccConfig(t, cfgUpdate, networkInterfaceResourceConfigTpl),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkNetworkInterfaceResource(resourceName, nicName),
+					resource.TestCheckResourceAttr(
+						resourceName, "description", "an updated standalone nic",
+					),
+					resource.TestCheckResourceAttr(resourceName, "primary", "false"),
+					resource.TestCheckResourceAttr(resourceName, "transit_ips.#", "1"),
+					resource.TestCheckTypeSetElemAttr(
+						resourceName, "transit_ips.*", "192.168.0.0/24",
+					),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeouts"},
+			},
+		},
+	})
+}
+
+type networkInterfacesResourceConfig struct {
+	InstanceName    string
+	SubnetName      string
+	SubnetIPv4Block string
+	NicName         string
+	WithNICs        bool
+}
+
+var networkInterfacesResourceConfigTpl = `
+data "oxide_project" "test" {
+  name = "tf-acc-test"
+}
+
+data "oxide_vpc_subnet" "default" {
+  project_name = data.oxide_project.test.name
+  vpc_name     = "default"
+  name         = "default"
+}
+
+resource "oxide_vpc_subnet" "other" {
+  vpc_id      = data.oxide_vpc_subnet.default.vpc_id
+  name        = "{{.SubnetName}}"
+  description = "a subnet for standalone nics"
+  ipv4_block  = "{{.SubnetIPv4Block}}"
+}
+
+resource "oxide_instance" "test" {
+  project_id    = data.oxide_project.test.id
+  description   = "a test instance"
+  name          = "{{.InstanceName}}"
+  hostname      = "terraform-acc-myhost"
+  memory        = 1073741824
+  ncpus         = 1
+  desired_state = "running"
+
+  ignore_unowned_network_interfaces = true
+  network_interfaces = [
+    {
+      subnet_id   = data.oxide_vpc_subnet.default.id
+      vpc_id      = data.oxide_vpc_subnet.default.vpc_id
+      description = "the primary nic"
+      name        = "net0"
+      ip_config = {
+        v4 = {
+          ip = "auto"
+        }
+      }
+    },
+  ]
+}
+{{- if .WithNICs}}
+
+resource "oxide_instance_network_interface" "a" {
+  instance_id = oxide_instance.test.id
+  subnet_id   = oxide_vpc_subnet.other.id
+  vpc_id      = oxide_vpc_subnet.other.vpc_id
+  name        = "{{.NicName}}-a"
+  description = "a standalone nic"
+  ip_config = {
+    v4 = {
+      ip = "auto"
+    }
+  }
+  timeouts = {
+    create = "5m"
+    delete = "5m"
+  }
+}
+
+resource "oxide_instance_network_interface" "b" {
+  instance_id = oxide_instance.test.id
+  subnet_id   = oxide_vpc_subnet.other.id
+  vpc_id      = oxide_vpc_subnet.other.vpc_id
+  name        = "{{.NicName}}-b"
+  description = "a standalone nic"
+  ip_config = {
+    v4 = {
+      ip = "auto"
+    }
+  }
+  timeouts = {
+    create = "5m"
+    delete = "5m"
+  }
+}
+{{- end}}
+`
+
+// TestAccCloudResourceInstanceNetworkInterface_parallel checks that network
+// interfaces of the same instance, which are created and deleted in parallel,
+// don't stop and start the instance under each other.
+func TestAccCloudResourceInstanceNetworkInterface_parallel(t *testing.T) {
+	cfg := networkInterfacesResourceConfig{
+		InstanceName:    sharedtest.NewResourceName(),
+		SubnetName:      sharedtest.NewResourceName(),
+		SubnetIPv4Block: fmt.Sprintf("10.%d.%d.0/24", rand.IntN(255), rand.IntN(255)),
+		NicName:         sharedtest.NewResourceName(),
+		WithNICs:        true,
+	}
+
+	cfgDelete := cfg
+	cfgDelete.WithNICs = false
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { sharedtest.PreCheck(t) },
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		CheckDestroy:             testAccResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: sharedtest.ParsedAccConfig(t, cfg, networkInterfacesResourceConfigTpl),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("oxide_instance_network_interface.a", "id"),
+					resource.TestCheckResourceAttrSet("oxide_instance_network_interface.b", "id"),
+					sharedtest.CheckInstanceRunState("oxide_instance.test", oxide.InstanceStateRunning),
+				),
+			},
+			{
+				Config: sharedtest.ParsedAccConfig(t, cfgDelete, networkInterfacesResourceConfigTpl),
+				Check: sharedtest.CheckInstanceRunState(
+					"oxide_instance.test", oxide.InstanceStateRunning,
+				),
+			},
+		},
+	})
+}
+
+func checkNetworkInterfaceResource(resourceName, nicName string) resource.TestCheckFunc {
+	return resource.ComposeAggregateTestCheckFunc([]resource.TestCheckFunc{
+		resource.TestCheckResourceAttrSet(resourceName, "id"),
+		resource.TestCheckResourceAttrPair(resourceName, "instance_id", "oxide_instance.test", "id"),
+		resource.TestCheckResourceAttr(resourceName, "name", nicName),
+		resource.TestCheckResourceAttrPair(resourceName, "subnet_id", "oxide_vpc_subnet.other", "id"),
+		resource.TestCheckResourceAttrPair(
+			resourceName, "vpc_id", "oxide_vpc_subnet.other", "vpc_id",
+		),
+		resource.TestCheckResourceAttr(resourceName, "ip_config.v4.ip", "auto"),
+		resource.TestCheckResourceAttrSet(resourceName, "ip_stack.v4.ip"),
+		resource.TestCheckResourceAttrSet(resourceName, "mac_address"),
+		resource.TestCheckResourceAttrSet(resourceName, "time_created"),
+		resource.TestCheckResourceAttrSet(resourceName, "time_modified"),
+	}...)
+}
+
+func testAccResourceDestroy(s *terraform.State) error {
+	client, err := sharedtest.NewTestClient()
+	if err != nil {
+		return err
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "oxide_instance_network_interface" {
+			continue
+		}
+
+		ctx := context.Background()
+		ctx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+
+		params := oxide.InstanceNetworkInterfaceViewParams{
+			Interface: oxide.NameOrId(rs.Primary.Attributes["id"]),
+		}
+		res, err := client.InstanceNetworkInterfaceView(ctx, params)
+		if err != nil && shared.Is404(err) {
+			continue
+		}
+
+		return fmt.Errorf("instance network interface (%v) still exists", &res.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,229 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package instanceserialconsole
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/oxidecomputer/oxide.go/oxide"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/shared"
+	oxidevalidator "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/validator"
+)
+
+// defaultMostRecent is the number of bytes read from the end of the serial
+// console buffer when most_recent isn't set.
+const defaultMostRecent = 16384
+
+var (
+	_ datasource.DataSource              = (*DataSource)(nil)
+	_ datasource.DataSourceWithConfigure = (*DataSource)(nil)
+)
+
+// NewDataSource initialises an instance serial console datasource
+func NewDataSource() datasource.DataSource {
+	return &DataSource{}
+}
+
+type DataSource struct {
+	client *oxide.Client
+}
+
+type DataSourceModel struct {
+	ID             types.String   `tfsdk:"id"`
+	InstanceID     types.String   `tfsdk:"instance_id"`
+	MostRecent     types.Int64    `tfsdk:"most_recent"`
+	Filter         types.String   `tfsdk:"filter"`
+	Output         types.String   `tfsdk:"output"`
+	LastByteOffset types.Int64    `tfsdk:"last_byte_offset"`
+	Timeouts       timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (d *DataSource) Metadata(
+	ctx context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = "oxide_instance_serial_console"
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *DataSource) Configure(
+	_ context.Context,
+	req datasource.ConfigureRequest,
+	_ *datasource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	d.client = req.ProviderData.(*shared.ProviderData).Client
+}
+
+func (d *DataSource) Schema(
+	ctx context.Context,
+	req datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `
+Retrieve the most recent serial console output of an instance.
+
+This can be used in checks and postconditions to assert that the guest reached a given point of its boot process.
+`,
+		Attributes: map[string]schema.Attribute{
+			"instance_id": schema.StringAttribute{
+				Required:    true,
+				Description: "ID of the instance to read the serial console output from.",
+				Validators: []validator.String{
+					oxidevalidator.IsUUID(),
+				},
+			},
+			"most_recent": schema.Int64Attribute{
+				Optional: true,
+				MarkdownDescription: fmt.Sprintf(
+					"Number of bytes to read from the end of the serial console output. Defaults to `%d`.",
+					defaultMostRecent,
+				),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"filter": schema.StringAttribute{
+				Optional:    true,
+				Description: "Regular expression used to filter the serial console output. Only lines that match are kept in `output`.",
+				Validators: []validator.String{
+					oxidevalidator.IsRegexp(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"output": schema.StringAttribute{
+				Computed:    true,
+				Description: "Serial console output of the instance. Invalid UTF-8 is replaced with `U+FFFD`.",
+			},
+			"last_byte_offset": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Offset of the last byte of the output in the serial console history of the instance.",
+			},
+			"timeouts": timeouts.Attributes(ctx),
+		},
+	}
+}
+
+func (d *DataSource) Read(
+	ctx context.Context,
+	req datasource.ReadRequest,
+	resp *datasource.ReadResponse,
+) {
+	var state DataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The filter is validated in the schema, but compile it before reading the
+	// console to avoid reading it for nothing.
+	var filter *regexp.Regexp
+	if !state.Filter.IsNull() {
+		var err error
+		filter, err = regexp.Compile(state.Filter.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("filter"),
+				"Invalid filter",
+				fmt.Sprintf("Unable to compile regular expression: %v", err),
+			)
+			return
+		}
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	mostRecent := int64(defaultMostRecent)
+	if !state.MostRecent.IsNull() {
+		mostRecent = state.MostRecent.ValueInt64()
+	}
+
+	params := oxide.InstanceSerialConsoleParams{
+		Instance:   oxide.NameOrId(state.InstanceID.ValueString()),
+		MostRecent: oxide.NewPointer(int(mostRecent)),
+	}
+	console, err := d.client.InstanceSerialConsole(ctx, params)
+	if err != nil {
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+			"Unable to read instance serial console:",
+			err,
+		))
+		return
+	}
+
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("read serial console from instance: %v", state.InstanceID.ValueString()),
+		map[string]any{"success": true},
+	)
+
+	// Set a unique ID for the datasource payload
+	state.ID = types.StringValue(uuid.New().String())
+
+	// Map response body to model
+	data := make([]byte, len(console.Data))
+	for i, b := range console.Data {
+		data[i] = byte(b)
+	}
+	// most_recent can cut the output in the middle of a multi-byte character,
+	// and guests can write any bytes to the serial console.
+	output := strings.ToValidUTF8(string(data), "\uFFFD")
+	state.Output = types.StringValue(filterLines(output, filter))
+	state.LastByteOffset = types.Int64Null()
+	if console.LastByteOffset != nil {
+		state.LastByteOffset = types.Int64Value(int64(*console.LastByteOffset))
+	}
+
+	// Save state into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// filterLines returns the lines of output that match filter. The output is
+// returned unchanged when there's no filter.
+func filterLines(output string, filter *regexp.Regexp) string {
+	if filter == nil {
+		return output
+	}
+
+	var lines []string
+	for line := range strings.Lines(output) {
+		line = strings.TrimRight(line, "\r\n")
+		if filter.MatchString(line) {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,155 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package instanceserialconsole_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/sharedtest"
+)
+
+const (
+	fakeInstanceID = "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+	fakeConsole    = "Booting…\r\ncloud-init: modules done\r\nlogin: "
+)
+
+type dataSourceConfig struct {
+	Host       string
+	BlockName  string
+	MostRecent int
+	Filter     string
+}
+
+var dataSourceConfigTpl = `
+provider "oxide" {
+  host  = "{{.Host}}"
+  token = "fake"
+}
+
+data "oxide_instance_serial_console" "{{.BlockName}}" {
+  instance_id = "` + fakeInstanceID + `"
+{{- if .MostRecent}}
+  most_recent = {{.MostRecent}}
+{{- end}}
+{{- if .Filter}}
+  filter      = "{{.Filter}}"
+{{- end}}
+}
+`
+
+func TestDataSourceInstanceSerialConsole_fakeAPI(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != fmt.Sprintf("/v1/instances/%s/serial-console", fakeInstanceID) {
+			http.NotFound(w, req)
+			return
+		}
+
+		// Serve the most recent bytes of the canned console output.
+		data := []byte(fakeConsole)
+		var mostRecent int
+		if _, err := fmt.Sscan(req.URL.Query().Get("most_recent"), &mostRecent); err != nil {
+			t.Errorf("invalid most_recent query parameter: %v", err)
+			http.Error(w, "invalid most_recent", http.StatusBadRequest)
+			return
+		}
+		if mostRecent < len(data) {
+			data = data[len(data)-mostRecent:]
+		}
+
+		// Bytes are encoded as a list of numbers, not as base64.
+		ints := make([]int, len(data))
+		for i, b := range data {
+			ints[i] = int(b)
+		}
+		body := map[string]any{
+			"data":             ints,
+			"last_byte_offset": len(fakeConsole),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(body); err != nil {
+			t.Errorf("failed to encode response: %v", err)
+		}
+	}))
+	t.Cleanup(func() {
+		ts.Close()
+	})
+
+	blockName := sharedtest.NewBlockName("datasource-instance-serial-console")
+	dataSourceName := fmt.Sprintf("data.oxide_instance_serial_console.%s", blockName)
+
+	//lintignore:AT004 // Provider must connect to test server.
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: sharedtest.ParsedAccConfig(t,
+					dataSourceConfig{
+						Host:      ts.URL,
+						BlockName: blockName,
+					},
+					dataSourceConfigTpl,
+				),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet(dataSourceName, "id"),
+					resource.TestCheckResourceAttr(dataSourceName, "output", fakeConsole),
+					resource.TestCheckResourceAttr(
+						dataSourceName, "last_byte_offset", fmt.Sprint(len(fakeConsole)),
+					),
+				),
+			},
+			{
+				Config: sharedtest.ParsedAccConfig(t,
+					dataSourceConfig{
+						Host:       ts.URL,
+						BlockName:  blockName,
+						MostRecent: 7,
+					},
+					dataSourceConfigTpl,
+				),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "output", "login: "),
+				),
+			},
+			{
+				// Cut the output in the middle of the multi-byte ellipsis.
+				Config: sharedtest.ParsedAccConfig(t,
+					dataSourceConfig{
+						Host:       ts.URL,
+						BlockName:  blockName,
+						MostRecent: len(fakeConsole) - len("Booting") - 1,
+					},
+					dataSourceConfigTpl,
+				),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						dataSourceName, "output", "\uFFFD"+fakeConsole[len("Booting…"):],
+					),
+				),
+			},
+			{
+				Config: sharedtest.ParsedAccConfig(t,
+					dataSourceConfig{
+						Host:      ts.URL,
+						BlockName: blockName,
+						Filter:    "^cloud-init:",
+					},
+					dataSourceConfigTpl,
+				),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						dataSourceName, "output", "cloud-init: modules done",
+					),
+				),
+			},
+		},
+	})
+}
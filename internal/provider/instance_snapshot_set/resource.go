@@ -0,0 +1,468 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package instancesnapshotset
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/oxidecomputer/oxide.go/oxide"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/instance"
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/shared"
+	oxidevalidator "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/validator"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource               = (*Resource)(nil)
+	_ resource.ResourceWithConfigure  = (*Resource)(nil)
+	_ resource.ResourceWithModifyPlan = (*Resource)(nil)
+)
+
+// snapshotSetIncompleteKey is the private state key set by Read when some of
+// the snapshots of the set no longer exist.
+const snapshotSetIncompleteKey = "incomplete"
+
+// NewResource is a helper function to simplify the provider implementation.
+func NewResource() resource.Resource {
+	return &Resource{}
+}
+
+// Resource is the resource implementation.
+type Resource struct {
+	client *oxide.Client
+}
+
+type ResourceModel struct {
+	ID           types.String   `tfsdk:"id"`
+	InstanceID   types.String   `tfsdk:"instance_id"`
+	Name         types.String   `tfsdk:"name"`
+	Description  types.String   `tfsdk:"description"`
+	StopInstance types.Bool     `tfsdk:"stop_instance"`
+	ProjectID    types.String   `tfsdk:"project_id"`
+	Snapshots    types.Map      `tfsdk:"snapshots"`
+	Timeouts     timeouts.Value `tfsdk:"timeouts"`
+}
+
+// Metadata returns the resource type name.
+func (r *Resource) Metadata(
+	_ context.Context,
+	req resource.MetadataRequest,
+	resp *resource.MetadataResponse,
+) {
+	resp.TypeName = "oxide_instance_snapshot_set"
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *Resource) Configure(
+	_ context.Context,
+	req resource.ConfigureRequest,
+	_ *resource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	r.client = req.ProviderData.(*shared.ProviderData).Client
+}
+
+// ModifyPlan replaces a set that lost some of its snapshots, so the remaining
+// ones are deleted before the set is taken again under the same names.
+func (r *Resource) ModifyPlan(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	incomplete, diags := req.Private.GetKey(ctx, snapshotSetIncompleteKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() || len(incomplete) == 0 {
+		return
+	}
+
+	resp.Diagnostics.Append(
+		resp.Plan.SetAttribute(ctx, path.Root("snapshots"), types.MapUnknown(types.StringType))...,
+	)
+	resp.RequiresReplace = append(resp.RequiresReplace, path.Root("snapshots"))
+}
+
+// Schema defines the schema for the resource.
+func (r *Resource) Schema(
+	ctx context.Context,
+	_ resource.SchemaRequest,
+	resp *resource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: shared.ReplaceBackticks(`
+This resource manages a set of snapshots of every disk attached to an instance, taken while the instance is stopped so they are consistent with each other.
+
+A running instance is stopped before the snapshots are taken and started again afterwards, unless ''stop_instance'' is ''false'', in which case the instance must already be stopped.
+
+Snapshots are named after the set and the disk they are taken from, such as ''nightly-data'' for the disk ''data'' in a set named ''nightly''. Names longer than 63 characters are shortened and end with a hash of the full name instead.
+
+If any of the snapshots is deleted outside of Terraform, the next apply deletes the remaining snapshots of the set and takes them all again.
+
+-> This resource currently only provides create, read and delete actions. An update requires a resource replacement.
+`),
+		Attributes: map[string]schema.Attribute{
+			// Snapshot sets aren't an API resource, so the ID is made of the
+			// instance ID and the name of the set.
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier for the snapshot set, in the format `instance_id/name`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"instance_id": schema.StringAttribute{
+				Required:    true,
+				Description: "ID of the instance whose disks are snapshotted.",
+				Validators: []validator.String{
+					oxidevalidator.IsUUID(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Name of the snapshot set. Snapshots are named after the set, followed by the name of the disk, and shortened to 63 characters if needed.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Required:    true,
+				Description: "Description for the snapshots.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"stop_instance": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Whether to stop a running instance while the snapshots are taken. When `false`, the instance must already be stopped. Defaults to `true`.",
+			},
+			"project_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "ID of the project that contains the instance and the snapshots.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"snapshots": schema.MapAttribute{
+				Computed:    true,
+				Description: "IDs of the snapshots, keyed by the name of the disk they were taken from.",
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *Resource) Create(
+	ctx context.Context,
+	req resource.CreateRequest,
+	resp *resource.CreateResponse,
+) {
+	var plan ResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := plan.Timeouts.Create(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	instanceID := plan.InstanceID.ValueString()
+	inst, err := r.client.InstanceView(ctx, oxide.InstanceViewParams{
+		Instance: oxide.NameOrId(instanceID),
+	})
+	if err != nil {
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+			"Unable to read instance:",
+			err,
+		))
+		return
+	}
+
+	if !plan.StopInstance.ValueBool() && inst.RunState != oxide.InstanceStateStopped {
+		resp.Diagnostics.AddError(
+			"Instance is not stopped",
+			fmt.Sprintf(
+				"Instance %s is %s. Stop it before taking snapshots, or set stop_instance to true.",
+				inst.Name,
+				inst.RunState,
+			),
+		)
+		return
+	}
+
+	disks, err := r.client.InstanceDiskList(ctx, oxide.InstanceDiskListParams{
+		Limit:    oxide.NewPointer(1000000000),
+		Instance: oxide.NameOrId(instanceID),
+	})
+	if err != nil {
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+			"Unable to list attached disks:",
+			err,
+		))
+		return
+	}
+
+	// The instance is started again after the snapshots are taken, even if
+	// some of them failed.
+	snapshots := map[string]string{}
+	resp.Diagnostics.Append(instance.WithInstanceStopped(ctx, r.client, createTimeout, instanceID,
+		func() diag.Diagnostics {
+			var diags diag.Diagnostics
+			for _, disk := range disks.Items {
+				snapshot, err := r.client.SnapshotCreate(ctx, oxide.SnapshotCreateParams{
+					Project: oxide.NameOrId(inst.ProjectId),
+					Body: &oxide.SnapshotCreate{
+						Description: plan.Description.ValueString(),
+						Name: oxide.Name(
+							snapshotSetSnapshotName(plan.Name.ValueString(), string(disk.Name)),
+						),
+						Disk: oxide.NameOrId(disk.Id),
+					},
+				})
+				if err != nil {
+					diags.Append(shared.APIErrorDiagnostic(
+						fmt.Sprintf("Error creating snapshot of disk %s", disk.Name),
+						err,
+					))
+					return diags
+				}
+				tflog.Trace(
+					ctx,
+					fmt.Sprintf("created snapshot with ID: %v", snapshot.Id),
+					map[string]any{"success": true},
+				)
+				snapshots[string(disk.Name)] = snapshot.Id
+			}
+			return diags
+		},
+	)...)
+
+	plan.ID = types.StringValue(fmt.Sprintf("%s/%s", instanceID, plan.Name.ValueString()))
+	plan.ProjectID = types.StringValue(inst.ProjectId)
+	plan.Snapshots, diags = types.MapValueFrom(ctx, types.StringType, snapshots)
+	resp.Diagnostics.Append(diags...)
+
+	// Save the snapshots taken so far even on failure, so the set is tainted
+	// instead of leaving snapshots out of the state.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("created snapshot set with ID: %v", plan.ID.ValueString()),
+		map[string]any{"success": true},
+	)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *Resource) Read(
+	ctx context.Context,
+	req resource.ReadRequest,
+	resp *resource.ReadResponse,
+) {
+	var state ResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	var snapshots map[string]string
+	resp.Diagnostics.Append(state.Snapshots.ElementsAs(ctx, &snapshots, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	incomplete := false
+	for diskName, id := range snapshots {
+		_, err := r.client.SnapshotView(ctx, oxide.SnapshotViewParams{
+			Snapshot: oxide.NameOrId(id),
+		})
+		if err != nil {
+			// Only the missing snapshot is dropped from the set, so the
+			// others are deleted when ModifyPlan replaces the set instead of
+			// keeping their names taken.
+			if shared.Is404(err) {
+				tflog.Warn(ctx, fmt.Sprintf(
+					"snapshot of disk %s with ID %v no longer exists",
+					diskName,
+					id,
+				))
+				delete(snapshots, diskName)
+				incomplete = true
+				continue
+			}
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+				"Unable to read snapshot:",
+				err,
+			))
+			return
+		}
+	}
+
+	if incomplete {
+		state.Snapshots, diags = types.MapValueFrom(ctx, types.StringType, snapshots)
+		resp.Diagnostics.Append(diags...)
+		resp.Diagnostics.Append(
+			resp.Private.SetKey(ctx, snapshotSetIncompleteKey, []byte("true"))...,
+		)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("read snapshot set with ID: %v", state.ID.ValueString()),
+		map[string]any{"success": true},
+	)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+// Only stop_instance and timeouts can change in-place; they only apply to
+// future operations.
+func (r *Resource) Update(
+	ctx context.Context,
+	req resource.UpdateRequest,
+	resp *resource.UpdateResponse,
+) {
+	var plan ResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *Resource) Delete(
+	ctx context.Context,
+	req resource.DeleteRequest,
+	resp *resource.DeleteResponse,
+) {
+	var state ResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, diags := state.Timeouts.Delete(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	var snapshots map[string]string
+	resp.Diagnostics.Append(state.Snapshots.ElementsAs(ctx, &snapshots, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, id := range snapshots {
+		params := oxide.SnapshotDeleteParams{
+			Snapshot: oxide.NameOrId(id),
+		}
+		if err := r.client.SnapshotDelete(ctx, params); err != nil {
+			if !shared.Is404(err) {
+				resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+					"Error deleting snapshot:",
+					err,
+				))
+				return
+			}
+		}
+		tflog.Trace(
+			ctx,
+			fmt.Sprintf("deleted snapshot with ID: %v", id),
+			map[string]any{"success": true},
+		)
+	}
+
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("deleted snapshot set with ID: %v", state.ID.ValueString()),
+		map[string]any{"success": true},
+	)
+}
+
+// snapshotSetSnapshotName returns the name of the snapshot of disk in set.
+// Names longer than the 63 characters allowed are truncated and end with a
+// hash of the full name, so the snapshots of a set keep distinct names.
+func snapshotSetSnapshotName(set string, disk string) string {
+	const maxLength = 63
+
+	name := set + "-" + disk
+	if len(name) <= maxLength {
+		return name
+	}
+
+	sum := sha256.Sum256([]byte(name))
+	hash := hex.EncodeToString(sum[:4])
+	return name[:maxLength-len(hash)-1] + "-" + hash
+}
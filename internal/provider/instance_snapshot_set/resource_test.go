@@ -0,0 +1,262 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package instancesnapshotset_test
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/oxidecomputer/oxide.go/oxide"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/shared"
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/sharedtest"
+)
+
+type snapshotSetResourceConfig struct {
+	BlockName    string
+	InstanceName string
+	DiskName     string
+	SetName      string
+	DesiredState string
+	StopInstance bool
+}
+
+var snapshotSetResourceConfigTpl = `
+data "oxide_project" "test" {
+  name = "tf-acc-test"
+}
+
+resource "oxide_disk" "data" {
+  project_id  = data.oxide_project.test.id
+  description = "a data disk"
+  name        = "{{.DiskName}}-data"
+  size        = 1073741824
+  block_size  = 512
+}
+
+resource "oxide_disk" "log" {
+  project_id  = data.oxide_project.test.id
+  description = "a log disk"
+  name        = "{{.DiskName}}-log"
+  size        = 1073741824
+  block_size  = 512
+}
+
+resource "oxide_instance" "test" {
+  project_id       = data.oxide_project.test.id
+  description      = "a test instance"
+  name             = "{{.InstanceName}}"
+  hostname         = "terraform-acc-myhost"
+  memory           = 1073741824
+  ncpus            = 1
+  desired_state    = "{{.DesiredState}}"
+  disk_attachments = [oxide_disk.data.id, oxide_disk.log.id]
+}
+
+resource "oxide_instance_snapshot_set" "{{.BlockName}}" {
+  instance_id   = oxide_instance.test.id
+  name          = "{{.SetName}}"
+  description   = "a test snapshot set"
+  stop_instance = {{.StopInstance}}
+  timeouts = {
+    create = "5m"
+    delete = "5m"
+  }
+}
+`
+
+func TestAccCloudResourceInstanceSnapshotSet_full(t *testing.T) {
+	blockName := sharedtest.NewBlockName("instance-snapshot-set")
+	resourceName := fmt.Sprintf("oxide_instance_snapshot_set.%s", blockName)
+	diskName := sharedtest.NewResourceName()
+	cfg := snapshotSetResourceConfig{
+		BlockName:    blockName,
+		InstanceName: sharedtest.NewResourceName(),
+		DiskName:     diskName,
+		SetName:      sharedtest.NewResourceName(),
+		DesiredState: "running",
+		StopInstance: true,
+	}
+
+	// The data snapshot is deleted outside of Terraform after the first step.
+	dataSnapshotKey := fmt.Sprintf("snapshots.%s-data", diskName)
+	var dataSnapshotID string
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { sharedtest.PreCheck(t) },
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		CheckDestroy:             testAccResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: sharedtest.ParsedAccConfig(t, cfg, snapshotSetResourceConfigTpl),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						resourceName, "instance_id", "oxide_instance.test", "id",
+					),
+					resource.TestCheckResourceAttrPair(
+						resourceName, "project_id", "data.oxide_project.test", "id",
+					),
+					resource.TestCheckResourceAttr(resourceName, "snapshots.%", "2"),
+					resource.TestCheckResourceAttrSet(
+						resourceName, fmt.Sprintf("snapshots.%s-data", diskName),
+					),
+					resource.TestCheckResourceAttrSet(
+						resourceName, fmt.Sprintf("snapshots.%s-log", diskName),
+					),
+					// The set and disk names add up to more than 63 characters.
+					testAccSnapshotSetNames(resourceName, cfg.SetName),
+					// The instance is started again after the snapshots are taken.
+					sharedtest.CheckInstanceRunState("oxide_instance.test", oxide.InstanceStateRunning),
+					func(s *terraform.State) error {
+						dataSnapshotID = s.RootModule().Resources[resourceName].Primary.Attributes[dataSnapshotKey]
+						return nil
+					},
+				),
+			},
+			{
+				// A set that lost one of its snapshots is taken again.
+				PreConfig: func() {
+					client, err := sharedtest.NewTestClient()
+					if err != nil {
+						t.Fatal(err)
+					}
+					err = client.SnapshotDelete(context.Background(), oxide.SnapshotDeleteParams{
+						Snapshot: oxide.NameOrId(dataSnapshotID),
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: sharedtest.ParsedAccConfig(t, cfg, snapshotSetResourceConfigTpl),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(
+							resourceName,
+							plancheck.ResourceActionDestroyBeforeCreate,
+						),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "snapshots.%", "2"),
+					resource.TestCheckResourceAttrWith(
+						resourceName, dataSnapshotKey, func(id string) error {
+							if id == dataSnapshotID {
+								return fmt.Errorf("expected a new snapshot, got %s", id)
+							}
+							return nil
+						},
+					),
+					testAccSnapshotSetNames(resourceName, cfg.SetName),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCloudResourceInstanceSnapshotSet_running(t *testing.T) {
+	cfg := snapshotSetResourceConfig{
+		BlockName:    sharedtest.NewBlockName("instance-snapshot-set"),
+		InstanceName: sharedtest.NewResourceName(),
+		DiskName:     sharedtest.NewResourceName(),
+		SetName:      sharedtest.NewResourceName(),
+		DesiredState: "running",
+		StopInstance: false,
+	}
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { sharedtest.PreCheck(t) },
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		CheckDestroy:             testAccResourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      sharedtest.ParsedAccConfig(t, cfg, snapshotSetResourceConfigTpl),
+				ExpectError: regexp.MustCompile(`Instance is not stopped`),
+			},
+		},
+	})
+}
+
+// testAccSnapshotSetNames checks that the snapshots of a set are named after
+// it and fit in 63 characters.
+func testAccSnapshotSetNames(resourceName, setName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", resourceName)
+		}
+
+		client, err := sharedtest.NewTestClient()
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		for key, id := range rs.Primary.Attributes {
+			if !regexp.MustCompile(`^snapshots\.[^%]+$`).MatchString(key) {
+				continue
+			}
+
+			snapshot, err := client.SnapshotView(ctx, oxide.SnapshotViewParams{
+				Snapshot: oxide.NameOrId(id),
+			})
+			if err != nil {
+				return err
+			}
+			if name := string(snapshot.Name); len(name) > 63 ||
+				!strings.HasPrefix(name, setName+"-") {
+				return fmt.Errorf("unexpected snapshot name %s in set %s", name, setName)
+			}
+		}
+
+		return nil
+	}
+}
+
+func testAccResourceDestroy(s *terraform.State) error {
+	client, err := sharedtest.NewTestClient()
+	if err != nil {
+		return err
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "oxide_instance_snapshot_set" {
+			continue
+		}
+
+		ctx := context.Background()
+		ctx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+
+		for key, id := range rs.Primary.Attributes {
+			if !regexp.MustCompile(`^snapshots\.[^%]+$`).MatchString(key) {
+				continue
+			}
+
+			params := oxide.SnapshotViewParams{
+				Snapshot: oxide.NameOrId(id),
+			}
+			res, err := client.SnapshotView(ctx, params)
+			if err != nil && shared.Is404(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			return fmt.Errorf("snapshot (%v) still exists", &res.Name)
+		}
+	}
+
+	return nil
+}
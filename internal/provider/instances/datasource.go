@@ -0,0 +1,445 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package instances
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/oxidecomputer/oxide.go/oxide"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/shared"
+	oxidevalidator "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/validator"
+)
+
+var (
+	_ datasource.DataSource              = (*DataSource)(nil)
+	_ datasource.DataSourceWithConfigure = (*DataSource)(nil)
+)
+
+// NewDataSource initialises an instances datasource
+func NewDataSource() datasource.DataSource {
+	return &DataSource{}
+}
+
+type DataSource struct {
+	client           *oxide.Client
+	defaultProjectID string
+}
+
+type DataSourceModel struct {
+	AffinityGroupID     types.String              `tfsdk:"affinity_group_id"`
+	AntiAffinityGroupID types.String              `tfsdk:"anti_affinity_group_id"`
+	ID                  types.String              `tfsdk:"id"`
+	IncludePrimaryIPs   types.Bool                `tfsdk:"include_primary_ips"`
+	Instances           []InstanceDataSourceModel `tfsdk:"instances"`
+	NameRegex           types.String              `tfsdk:"name_regex"`
+	ProjectName         types.String              `tfsdk:"project_name"`
+	RunState            types.String              `tfsdk:"run_state"`
+	Timeouts            timeouts.Value            `tfsdk:"timeouts"`
+}
+
+type InstanceDataSourceModel struct {
+	BootDiskID   types.String `tfsdk:"boot_disk_id"`
+	Description  types.String `tfsdk:"description"`
+	Hostname     types.String `tfsdk:"hostname"`
+	ID           types.String `tfsdk:"id"`
+	Memory       types.Int64  `tfsdk:"memory"`
+	Name         types.String `tfsdk:"name"`
+	NCPUs        types.Int64  `tfsdk:"ncpus"`
+	PrimaryIPv4  types.String `tfsdk:"primary_ipv4"`
+	PrimaryIPv6  types.String `tfsdk:"primary_ipv6"`
+	ProjectID    types.String `tfsdk:"project_id"`
+	RunState     types.String `tfsdk:"run_state"`
+	TimeCreated  types.String `tfsdk:"time_created"`
+	TimeModified types.String `tfsdk:"time_modified"`
+}
+
+func (d *DataSource) Metadata(
+	ctx context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = "oxide_instances"
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *DataSource) Configure(
+	_ context.Context,
+	req datasource.ConfigureRequest,
+	_ *datasource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData := req.ProviderData.(*shared.ProviderData)
+	d.client = providerData.Client
+	d.defaultProjectID = providerData.DefaultProjectID
+}
+
+func (d *DataSource) Schema(
+	ctx context.Context,
+	req datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: `
+Retrieve a list of all instances belonging to a project, optionally filtered by name, run state or anti-affinity group membership.
+`,
+		Attributes: map[string]schema.Attribute{
+			"project_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the project which contains the instances. Defaults to the provider's `default_project`.",
+			},
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Regular expression the instance names must match.",
+				Validators: []validator.String{
+					oxidevalidator.IsRegexp(),
+				},
+			},
+			"run_state": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only return instances in this run state, such as `running` or `stopped`.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						string(oxide.InstanceStateCreating),
+						string(oxide.InstanceStateStarting),
+						string(oxide.InstanceStateRunning),
+						string(oxide.InstanceStateStopping),
+						string(oxide.InstanceStateStopped),
+						string(oxide.InstanceStateRebooting),
+						string(oxide.InstanceStateMigrating),
+						string(oxide.InstanceStateRepairing),
+						string(oxide.InstanceStateFailed),
+						string(oxide.InstanceStateDestroyed),
+					),
+				},
+			},
+			"affinity_group_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return instances that are members of this affinity group.",
+				Validators: []validator.String{
+					oxidevalidator.IsUUID(),
+				},
+			},
+			"anti_affinity_group_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return instances that are members of this anti-affinity group.",
+				Validators: []validator.String{
+					oxidevalidator.IsUUID(),
+				},
+			},
+			"include_primary_ips": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Whether to set `primary_ipv4` and `primary_ipv6`. Reading the addresses takes one extra request per instance, so they are null unless this is `true`.",
+			},
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"timeouts": timeouts.Attributes(ctx),
+			"instances": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"boot_disk_id": schema.StringAttribute{
+							Computed:    true,
+							Description: "ID of the disk the instance is booted from, if any.",
+						},
+						"description": schema.StringAttribute{
+							Computed:    true,
+							Description: "Description of the instance.",
+						},
+						"hostname": schema.StringAttribute{
+							Computed:    true,
+							Description: "RFC1035-compliant hostname for the instance.",
+						},
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "Unique, immutable, system-controlled identifier of the instance.",
+						},
+						"memory": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The amount of RAM (in bytes) allocated to the instance.",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the instance.",
+						},
+						"ncpus": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The number of vCPUs allocated to the instance.",
+						},
+						"primary_ipv4": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "VPC-private IPv4 address of the instance's primary network interface, if any. Only set when `include_primary_ips` is `true`.",
+						},
+						"primary_ipv6": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "VPC-private IPv6 address of the instance's primary network interface, if any. Only set when `include_primary_ips` is `true`.",
+						},
+						"project_id": schema.StringAttribute{
+							Computed:    true,
+							Description: "ID of the project that contains the instance.",
+						},
+						"run_state": schema.StringAttribute{
+							Computed:    true,
+							Description: "Running state of the instance.",
+						},
+						"time_created": schema.StringAttribute{
+							Computed:    true,
+							Description: "Timestamp of when this instance was created.",
+						},
+						"time_modified": schema.StringAttribute{
+							Computed:    true,
+							Description: "Timestamp of when this instance was last modified.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *DataSource) Read(
+	ctx context.Context,
+	req datasource.ReadRequest,
+	resp *datasource.ReadResponse,
+) {
+	var state DataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	project, diags := shared.ProjectOrDefault(state.ProjectName, "project_name", d.defaultProjectID)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The regular expression has already been checked by the attribute
+	// validator.
+	var nameRegex *regexp.Regexp
+	if !state.NameRegex.IsNull() {
+		nameRegex = regexp.MustCompile(state.NameRegex.ValueString())
+	}
+
+	var members map[string]bool
+	if !state.AntiAffinityGroupID.IsNull() {
+		members, diags = d.antiAffinityGroupMembers(ctx, state.AntiAffinityGroupID.ValueString())
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	var affinityMembers map[string]bool
+	if !state.AffinityGroupID.IsNull() {
+		affinityMembers, diags = d.affinityGroupMembers(ctx, state.AffinityGroupID.ValueString())
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	params := oxide.InstanceListParams{
+		Project: project,
+		SortBy:  oxide.NameOrIdSortModeNameAscending,
+	}
+	instances, err := d.client.InstanceListAllPages(ctx, params)
+	if err != nil {
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+			"Unable to read instances:",
+			err,
+		))
+		return
+	}
+
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("read all instances from project: %v", project),
+		map[string]any{"success": true},
+	)
+
+	// Set a unique ID for the datasource payload
+	state.ID = types.StringValue(uuid.New().String())
+
+	// Map response body to model
+	for _, instance := range instances {
+		if nameRegex != nil && !nameRegex.MatchString(string(instance.Name)) {
+			continue
+		}
+		if !state.RunState.IsNull() && string(instance.RunState) != state.RunState.ValueString() {
+			continue
+		}
+		if members != nil && !members[instance.Id] {
+			continue
+		}
+		if affinityMembers != nil && !affinityMembers[instance.Id] {
+			continue
+		}
+
+		instanceState := InstanceDataSourceModel{
+			Description:  types.StringValue(instance.Description),
+			Hostname:     types.StringValue(instance.Hostname),
+			ID:           types.StringValue(instance.Id),
+			Memory:       types.Int64Value(int64(instance.Memory)),
+			Name:         types.StringValue(string(instance.Name)),
+			NCPUs:        types.Int64Value(int64(instance.Ncpus)),
+			ProjectID:    types.StringValue(instance.ProjectId),
+			RunState:     types.StringValue(string(instance.RunState)),
+			TimeCreated:  types.StringValue(instance.TimeCreated.String()),
+			TimeModified: types.StringValue(instance.TimeModified.String()),
+		}
+		if instance.BootDiskId != "" {
+			instanceState.BootDiskID = types.StringValue(instance.BootDiskId)
+		}
+
+		// Listing the network interfaces of every instance is expensive for
+		// large projects, so it's only done on request.
+		if state.IncludePrimaryIPs.ValueBool() {
+			instanceState.PrimaryIPv4, instanceState.PrimaryIPv6, diags = d.primaryIPs(
+				ctx,
+				instance.Id,
+			)
+			resp.Diagnostics.Append(diags...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+
+		state.Instances = append(state.Instances, instanceState)
+	}
+
+	// Save state into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// affinityGroupMembers returns the set of IDs of the instances that are
+// members of the given affinity group.
+func (d *DataSource) affinityGroupMembers(
+	ctx context.Context,
+	groupID string,
+) (map[string]bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	params := oxide.AffinityGroupMemberListParams{
+		AffinityGroup: oxide.NameOrId(groupID),
+	}
+	members, err := d.client.AffinityGroupMemberListAllPages(ctx, params)
+	if err != nil {
+		diags.Append(shared.APIErrorDiagnostic(
+			"Unable to read affinity group members:",
+			err,
+		))
+		return nil, diags
+	}
+
+	ids := make(map[string]bool, len(members))
+	for _, member := range members {
+		if m, ok := member.Value.(*oxide.AffinityGroupMemberInstance); ok {
+			ids[m.Value.Id] = true
+		}
+	}
+
+	return ids, nil
+}
+
+// antiAffinityGroupMembers returns the set of IDs of the instances that are
+// members of the given anti-affinity group.
+func (d *DataSource) antiAffinityGroupMembers(
+	ctx context.Context,
+	groupID string,
+) (map[string]bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	params := oxide.AntiAffinityGroupMemberListParams{
+		AntiAffinityGroup: oxide.NameOrId(groupID),
+	}
+	members, err := d.client.AntiAffinityGroupMemberListAllPages(ctx, params)
+	if err != nil {
+		diags.Append(shared.APIErrorDiagnostic(
+			"Unable to read anti-affinity group members:",
+			err,
+		))
+		return nil, diags
+	}
+
+	ids := make(map[string]bool, len(members))
+	for _, member := range members {
+		if m, ok := member.Value.(*oxide.AntiAffinityGroupMemberInstance); ok {
+			ids[m.Value.Id] = true
+		}
+	}
+
+	return ids, nil
+}
+
+// primaryIPs returns the VPC-private IPv4 and IPv6 addresses of the primary
+// network interface of an instance. An address is null when the interface
+// does not have one, or when the instance has no network interface.
+func (d *DataSource) primaryIPs(
+	ctx context.Context,
+	instanceID string,
+) (types.String, types.String, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	ipv4, ipv6 := types.StringNull(), types.StringNull()
+
+	params := oxide.InstanceNetworkInterfaceListParams{
+		Instance: oxide.NameOrId(instanceID),
+	}
+	nics, err := d.client.InstanceNetworkInterfaceListAllPages(ctx, params)
+	if err != nil {
+		diags.Append(shared.APIErrorDiagnostic(
+			"Unable to read instance network interfaces:",
+			err,
+		))
+		return ipv4, ipv6, diags
+	}
+
+	for _, nic := range nics {
+		if nic.Primary == nil || !*nic.Primary {
+			continue
+		}
+
+		switch s := nic.IpStack.Value.(type) {
+		case *oxide.PrivateIpStackV4:
+			ipv4 = types.StringValue(s.Value.Ip)
+		case *oxide.PrivateIpStackV6:
+			ipv6 = types.StringValue(s.Value.Ip)
+		case *oxide.PrivateIpStackDualStack:
+			ipv4 = types.StringValue(s.Value.V4.Ip)
+			ipv6 = types.StringValue(s.Value.V6.Ip)
+		}
+	}
+
+	return ipv4, ipv6, nil
+}
@@ -0,0 +1,146 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package instances_test
+
+import (
+	"testing"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/sharedtest"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+type dataSourceConfig struct {
+	InstanceName          string
+	AntiAffinityGroupName string
+}
+
+var dataSourceConfigTpl = `
+data "oxide_project" "test" {
+  name = "tf-acc-test"
+}
+
+data "oxide_vpc_subnet" "default" {
+  project_name = data.oxide_project.test.name
+  vpc_name     = "default"
+  name         = "default"
+}
+
+resource "oxide_anti_affinity_group" "test" {
+  project_id  = data.oxide_project.test.id
+  description = "a test anti-affinity group"
+  name        = "{{.AntiAffinityGroupName}}"
+  policy      = "allow"
+}
+
+resource "oxide_instance" "member" {
+  project_id           = data.oxide_project.test.id
+  description          = "a test instance"
+  name                 = "{{.InstanceName}}-a"
+  hostname             = "terraform-acc-myhost"
+  memory               = 1073741824
+  ncpus                = 1
+  start_on_create      = false
+  anti_affinity_groups = [oxide_anti_affinity_group.test.id]
+  network_interfaces = [
+    {
+      subnet_id   = data.oxide_vpc_subnet.default.id
+      vpc_id      = data.oxide_vpc_subnet.default.vpc_id
+      description = "a test nic"
+      name        = "net0"
+      ip_config = {
+        v4 = {
+          ip = "auto"
+        }
+      }
+    },
+  ]
+}
+
+resource "oxide_instance" "other" {
+  project_id      = data.oxide_project.test.id
+  description     = "a test instance"
+  name            = "{{.InstanceName}}-b"
+  hostname        = "terraform-acc-myhost"
+  memory          = 1073741824
+  ncpus           = 1
+  start_on_create = false
+}
+
+data "oxide_instances" "by_name" {
+  project_name = data.oxide_project.test.name
+  name_regex   = "^{{.InstanceName}}-"
+  run_state    = "stopped"
+  timeouts = {
+    read = "1m"
+  }
+
+  depends_on = [oxide_instance.member, oxide_instance.other]
+}
+
+data "oxide_instances" "by_group" {
+  project_name           = data.oxide_project.test.name
+  anti_affinity_group_id = oxide_anti_affinity_group.test.id
+  include_primary_ips    = true
+
+  depends_on = [oxide_instance.member, oxide_instance.other]
+}
+`
+
+func TestAccCloudDataSourceInstances_full(t *testing.T) {
+	instanceName := sharedtest.NewResourceName()
+	config := sharedtest.ParsedAccConfig(t,
+		dataSourceConfig{
+			InstanceName:          instanceName,
+			AntiAffinityGroupName: sharedtest.NewResourceName(),
+		},
+		dataSourceConfigTpl,
+	)
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { sharedtest.PreCheck(t) },
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkDataSourceByName("data.oxide_instances.by_name", instanceName),
+					checkDataSourceByGroup("data.oxide_instances.by_group", instanceName),
+				),
+			},
+		},
+	})
+}
+
+func checkDataSourceByName(dataName, instanceName string) resource.TestCheckFunc {
+	return resource.ComposeAggregateTestCheckFunc([]resource.TestCheckFunc{
+		resource.TestCheckResourceAttr(dataName, "timeouts.read", "1m"),
+		resource.TestCheckResourceAttrSet(dataName, "id"),
+		resource.TestCheckResourceAttr(dataName, "instances.#", "2"),
+		resource.TestCheckResourceAttr(dataName, "instances.0.name", instanceName+"-a"),
+		resource.TestCheckResourceAttr(dataName, "instances.0.run_state", "stopped"),
+		resource.TestCheckResourceAttr(dataName, "instances.0.ncpus", "1"),
+		resource.TestCheckResourceAttr(dataName, "instances.0.memory", "1073741824"),
+		resource.TestCheckResourceAttrSet(dataName, "instances.0.id"),
+		resource.TestCheckResourceAttrSet(dataName, "instances.0.project_id"),
+		resource.TestCheckResourceAttrSet(dataName, "instances.0.time_created"),
+		resource.TestCheckResourceAttrSet(dataName, "instances.0.time_modified"),
+		// The addresses are only read when include_primary_ips is set.
+		resource.TestCheckNoResourceAttr(dataName, "instances.0.primary_ipv4"),
+		resource.TestCheckResourceAttr(dataName, "instances.1.name", instanceName+"-b"),
+	}...)
+}
+
+func checkDataSourceByGroup(dataName, instanceName string) resource.TestCheckFunc {
+	return resource.ComposeAggregateTestCheckFunc([]resource.TestCheckFunc{
+		resource.TestCheckResourceAttrSet(dataName, "id"),
+		resource.TestCheckResourceAttr(dataName, "instances.#", "1"),
+		resource.TestCheckResourceAttrPair(
+			dataName, "instances.0.id", "oxide_instance.member", "id",
+		),
+		resource.TestCheckResourceAttr(dataName, "instances.0.name", instanceName+"-a"),
+		resource.TestCheckResourceAttrSet(dataName, "instances.0.primary_ipv4"),
+	}...)
+}
@@ -59,7 +59,7 @@ func (d *DataSource) Configure(
 		return
 	}
 
-	d.client = req.ProviderData.(*oxide.Client)
+	d.client = req.ProviderData.(*shared.ProviderData).Client
 }
 
 // Schema defines the schema for the data source.
@@ -129,10 +129,10 @@ func (d *DataSource) Read(
 	}
 	ipPool, err := d.client.IpPoolView(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read IP pool:",
-			err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -73,7 +73,7 @@ func (r *Resource) Configure(
 		return
 	}
 
-	r.client = req.ProviderData.(*oxide.Client)
+	r.client = req.ProviderData.(*shared.ProviderData).Client
 }
 
 func (r *Resource) ImportState(
@@ -81,7 +81,23 @@ func (r *Resource) ImportState(
 	req resource.ImportStateRequest,
 	resp *resource.ImportStateResponse,
 ) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	shared.ImportStateByPath(
+		ctx,
+		req,
+		resp,
+		path.Root("id"),
+		"ip_pool",
+		"",
+		func(ctx context.Context, names []string) (string, error) {
+			ipPool, err := r.client.SystemIpPoolView(ctx, oxide.SystemIpPoolViewParams{
+				Pool: oxide.NameOrId(names[0]),
+			})
+			if err != nil {
+				return "", err
+			}
+			return ipPool.Id, nil
+		},
+	)
 }
 
 // Schema defines the schema for the resource.
@@ -173,10 +189,10 @@ func (r *Resource) Create(
 	}
 	ipPool, err := r.client.SystemIpPoolCreate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error creating IP Pool",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -228,10 +244,10 @@ func (r *Resource) Read(
 		Pool: oxide.NameOrId(state.ID.ValueString()),
 	})
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read IP Pool:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -253,10 +269,10 @@ func (r *Resource) Read(
 	}
 	ipPoolRanges, err := r.client.SystemIpPoolRangeList(ctx, listParams)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read IP Pool ranges:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -359,10 +375,10 @@ func (r *Resource) Update(
 
 	ipPool, err := r.client.SystemIpPoolUpdate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error updating IP Pool",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -416,10 +432,10 @@ func (r *Resource) Delete(
 	)
 	if err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Error retrieving IP Pool ranges:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
@@ -437,10 +453,10 @@ func (r *Resource) Delete(
 		}
 		if err := r.client.SystemIpPoolRangeRemove(ctx, params); err != nil {
 			if !shared.Is404(err) {
-				resp.Diagnostics.AddError(
+				resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 					"Error deleting IP Pool range:",
-					"API error: "+err.Error(),
-				)
+					err,
+				))
 				return
 			}
 		}
@@ -457,10 +473,10 @@ func (r *Resource) Delete(
 			Pool: oxide.NameOrId(state.ID.ValueString()),
 		}); err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Error deleting IP Pool:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
@@ -499,10 +515,10 @@ func addRanges(
 
 		ipR, err := client.SystemIpPoolRangeAdd(ctx, params)
 		if err != nil {
-			diags.AddError(
+			diags.Append(shared.APIErrorDiagnostic(
 				"Error creating range within IP Pool",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return diags
 		}
 		tflog.Trace(
@@ -548,10 +564,10 @@ func removeRanges(
 
 		err = client.SystemIpPoolRangeRemove(ctx, params)
 		if err != nil {
-			diags.AddError(
+			diags.Append(shared.APIErrorDiagnostic(
 				"Error removing range within IP Pool",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return diags
 		}
 		tflog.Trace(
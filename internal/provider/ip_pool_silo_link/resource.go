@@ -68,7 +68,7 @@ func (r *Resource) Configure(
 		return
 	}
 
-	r.client = req.ProviderData.(*oxide.Client)
+	r.client = req.ProviderData.(*shared.ProviderData).Client
 }
 
 // ImportState imports an existing resource into Terraform state.
@@ -173,10 +173,10 @@ func (r *Resource) Create(
 	}
 	link, err := r.client.SystemIpPoolSiloLink(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error creating IP pool silo link",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -228,10 +228,10 @@ func (r *Resource) Read(
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read links:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -264,10 +264,10 @@ func (r *Resource) Read(
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read silo:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -326,10 +326,10 @@ func (r *Resource) Update(
 	}
 	link, err := r.client.SystemIpPoolSiloUpdate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error updating link",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -376,10 +376,10 @@ func (r *Resource) Delete(
 	}
 	if err := r.client.SystemIpPoolSiloUnlink(ctx, params); err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Error deleting link:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
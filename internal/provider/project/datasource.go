@@ -57,7 +57,7 @@ func (d *DataSource) Configure(
 		return
 	}
 
-	d.client = req.ProviderData.(*oxide.Client)
+	d.client = req.ProviderData.(*shared.ProviderData).Client
 }
 
 func (d *DataSource) Schema(
@@ -121,10 +121,10 @@ func (d *DataSource) Read(
 	}
 	project, err := d.client.ProjectView(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read project:",
-			err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
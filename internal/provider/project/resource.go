@@ -65,7 +65,7 @@ func (r *Resource) Configure(
 		return
 	}
 
-	r.client = req.ProviderData.(*oxide.Client)
+	r.client = req.ProviderData.(*shared.ProviderData).Client
 }
 
 // ImportState imports an existing project resource into Terraform state.
@@ -74,7 +74,23 @@ func (r *Resource) ImportState(
 	req resource.ImportStateRequest,
 	resp *resource.ImportStateResponse,
 ) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	shared.ImportStateByPath(
+		ctx,
+		req,
+		resp,
+		path.Root("id"),
+		"project",
+		"",
+		func(ctx context.Context, names []string) (string, error) {
+			project, err := r.client.ProjectView(ctx, oxide.ProjectViewParams{
+				Project: oxide.NameOrId(names[0]),
+			})
+			if err != nil {
+				return "", err
+			}
+			return project.Id, nil
+		},
+	)
 }
 
 // Schema defines the schema for the resource.
@@ -151,10 +167,10 @@ func (r *Resource) Create(
 	}
 	project, err := r.client.ProjectCreate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error creating project",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -207,10 +223,10 @@ func (r *Resource) Read(
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read project:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -271,10 +287,10 @@ func (r *Resource) Update(
 	}
 	project, err := r.client.ProjectUpdate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error updating project",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -325,10 +341,10 @@ func (r *Resource) Delete(
 	}
 	if err := r.client.VpcSubnetDelete(ctx, paramsSubnet); err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Error deleting default subnet:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
@@ -344,10 +360,10 @@ func (r *Resource) Delete(
 	}
 	if err := r.client.VpcDelete(ctx, paramsVPC); err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Error deleting default VPC:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
@@ -362,10 +378,10 @@ func (r *Resource) Delete(
 	}
 	if err := r.client.ProjectDelete(ctx, params); err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Error deleting project:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
@@ -63,7 +63,7 @@ func (d *DataSource) Configure(
 		return
 	}
 
-	d.client = req.ProviderData.(*oxide.Client)
+	d.client = req.ProviderData.(*shared.ProviderData).Client
 }
 
 // Schema defines the schema for the data source.
@@ -145,10 +145,10 @@ func (d *DataSource) Read(
 	}
 	projects, err := d.client.ProjectList(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read projects:",
-			err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/function"
@@ -21,7 +22,10 @@ import (
 	"github.com/oxidecomputer/oxide.go/oxide"
 
 	addresslot "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/address_lot"
+	affinitygroup "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/affinity_group"
 	antiaffinitygroup "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/anti_affinity_group"
+	antiaffinitygroupmember "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/anti_affinity_group_member"
+	cloudinit "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/cloud_init"
 	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/credentials"
 	currentuser "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/current_user"
 	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/disk"
@@ -31,14 +35,22 @@ import (
 	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/image"
 	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/images"
 	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/instance"
+	instancediskattachment "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/instance_disk_attachment"
 	instanceexternalips "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/instance_external_ips"
+	instancegroup "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/instance_group"
+	instancenetworkinterface "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/instance_network_interface"
+	instanceserialconsole "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/instance_serial_console"
+	instancesnapshotset "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/instance_snapshot_set"
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/instances"
 	ippool "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/ip_pool"
 	ippoolsilolink "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/ip_pool_silo_link"
 	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/project"
 	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/projects"
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/shared"
 	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/silo"
 	silosamlidp "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/silo_saml_identity_provider"
 	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/snapshot"
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/snapshots"
 	sshkey "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/ssh_key"
 	subnetpool "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/subnet_pool"
 	subnetpoolmember "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/subnet_pool_member"
@@ -47,6 +59,7 @@ import (
 	systemippool "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/system_ip_pool"
 	systemippools "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/system_ip_pools"
 	systemsubnetpools "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/system_subnet_pools"
+	oxidevalidator "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/validator"
 	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/vpc"
 	vpcfirewallrules "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/vpc_firewall_rules"
 	vpcinternetgateway "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/vpc_internet_gateway"
@@ -70,6 +83,12 @@ type oxideProviderModel struct {
 	Profile            types.String `tfsdk:"profile"`
 	ConfigDir          types.String `tfsdk:"config_dir"`
 	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+	MaxRetries         types.Int64  `tfsdk:"max_retries"`
+	RetryMaxBackoff    types.String `tfsdk:"retry_max_backoff"`
+	DefaultProject     types.String `tfsdk:"default_project"`
+	CABundle           types.String `tfsdk:"ca_bundle"`
+	ProxyURL           types.String `tfsdk:"proxy_url"`
+	Headers            types.Map    `tfsdk:"headers"`
 }
 
 // New initialises a new provider
@@ -140,6 +159,42 @@ to create, read, update, and delete Oxide resources.
 				Optional:            true,
 				MarkdownDescription: "Disables TLS certificate if `true`. This is insecure and should only be used for testing or in controlled environments.",
 			},
+			"ca_bundle": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "PEM encoded CA certificates used to verify the TLS certificate of the Oxide API, in addition to the system's trusted CAs. Either the path to a file or the certificates themselves. Conflicts with `insecure_skip_verify`.",
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.Expressions{
+						path.MatchRoot("insecure_skip_verify"),
+					}...),
+				},
+			},
+			"proxy_url": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "URL of the proxy used for requests to the Oxide API (e.g., http://proxy.example.com:3128). Defaults to the proxy set in the `HTTPS_PROXY` and `HTTP_PROXY` environment variables.",
+			},
+			"headers": schema.MapAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Additional HTTP headers to send with every request to the Oxide API. Headers set by the provider, such as `Authorization`, are not overridden.",
+			},
+			"default_project": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Name or ID of the project used by project-scoped resources and data sources that don't set `project_id` or `project_name`. The project is looked up when the provider is configured, and plans show its ID. Changing it replaces resources that rely on it.",
+			},
+			"max_retries": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Maximum number of times a request to the Oxide API is retried after a transient failure, such as a `429` or `503` response or a connection reset. Requests that may have modified a resource are only retried when the API guarantees they were not applied. Set to `0` to disable retries. Defaults to `3`.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_max_backoff": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Maximum time to wait between retries, as a [duration](https://pkg.go.dev/time#ParseDuration) such as `\"30s\"` or `\"2m\"`. The wait starts at one second and doubles on every retry, unless the API returns a `Retry-After` header. Defaults to `\"30s\"`.",
+				Validators: []validator.String{
+					oxidevalidator.IsDuration(),
+				},
+			},
 		},
 	}
 }
@@ -173,9 +228,19 @@ func (p *oxideProvider) Configure(
 	if dir := data.ConfigDir.ValueString(); dir != "" {
 		clientOpts = append(clientOpts, oxide.WithConfigDir(dir))
 	}
-	if data.InsecureSkipVerify.ValueBool() {
-		clientOpts = append(clientOpts, oxide.WithInsecureSkipVerify())
+
+	transport, diags := newTransport(data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	httpClient, diags := newHTTPClient(ctx, data, transport)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	clientOpts = append(clientOpts, oxide.WithHTTPClient(httpClient))
 
 	client, err := oxide.NewClient(clientOpts...)
 	if err != nil {
@@ -188,25 +253,64 @@ func (p *oxideProvider) Configure(
 
 	tflog.Info(ctx, "Configured Oxide client", map[string]any{"success": true})
 
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	providerData := &shared.ProviderData{
+		Client:         client,
+		DownloadClient: newDownloadClient(transport),
+	}
+
+	if data.DefaultProject.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("default_project"),
+			"Unknown default_project",
+			"The provider cannot resolve default_project because its value is not known until apply. "+
+				"Set it to a static value or remove it and set project_id on each resource instead.",
+		)
+		return
+	}
+	if defaultProject := data.DefaultProject.ValueString(); defaultProject != "" {
+		project, err := client.ProjectView(ctx, oxide.ProjectViewParams{
+			Project: oxide.NameOrId(defaultProject),
+		})
+		if err != nil {
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+				fmt.Sprintf("Unable to read default project %q", defaultProject),
+				err,
+			))
+			return
+		}
+		tflog.Trace(
+			ctx,
+			fmt.Sprintf("read default project with ID: %v", project.Id),
+			map[string]any{"success": true},
+		)
+		providerData.DefaultProjectID = project.Id
+	}
+
+	resp.DataSourceData = providerData
+	resp.ResourceData = providerData
 }
 
 // DataSources defines the data sources implemented in the provider.
 func (p *oxideProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		addresslot.NewDataSource,
+		affinitygroup.NewDataSource,
 		antiaffinitygroup.NewDataSource,
 		currentuser.NewDataSource,
 		disk.NewDataSource,
 		floatingip.NewDataSource,
 		image.NewDataSource,
 		images.NewDataSource,
+		instance.NewDataSource,
 		instanceexternalips.NewDataSource,
+		instances.NewDataSource,
+		instanceserialconsole.NewDataSource,
 		ippool.NewDataSource,
 		project.NewDataSource,
 		projects.NewDataSource,
 		silo.NewDataSource,
+		snapshot.NewDataSource,
+		snapshots.NewDataSource,
 		sshkey.NewDataSource,
 		subnetpool.NewDataSource,
 		systemippool.NewDataSource,
@@ -224,13 +328,19 @@ func (p *oxideProvider) DataSources(_ context.Context) []func() datasource.DataS
 func (p *oxideProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		addresslot.NewResource,
+		affinitygroup.NewResource,
 		antiaffinitygroup.NewResource,
+		antiaffinitygroupmember.NewResource,
 		disk.NewResource,
 		externalsubnetattachment.NewResource,
 		externalsubnet.NewResource,
 		floatingip.NewResource,
 		image.NewResource,
 		instance.NewResource,
+		instancediskattachment.NewResource,
+		instancegroup.NewResource,
+		instancenetworkinterface.NewResource,
+		instancesnapshotset.NewResource,
 		ippool.NewResource,
 		ippoolsilolink.NewResource,
 		project.NewResource,
@@ -254,6 +364,7 @@ func (p *oxideProvider) Resources(_ context.Context) []func() resource.Resource
 // Functions defines the functions implemented in the provider.
 func (p *oxideProvider) Functions(_ context.Context) []func() function.Function {
 	return []func() function.Function{
+		cloudinit.NewFunction,
 		credentials.NewFunction,
 	}
 }
@@ -5,6 +5,7 @@
 package provider_test
 
 import (
+	"encoding/pem"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -38,6 +39,9 @@ token_id = "f5660a9f-962e-4c00-a6dc-638256ae1d4e"
 user = "4dc4ba10-ab9e-403f-b08a-a7388df64e3a"
 `, testProfile, ts.URL(), profileToken)
 
+	caBundlePath := filepath.Join(configDir, "ca.pem")
+	require.NoError(t, os.WriteFile(caBundlePath, []byte(ts.CertificatePEM()), 0o600))
+
 	renderConfig := func(provider string, a ...any) string {
 		return fmt.Sprintf(`
 %s
@@ -144,6 +148,117 @@ provider "oxide" {}
 `),
 			expectError: regexp.MustCompile("tls: failed to verify"),
 		},
+		{
+			name: "default project",
+			preConfig: func(t *testing.T) {
+				t.Setenv("OXIDE_HOST", ts.URL())
+				t.Setenv("OXIDE_TOKEN", "env-token")
+			},
+			config: `
+provider "oxide" {
+  default_project = "test-project"
+}
+
+data "oxide_vpc" "test" {
+  name = "test-vpc"
+}`,
+			checkFunc: func(t *testing.T) {
+				req := ts.LastRequest()
+				require.Equal(t, "/v1/vpcs/test-vpc", req.URL.Path)
+				require.Equal(
+					t,
+					"3fa85f64-5717-4562-b3fc-2c963f66afa6",
+					req.URL.Query().Get("project"),
+				)
+			},
+		},
+		{
+			name: "ca bundle file",
+			preConfig: func(t *testing.T) {
+				t.Setenv("OXIDE_HOST", ts.URLTLS())
+				t.Setenv("OXIDE_TOKEN", "env-token")
+			},
+			config: renderConfig(`
+provider "oxide" {
+  ca_bundle = "%s"
+}
+`, caBundlePath),
+		},
+		{
+			name: "ca bundle inline",
+			preConfig: func(t *testing.T) {
+				t.Setenv("OXIDE_HOST", ts.URLTLS())
+				t.Setenv("OXIDE_TOKEN", "env-token")
+			},
+			config: renderConfig(`
+provider "oxide" {
+  ca_bundle = <<EOT
+%sEOT
+}
+`, ts.CertificatePEM()),
+		},
+		{
+			name: "ca bundle without certificates",
+			preConfig: func(t *testing.T) {
+				t.Setenv("OXIDE_HOST", ts.URLTLS())
+				t.Setenv("OXIDE_TOKEN", "env-token")
+			},
+			config: renderConfig(`
+provider "oxide" {
+  ca_bundle = "%s"
+}
+`, filepath.Join(configDir, "credentials.toml")),
+			expectError: regexp.MustCompile("no PEM encoded certificates found"),
+		},
+		{
+			name: "headers",
+			preConfig: func(t *testing.T) {
+				t.Setenv("OXIDE_HOST", ts.URL())
+				t.Setenv("OXIDE_TOKEN", "env-token")
+			},
+			config: renderConfig(`
+provider "oxide" {
+  headers = {
+    "X-Request-Source" = "terraform"
+    "Authorization"    = "Bearer header-token"
+  }
+}
+`),
+			checkFunc: func(t *testing.T) {
+				req := ts.LastRequest()
+				requireRequestHeader(t, req, "X-Request-Source", "terraform")
+				requireRequestToken(t, req, "env-token")
+			},
+		},
+		{
+			name: "proxy url",
+			preConfig: func(t *testing.T) {
+				t.Setenv("OXIDE_HOST", "http://oxide.example.invalid")
+				t.Setenv("OXIDE_TOKEN", "env-token")
+			},
+			config: renderConfig(`
+provider "oxide" {
+  proxy_url = "%s"
+}
+`, ts.URL()),
+			checkFunc: func(t *testing.T) {
+				req := ts.LastRequest()
+				require.Equal(t, "oxide.example.invalid", req.Host)
+			},
+		},
+		{
+			name: "invalid retry max backoff",
+			preConfig: func(t *testing.T) {
+				t.Setenv("OXIDE_HOST", ts.URL())
+				t.Setenv("OXIDE_TOKEN", "env-token")
+			},
+			config: renderConfig(`
+provider "oxide" {
+  retry_max_backoff = "-5s"
+}
+`),
+			expectError: regexp.MustCompile("Invalid duration"),
+		},
 		{
 			name: "profile conflicts with host and token",
 			preConfig: func(t *testing.T) {
@@ -223,6 +338,14 @@ func (t *testProviderServer) URLTLS() string {
 	return t.testServerTLS.URL
 }
 
+// CertificatePEM returns the PEM encoded certificate of the TLS server.
+func (t *testProviderServer) CertificatePEM() string {
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: t.testServerTLS.Certificate().Raw,
+	}))
+}
+
 func (t *testProviderServer) handleRequest(w http.ResponseWriter, r *http.Request) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
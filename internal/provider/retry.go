@@ -0,0 +1,263 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// defaultMaxRetries is the number of times a failed request is retried
+	// when max_retries is not set.
+	defaultMaxRetries = 3
+
+	// defaultRetryMaxBackoff is the longest the provider waits between
+	// retries when retry_max_backoff is not set.
+	defaultRetryMaxBackoff = 30 * time.Second
+
+	// retryMinBackoff is the wait before the first retry. It doubles on
+	// every subsequent attempt up to the configured maximum.
+	retryMinBackoff = time.Second
+)
+
+// retryTransport is an [http.RoundTripper] that retries requests to the Oxide
+// API that failed for transient reasons.
+//
+// Requests using idempotent methods are retried on network errors and on 429,
+// 502, 503 and 504 responses. POST requests are only retried when the
+// response guarantees the request was not acted upon: 429 and 503 responses,
+// conflicts where the API asks the client to try again, and connections that
+// could not be established.
+type retryTransport struct {
+	next       http.RoundTripper
+	maxRetries int
+	maxBackoff time.Duration
+
+	// sleep waits for d or until the request context is done. It's a field so
+	// tests can avoid waiting.
+	sleep func(req *http.Request, d time.Duration) error
+}
+
+func newRetryTransport(
+	next http.RoundTripper,
+	maxRetries int,
+	maxBackoff time.Duration,
+) *retryTransport {
+	return &retryTransport{
+		next:       next,
+		maxRetries: maxRetries,
+		maxBackoff: maxBackoff,
+		sleep:      sleepContext,
+	}
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		// Each attempt after the first needs a fresh copy of the request body.
+		attemptReq := req
+		if attempt > 0 && hasBody(req) {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to rewind request body for retry: %w", err)
+			}
+			attemptReq = req.Clone(ctx)
+			attemptReq.Body = body
+		}
+
+		resp, err := t.next.RoundTrip(attemptReq)
+
+		retry, reason := t.shouldRetry(req, resp, err)
+		if !retry || attempt >= t.maxRetries {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+		tflog.Warn(ctx, "Retrying Oxide API request after transient failure", map[string]any{
+			"method":  req.Method,
+			"url":     req.URL.String(),
+			"reason":  reason,
+			"attempt": attempt + 1,
+			"wait":    wait.String(),
+		})
+
+		// Drain and close the body so the connection can be reused.
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := t.sleep(req, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// shouldRetry reports whether the request should be retried given the result
+// of the last attempt, and a short description of why.
+func (t *retryTransport) shouldRetry(
+	req *http.Request,
+	resp *http.Response,
+	err error,
+) (bool, string) {
+	// The body can't be replayed, so there's nothing to retry with.
+	if hasBody(req) && req.GetBody == nil {
+		return false, ""
+	}
+
+	// The request was cancelled or timed out on our side.
+	if req.Context().Err() != nil {
+		return false, ""
+	}
+
+	idempotent := isIdempotentMethod(req.Method)
+
+	if err != nil {
+		if isConnectionRefused(err) {
+			// The request never reached the API, so it's always safe to
+			// send it again.
+			return true, err.Error()
+		}
+		if idempotent && isTransientNetworkError(err) {
+			return true, err.Error()
+		}
+		return false, ""
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true, resp.Status
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent, resp.Status
+	case http.StatusConflict:
+		if isTryAgainConflict(resp) {
+			return true, resp.Status
+		}
+	}
+
+	return false, ""
+}
+
+// backoff returns how long to wait before the next attempt. The Retry-After
+// response header is honored when present. The wait is never longer than the
+// configured maximum backoff.
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return min(d, t.maxBackoff)
+		}
+	}
+
+	wait := retryMinBackoff << attempt
+	if wait <= 0 || wait > t.maxBackoff {
+		wait = t.maxBackoff
+	}
+
+	// Add up to 20% of jitter so parallel applies don't retry in lockstep.
+	jitter := time.Duration(rand.Int64N(int64(wait)/5 + 1))
+	return min(wait+jitter, t.maxBackoff)
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which may be a
+// number of seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
+
+func hasBody(req *http.Request) bool {
+	return req.Body != nil && req.Body != http.NoBody
+}
+
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func isConnectionRefused(err error) bool {
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+func isTransientNetworkError(err error) bool {
+	if errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// isTryAgainConflict reports whether a 409 response was caused by a
+// concurrent operation on the same object, such as a running saga, in which
+// case the API asks the client to try again later. The response body is
+// restored so it can still be read by the caller.
+func isTryAgainConflict(resp *http.Response) bool {
+	if resp.Body == nil {
+		return false
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+
+	var apiErr struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &apiErr); err != nil {
+		return false
+	}
+
+	return strings.Contains(strings.ToLower(apiErr.Message), "try again")
+}
+
+func sleepContext(req *http.Request, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-req.Context().Done():
+		return req.Context().Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,219 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package provider
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_retryTransport(t *testing.T) {
+	testCases := []struct {
+		name         string
+		method       string
+		responses    []int
+		body         string
+		retryAfter   string
+		wantStatus   int
+		wantAttempts int
+		wantWait     time.Duration
+	}{
+		{
+			name:         "no retry on success",
+			method:       http.MethodGet,
+			responses:    []int{http.StatusOK},
+			wantStatus:   http.StatusOK,
+			wantAttempts: 1,
+		},
+		{
+			name:         "retry on 503",
+			method:       http.MethodGet,
+			responses:    []int{503, 503, http.StatusOK},
+			wantStatus:   http.StatusOK,
+			wantAttempts: 3,
+		},
+		{
+			name:         "retry post on 429",
+			method:       http.MethodPost,
+			responses:    []int{http.StatusTooManyRequests, http.StatusOK},
+			wantStatus:   http.StatusOK,
+			wantAttempts: 2,
+		},
+		{
+			name:         "no retry of post on 502",
+			method:       http.MethodPost,
+			responses:    []int{http.StatusBadGateway, http.StatusOK},
+			wantStatus:   http.StatusBadGateway,
+			wantAttempts: 1,
+		},
+		{
+			name:         "retry get on 502",
+			method:       http.MethodGet,
+			responses:    []int{http.StatusBadGateway, http.StatusOK},
+			wantStatus:   http.StatusOK,
+			wantAttempts: 2,
+		},
+		{
+			name:         "no retry on 400",
+			method:       http.MethodGet,
+			responses:    []int{http.StatusBadRequest, http.StatusOK},
+			wantStatus:   http.StatusBadRequest,
+			wantAttempts: 1,
+		},
+		{
+			name:         "retry on try again conflict",
+			method:       http.MethodPost,
+			responses:    []int{http.StatusConflict, http.StatusOK},
+			body:         `{"error_code":"ObjectAlreadyExists","message":"saga in progress, try again later"}`,
+			wantStatus:   http.StatusOK,
+			wantAttempts: 2,
+		},
+		{
+			name:         "no retry on conflict",
+			method:       http.MethodPost,
+			responses:    []int{http.StatusConflict, http.StatusOK},
+			body:         `{"error_code":"ObjectAlreadyExists","message":"already exists: project \"test\""}`,
+			wantStatus:   http.StatusConflict,
+			wantAttempts: 1,
+		},
+		{
+			name:         "give up after max retries",
+			method:       http.MethodGet,
+			responses:    []int{503, 503, 503, 503, 503},
+			wantStatus:   http.StatusServiceUnavailable,
+			wantAttempts: 4,
+		},
+		{
+			name:         "honor retry after",
+			method:       http.MethodGet,
+			responses:    []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter:   "7",
+			wantStatus:   http.StatusOK,
+			wantAttempts: 2,
+			wantWait:     7 * time.Second,
+		},
+		{
+			name:         "cap retry after",
+			method:       http.MethodGet,
+			responses:    []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter:   "3600",
+			wantStatus:   http.StatusOK,
+			wantAttempts: 2,
+			wantWait:     defaultRetryMaxBackoff,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(attempts.Add(1)) - 1
+
+				// Every attempt must send the full request body.
+				if r.Method == http.MethodPost {
+					body, err := io.ReadAll(r.Body)
+					assert.NoError(t, err)
+					assert.Equal(t, `{"name":"test"}`, string(body))
+				}
+
+				status := tc.responses[min(n, len(tc.responses)-1)]
+				if tc.retryAfter != "" && status != http.StatusOK {
+					w.Header().Set("Retry-After", tc.retryAfter)
+				}
+				w.WriteHeader(status)
+				if status != http.StatusOK {
+					io.WriteString(w, tc.body)
+				}
+			}))
+			defer ts.Close()
+
+			var waits []time.Duration
+			transport := newRetryTransport(
+				http.DefaultTransport,
+				defaultMaxRetries,
+				defaultRetryMaxBackoff,
+			)
+			transport.sleep = func(_ *http.Request, d time.Duration) error {
+				waits = append(waits, d)
+				return nil
+			}
+			client := &http.Client{Transport: transport}
+
+			var body io.Reader
+			if tc.method == http.MethodPost {
+				body = strings.NewReader(`{"name":"test"}`)
+			}
+			req, err := http.NewRequest(tc.method, ts.URL, body)
+			require.NoError(t, err)
+
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tc.wantStatus, resp.StatusCode)
+			assert.Equal(t, tc.wantAttempts, int(attempts.Load()))
+			assert.Len(t, waits, tc.wantAttempts-1)
+			for _, wait := range waits {
+				assert.LessOrEqual(t, wait, defaultRetryMaxBackoff)
+			}
+			if tc.wantWait != 0 {
+				assert.Equal(t, tc.wantWait, waits[0])
+			}
+
+			// The body of the last response must still be readable.
+			if tc.wantStatus != http.StatusOK {
+				got, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.Equal(t, tc.body, string(got))
+			}
+		})
+	}
+}
+
+func Test_retryTransport_connectionRefused(t *testing.T) {
+	// Grab a free port and close the listener so nothing is listening on it.
+	ts := httptest.NewServer(http.NotFoundHandler())
+	url := ts.URL
+	ts.Close()
+
+	var waits int
+	transport := newRetryTransport(http.DefaultTransport, 2, defaultRetryMaxBackoff)
+	transport.sleep = func(_ *http.Request, _ time.Duration) error {
+		waits++
+		return nil
+	}
+	client := &http.Client{Transport: transport}
+
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(`{}`))
+	require.NoError(t, err)
+
+	_, err = client.Do(req)
+	require.Error(t, err)
+	assert.Equal(t, 2, waits)
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	d, ok := parseRetryAfter("5")
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Second, d)
+
+	d, ok = parseRetryAfter(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), d)
+
+	_, ok = parseRetryAfter("")
+	assert.False(t, ok)
+
+	_, ok = parseRetryAfter("soon")
+	assert.False(t, ok)
+}
@@ -0,0 +1,203 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/oxidecomputer/oxide.go/oxide"
+)
+
+// APIErrorKind classifies an error returned by the Oxide API.
+type APIErrorKind string
+
+const (
+	// APIErrorKindUnknown is used for errors that are not structured Oxide API
+	// errors, such as network failures, or for status codes that don't have a
+	// more specific kind.
+	APIErrorKindUnknown APIErrorKind = "unknown"
+
+	// APIErrorKindNotFound is used when the requested object does not exist.
+	APIErrorKindNotFound APIErrorKind = "not_found"
+
+	// APIErrorKindConflict is used when the request conflicts with the current
+	// state of the object, such as a name already in use or an instance in the
+	// wrong run state.
+	APIErrorKindConflict APIErrorKind = "conflict"
+
+	// APIErrorKindQuotaExceeded is used when the silo or fleet does not have
+	// enough capacity left to satisfy the request.
+	APIErrorKindQuotaExceeded APIErrorKind = "quota_exceeded"
+
+	// APIErrorKindValidation is used when the API rejected the request body or
+	// parameters.
+	APIErrorKindValidation APIErrorKind = "validation"
+
+	// APIErrorKindPermission is used when the credentials are missing or don't
+	// grant access to the requested object.
+	APIErrorKindPermission APIErrorKind = "permission"
+
+	// APIErrorKindServer is used for errors caused by the Oxide control plane
+	// itself.
+	APIErrorKindServer APIErrorKind = "server"
+)
+
+// insufficientCapacityErrorCode is the error code returned by Nexus when a
+// silo quota or the physical capacity of the rack would be exceeded.
+const insufficientCapacityErrorCode = "InsufficientCapacity"
+
+// APIError is the structured form of an error returned by the Oxide API.
+type APIError struct {
+	Kind       APIErrorKind
+	StatusCode int
+	ErrorCode  string
+	Message    string
+	RequestID  string
+}
+
+// ParseAPIError extracts the structured Oxide API error from err. It returns
+// false if err is not, and does not wrap, an Oxide API HTTP error.
+func ParseAPIError(err error) (APIError, bool) {
+	var httpErr *oxide.HTTPError
+	if !errors.As(err, &httpErr) || httpErr == nil || httpErr.HTTPResponse == nil {
+		return APIError{}, false
+	}
+
+	apiErr := APIError{
+		StatusCode: httpErr.HTTPResponse.StatusCode,
+	}
+	if httpErr.ErrorResponse != nil {
+		apiErr.ErrorCode = httpErr.ErrorResponse.ErrorCode
+		apiErr.Message = httpErr.ErrorResponse.Message
+		apiErr.RequestID = httpErr.ErrorResponse.RequestId
+	}
+	apiErr.Kind = classifyAPIError(apiErr.StatusCode, apiErr.ErrorCode)
+
+	return apiErr, true
+}
+
+func classifyAPIError(statusCode int, errorCode string) APIErrorKind {
+	if errorCode == insufficientCapacityErrorCode {
+		return APIErrorKindQuotaExceeded
+	}
+
+	switch {
+	case statusCode == http.StatusNotFound:
+		return APIErrorKindNotFound
+	case statusCode == http.StatusConflict:
+		return APIErrorKindConflict
+	case statusCode == http.StatusInsufficientStorage:
+		return APIErrorKindQuotaExceeded
+	case statusCode == http.StatusBadRequest:
+		return APIErrorKindValidation
+	case statusCode == http.StatusUnauthorized, statusCode == http.StatusForbidden:
+		return APIErrorKindPermission
+	case statusCode >= http.StatusInternalServerError:
+		return APIErrorKindServer
+	default:
+		return APIErrorKindUnknown
+	}
+}
+
+// APIErrorKindOf returns the kind of the Oxide API error wrapped in err, or
+// [APIErrorKindUnknown] if err is not an Oxide API error.
+func APIErrorKindOf(err error) APIErrorKind {
+	apiErr, ok := ParseAPIError(err)
+	if !ok {
+		return APIErrorKindUnknown
+	}
+	return apiErr.Kind
+}
+
+// Is404 returns true if err is an Oxide API error for an object that doesn't
+// exist.
+func Is404(err error) bool {
+	return APIErrorKindOf(err) == APIErrorKindNotFound
+}
+
+// IsConflict returns true if err is an Oxide API error caused by a conflict
+// with the current state of an object.
+func IsConflict(err error) bool {
+	return APIErrorKindOf(err) == APIErrorKindConflict
+}
+
+// IsQuotaExceeded returns true if err is an Oxide API error caused by a
+// silo quota or the rack capacity being exhausted.
+func IsQuotaExceeded(err error) bool {
+	return APIErrorKindOf(err) == APIErrorKindQuotaExceeded
+}
+
+// IsValidationError returns true if err is an Oxide API error caused by an
+// invalid request.
+func IsValidationError(err error) bool {
+	return APIErrorKindOf(err) == APIErrorKindValidation
+}
+
+// IsServerError returns true if err is an Oxide API error caused by the
+// control plane itself.
+func IsServerError(err error) bool {
+	return APIErrorKindOf(err) == APIErrorKindServer
+}
+
+// APIErrorDiagnostic returns an error diagnostic for err. If err is an Oxide
+// API error, the diagnostic detail describes the kind of failure and includes
+// the request ID so it can be matched against the control plane logs.
+//
+//	resp.Diagnostics.Append(shared.APIErrorDiagnostic("Unable to read disk:", err))
+func APIErrorDiagnostic(summary string, err error) diag.Diagnostic {
+	return diag.NewErrorDiagnostic(summary, APIErrorDetail(err))
+}
+
+// APIErrorDetail returns the diagnostic detail used by [APIErrorDiagnostic].
+func APIErrorDetail(err error) string {
+	apiErr, ok := ParseAPIError(err)
+	if !ok {
+		return "API error: " + err.Error()
+	}
+
+	var detail strings.Builder
+	detail.WriteString(apiErrorKindDescription(apiErr.Kind))
+	detail.WriteString("\n\n")
+
+	fmt.Fprintf(&detail, "Status: %d", apiErr.StatusCode)
+	if apiErr.ErrorCode != "" {
+		fmt.Fprintf(&detail, " %s", apiErr.ErrorCode)
+	}
+	detail.WriteString("\n")
+	if apiErr.Message != "" {
+		fmt.Fprintf(&detail, "Message: %s\n", apiErr.Message)
+	}
+	if apiErr.RequestID != "" {
+		fmt.Fprintf(&detail, "Request ID: %s\n", apiErr.RequestID)
+	}
+
+	detail.WriteString("\nAPI error: " + err.Error())
+	return detail.String()
+}
+
+func apiErrorKindDescription(kind APIErrorKind) string {
+	switch kind {
+	case APIErrorKindNotFound:
+		return "The requested object was not found. It may have been deleted outside of Terraform."
+	case APIErrorKindConflict:
+		return "The request conflicts with the current state of the object. " +
+			"Another operation may be in progress, or the name may already be in use."
+	case APIErrorKindQuotaExceeded:
+		return "There is not enough capacity to satisfy the request. " +
+			"Check the silo quotas and the available capacity of the rack."
+	case APIErrorKindValidation:
+		return "The Oxide API rejected the request as invalid."
+	case APIErrorKindPermission:
+		return "The credentials used by the provider are not allowed to perform this request."
+	case APIErrorKindServer:
+		return "The Oxide API encountered an internal error while processing the request."
+	default:
+		return "The Oxide API returned an unexpected error."
+	}
+}
@@ -0,0 +1,130 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package shared
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/oxidecomputer/oxide.go/oxide"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAPIError returns the error produced by the Oxide SDK when the API
+// responds with the given status code and error code.
+func newAPIError(t *testing.T, statusCode int, errorCode string) error {
+	t.Helper()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		fmt.Fprintf(w, `{
+  "request_id": "a0ad4c3c-2cb9-4a6b-8f0c-8e8d6d8e0b5f",
+  "error_code": %q,
+  "message": "something went wrong"
+}`, errorCode)
+	}))
+	t.Cleanup(ts.Close)
+
+	client, err := oxide.NewClient(oxide.WithHost(ts.URL), oxide.WithToken("fake"))
+	require.NoError(t, err)
+
+	_, err = client.ProjectView(context.Background(), oxide.ProjectViewParams{
+		Project: oxide.NameOrId("test-project"),
+	})
+	require.Error(t, err)
+
+	return err
+}
+
+func Test_ParseAPIError(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		errorCode  string
+		want       APIErrorKind
+	}{
+		{
+			name:       "not found",
+			statusCode: http.StatusNotFound,
+			errorCode:  "ObjectNotFound",
+			want:       APIErrorKindNotFound,
+		},
+		{
+			name:       "conflict",
+			statusCode: http.StatusConflict,
+			errorCode:  "ObjectAlreadyExists",
+			want:       APIErrorKindConflict,
+		},
+		{
+			name:       "quota exceeded",
+			statusCode: http.StatusInsufficientStorage,
+			errorCode:  "InsufficientCapacity",
+			want:       APIErrorKindQuotaExceeded,
+		},
+		{
+			name:       "validation",
+			statusCode: http.StatusBadRequest,
+			errorCode:  "InvalidValue",
+			want:       APIErrorKindValidation,
+		},
+		{
+			name:       "permission",
+			statusCode: http.StatusForbidden,
+			errorCode:  "Forbidden",
+			want:       APIErrorKindPermission,
+		},
+		{
+			name:       "server",
+			statusCode: http.StatusServiceUnavailable,
+			errorCode:  "ServiceUnavailable",
+			want:       APIErrorKindServer,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newAPIError(t, tt.statusCode, tt.errorCode)
+
+			apiErr, ok := ParseAPIError(err)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, apiErr.Kind)
+			assert.Equal(t, tt.statusCode, apiErr.StatusCode)
+			assert.Equal(t, tt.errorCode, apiErr.ErrorCode)
+			assert.Equal(t, "something went wrong", apiErr.Message)
+			assert.Equal(t, "a0ad4c3c-2cb9-4a6b-8f0c-8e8d6d8e0b5f", apiErr.RequestID)
+
+			// Classification must survive error wrapping.
+			wrapped := fmt.Errorf("while doing something: %w", err)
+			assert.Equal(t, tt.want, APIErrorKindOf(wrapped))
+		})
+	}
+}
+
+func Test_Is404(t *testing.T) {
+	assert.True(t, Is404(newAPIError(t, http.StatusNotFound, "ObjectNotFound")))
+	assert.False(t, Is404(newAPIError(t, http.StatusBadRequest, "InvalidValue")))
+
+	// Errors that aren't structured API errors are never classified, even if
+	// their message looks like one.
+	assert.False(t, Is404(errors.New("Status: 404 ObjectNotFound")))
+}
+
+func Test_APIErrorDiagnostic(t *testing.T) {
+	err := newAPIError(t, http.StatusConflict, "ObjectAlreadyExists")
+
+	d := APIErrorDiagnostic("Error creating project", err)
+	assert.Equal(t, "Error creating project", d.Summary())
+	assert.Contains(t, d.Detail(), "conflicts with the current state")
+	assert.Contains(t, d.Detail(), "Status: 409 ObjectAlreadyExists")
+	assert.Contains(t, d.Detail(), "Request ID: a0ad4c3c-2cb9-4a6b-8f0c-8e8d6d8e0b5f")
+
+	d = APIErrorDiagnostic("Error creating project", errors.New("connection refused"))
+	assert.Equal(t, "API error: connection refused", d.Detail())
+}
@@ -0,0 +1,63 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package shared
+
+import (
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/oxidecomputer/oxide.go/oxide"
+)
+
+// ImageFilter selects images by name, OS and version. The zero value matches
+// every image.
+type ImageFilter struct {
+	NameRegex *regexp.Regexp
+	OS        string
+	Version   string
+}
+
+// NewImageFilter returns a filter for the name_regex, os and version
+// attributes of a data source. Null attributes match every image. The regular
+// expression must already be validated.
+func NewImageFilter(nameRegex, os, version types.String) ImageFilter {
+	var f ImageFilter
+	if !nameRegex.IsNull() {
+		f.NameRegex = regexp.MustCompile(nameRegex.ValueString())
+	}
+	f.OS = os.ValueString()
+	f.Version = version.ValueString()
+	return f
+}
+
+// Apply returns the images that match the filter, in the same order.
+func (f ImageFilter) Apply(images []oxide.Image) []oxide.Image {
+	var matches []oxide.Image
+	for _, image := range images {
+		if f.NameRegex != nil && !f.NameRegex.MatchString(string(image.Name)) {
+			continue
+		}
+		if f.OS != "" && image.Os != f.OS {
+			continue
+		}
+		if f.Version != "" && image.Version != f.Version {
+			continue
+		}
+		matches = append(matches, image)
+	}
+	return matches
+}
+
+// MostRecentImage returns the most recently created image. It panics if
+// images is empty.
+func MostRecentImage(images []oxide.Image) oxide.Image {
+	newest := images[0]
+	for _, image := range images[1:] {
+		if image.TimeCreated.After(*newest.TimeCreated) {
+			newest = image
+		}
+	}
+	return newest
+}
@@ -0,0 +1,83 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package shared
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/oxidecomputer/oxide.go/oxide"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageFilter_Apply(t *testing.T) {
+	images := []oxide.Image{
+		{Name: "ubuntu-24-04-a", Os: "ubuntu", Version: "24.04"},
+		{Name: "ubuntu-22-04", Os: "ubuntu", Version: "22.04"},
+		{Name: "ubuntu-24-04-b", Os: "ubuntu", Version: "24.04"},
+		{Name: "alpine", Os: "alpine", Version: "3.22"},
+	}
+
+	tests := []struct {
+		name      string
+		nameRegex types.String
+		os        types.String
+		version   types.String
+		want      []oxide.Name
+	}{
+		{
+			name:      "no filters",
+			nameRegex: types.StringNull(),
+			os:        types.StringNull(),
+			version:   types.StringNull(),
+			want:      []oxide.Name{"ubuntu-24-04-a", "ubuntu-22-04", "ubuntu-24-04-b", "alpine"},
+		},
+		{
+			name:      "name regex",
+			nameRegex: types.StringValue("-b$"),
+			os:        types.StringNull(),
+			version:   types.StringNull(),
+			want:      []oxide.Name{"ubuntu-24-04-b"},
+		},
+		{
+			name:      "os and version",
+			nameRegex: types.StringNull(),
+			os:        types.StringValue("ubuntu"),
+			version:   types.StringValue("24.04"),
+			want:      []oxide.Name{"ubuntu-24-04-a", "ubuntu-24-04-b"},
+		},
+		{
+			name:      "no match",
+			nameRegex: types.StringValue("^ubuntu"),
+			os:        types.StringValue("alpine"),
+			version:   types.StringNull(),
+			want:      nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []oxide.Name
+			for _, image := range NewImageFilter(tt.nameRegex, tt.os, tt.version).Apply(images) {
+				got = append(got, image.Name)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMostRecentImage(t *testing.T) {
+	now := time.Now()
+	older := now.Add(-time.Hour)
+	oldest := now.Add(-2 * time.Hour)
+
+	images := []oxide.Image{
+		{Name: "older", TimeCreated: &older},
+		{Name: "newest", TimeCreated: &now},
+		{Name: "oldest", TimeCreated: &oldest},
+	}
+
+	assert.Equal(t, oxide.Name("newest"), MostRecentImage(images).Name)
+}
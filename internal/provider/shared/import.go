@@ -0,0 +1,73 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package shared
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+// ImportLookupFunc returns the ID of the object identified by names, which has
+// one element for each segment of the import path format.
+type ImportLookupFunc func(ctx context.Context, names []string) (string, error)
+
+// ImportStateByPath imports a resource using either its ID or a path of names
+// separated by "/", such as "my-project/my-instance". The path must match
+// format, such as "project/instance", and is resolved to an ID with lookup.
+// The ID is stored in the attribute at idPath.
+//
+// When the first segment of format is "project", it may be omitted from the
+// path if the provider sets default_project, in which case the default
+// project ID is used.
+func ImportStateByPath(
+	ctx context.Context,
+	req resource.ImportStateRequest,
+	resp *resource.ImportStateResponse,
+	idPath path.Path,
+	format string,
+	defaultProjectID string,
+	lookup ImportLookupFunc,
+) {
+	if _, err := uuid.Parse(req.ID); err == nil {
+		resource.ImportStatePassthroughID(ctx, idPath, req, resp)
+		return
+	}
+
+	segments := strings.Split(format, "/")
+	names := strings.Split(req.ID, "/")
+	if len(names) == len(segments)-1 && segments[0] == "project" && defaultProjectID != "" {
+		names = append([]string{defaultProjectID}, names...)
+	}
+
+	valid := len(names) == len(segments)
+	for _, name := range names {
+		if name == "" {
+			valid = false
+		}
+	}
+	if !valid {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("Expected import ID format: ID or %s, got: %s", format, req.ID),
+		)
+		return
+	}
+
+	id, err := lookup(ctx, names)
+	if err != nil {
+		resp.Diagnostics.Append(APIErrorDiagnostic(
+			fmt.Sprintf("Unable to find %s to import", req.ID),
+			err,
+		))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, idPath, id)...)
+}
@@ -0,0 +1,114 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package shared
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ImportStateByPath(t *testing.T) {
+	const (
+		instanceID       = "a0ad4c3c-2cb9-4a6b-8f0c-8e8d6d8e0b5f"
+		defaultProjectID = "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+	)
+
+	tests := []struct {
+		name             string
+		id               string
+		defaultProjectID string
+		wantNames        []string
+		wantID           string
+		wantErr          bool
+	}{
+		{
+			name:   "id",
+			id:     instanceID,
+			wantID: instanceID,
+		},
+		{
+			name:      "path",
+			id:        "my-project/my-instance",
+			wantNames: []string{"my-project", "my-instance"},
+			wantID:    instanceID,
+		},
+		{
+			name:             "path in default project",
+			id:               "my-instance",
+			defaultProjectID: defaultProjectID,
+			wantNames:        []string{defaultProjectID, "my-instance"},
+			wantID:           instanceID,
+		},
+		{
+			name:    "path without default project",
+			id:      "my-instance",
+			wantErr: true,
+		},
+		{
+			name:    "path with empty segment",
+			id:      "my-project/",
+			wantErr: true,
+		},
+		{
+			name:    "path with too many segments",
+			id:      "my-project/my-vpc/my-instance",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			s := schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{Computed: true},
+				},
+			}
+			req := resource.ImportStateRequest{ID: tt.id}
+			resp := &resource.ImportStateResponse{
+				State: tfsdk.State{
+					Schema: s,
+					Raw:    tftypes.NewValue(s.Type().TerraformType(ctx), nil),
+				},
+			}
+
+			var gotNames []string
+			lookup := func(_ context.Context, names []string) (string, error) {
+				gotNames = names
+				return instanceID, nil
+			}
+
+			ImportStateByPath(
+				ctx,
+				req,
+				resp,
+				path.Root("id"),
+				"project/instance",
+				tt.defaultProjectID,
+				lookup,
+			)
+			require.Equal(t, tt.wantErr, resp.Diagnostics.HasError(), resp.Diagnostics)
+			if tt.wantErr {
+				return
+			}
+
+			assert.Equal(t, tt.wantNames, gotNames)
+
+			var id types.String
+			resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root("id"), &id)...)
+			require.False(t, resp.Diagnostics.HasError())
+			assert.Equal(t, tt.wantID, id.ValueString())
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package shared
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/oxidecomputer/oxide.go/oxide"
+)
+
+// ProviderData is passed by the provider to every resource and data source
+// when it's configured.
+type ProviderData struct {
+	// Client is the Oxide API client.
+	Client *oxide.Client
+
+	// DownloadClient is the HTTP client used to download files from servers
+	// other than the Oxide API. It uses the TLS and proxy settings of the
+	// provider.
+	DownloadClient *http.Client
+
+	// DefaultProjectID is the ID of the project set in the provider's
+	// default_project attribute, or empty if it's not set.
+	DefaultProjectID string
+}
+
+// ModifyPlanForDefaultProject sets the planned value of the project_id
+// attribute to the provider's default project when it's not set in the
+// configuration, so plans show the project the resource will be created in.
+//
+// Resources using it must mark project_id as Optional and Computed, and use
+// stringplanmodifier.RequiresReplaceIfConfigured. Changing the default project
+// replaces resources that rely on it.
+func ModifyPlanForDefaultProject(
+	ctx context.Context,
+	defaultProjectID string,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	// Nothing to do when the resource is being destroyed.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var configProjectID types.String
+	resp.Diagnostics.Append(
+		req.Config.GetAttribute(ctx, path.Root("project_id"), &configProjectID)...,
+	)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !configProjectID.IsNull() {
+		return
+	}
+
+	if defaultProjectID == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("project_id"),
+			"Missing project_id",
+			"The project_id attribute must be set when the provider does not set default_project.",
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(
+		resp.Plan.SetAttribute(ctx, path.Root("project_id"), defaultProjectID)...,
+	)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The resource is being created.
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var stateProjectID types.String
+	resp.Diagnostics.Append(
+		req.State.GetAttribute(ctx, path.Root("project_id"), &stateProjectID)...,
+	)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if stateProjectID.ValueString() != defaultProjectID {
+		resp.RequiresReplace.Append(path.Root("project_id"))
+	}
+}
+
+// ProjectOrDefault returns the project set in the given data source attribute,
+// or the provider's default project if the attribute is not set.
+func ProjectOrDefault(
+	project types.String,
+	attribute string,
+	defaultProjectID string,
+) (oxide.NameOrId, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if p := project.ValueString(); p != "" {
+		return oxide.NameOrId(p), diags
+	}
+
+	if defaultProjectID == "" {
+		diags.AddAttributeError(
+			path.Root(attribute),
+			"Missing "+attribute,
+			"The "+attribute+" attribute must be set when the provider does not set default_project.",
+		)
+		return "", diags
+	}
+
+	return oxide.NameOrId(defaultProjectID), diags
+}
@@ -0,0 +1,50 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package shared
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/oxidecomputer/oxide.go/oxide"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ProjectOrDefault(t *testing.T) {
+	const defaultProjectID = "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+
+	tests := []struct {
+		name             string
+		project          types.String
+		defaultProjectID string
+		want             oxide.NameOrId
+		wantErr          bool
+	}{
+		{
+			name:             "project set",
+			project:          types.StringValue("my-project"),
+			defaultProjectID: defaultProjectID,
+			want:             oxide.NameOrId("my-project"),
+		},
+		{
+			name:             "project not set",
+			project:          types.StringNull(),
+			defaultProjectID: defaultProjectID,
+			want:             oxide.NameOrId(defaultProjectID),
+		},
+		{
+			name:    "project and default not set",
+			project: types.StringNull(),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, diags := ProjectOrDefault(tt.project, "project_name", tt.defaultProjectID)
+			assert.Equal(t, tt.wantErr, diags.HasError())
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
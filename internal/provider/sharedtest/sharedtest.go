@@ -6,6 +6,7 @@ package sharedtest
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"hash/fnv"
 	"os"
@@ -123,6 +124,47 @@ func VerifyResourceIDChanged(
 	}
 }
 
+// CheckInstanceRunState verifies the instance managed by
+// resourceName is in the given run state. Use to confirm
+// resources that stop an instance start it again.
+func CheckInstanceRunState(
+	resourceName string,
+	want oxide.InstanceState,
+) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf(
+				"resource not found: %s",
+				resourceName,
+			)
+		}
+
+		client, err := NewTestClient()
+		if err != nil {
+			return err
+		}
+
+		instance, err := client.InstanceView(
+			context.Background(),
+			oxide.InstanceViewParams{
+				Instance: oxide.NameOrId(rs.Primary.ID),
+			},
+		)
+		if err != nil {
+			return err
+		}
+		if instance.RunState != want {
+			return fmt.Errorf(
+				"expected instance to be %s, got %s",
+				want,
+				instance.RunState,
+			)
+		}
+		return nil
+	}
+}
+
 func SiloDNSName() string {
 	if v := os.Getenv("OXIDE_TEST_SILO_DNS_NAME"); v != "" {
 		return v
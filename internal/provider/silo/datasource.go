@@ -60,7 +60,7 @@ func (d *DataSource) Configure(
 		return
 	}
 
-	d.client = req.ProviderData.(*oxide.Client)
+	d.client = req.ProviderData.(*shared.ProviderData).Client
 }
 
 // Schema defines the schema for the data source.
@@ -134,10 +134,10 @@ func (d *DataSource) Read(
 	}
 	silo, err := d.client.SiloView(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read Silo:",
-			err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
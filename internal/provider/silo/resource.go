@@ -106,7 +106,7 @@ func (r *Resource) Configure(
 		return
 	}
 
-	r.client = req.ProviderData.(*oxide.Client)
+	r.client = req.ProviderData.(*shared.ProviderData).Client
 }
 
 // ImportState imports this resource using its ID.
@@ -115,7 +115,23 @@ func (r *Resource) ImportState(
 	req resource.ImportStateRequest,
 	resp *resource.ImportStateResponse,
 ) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	shared.ImportStateByPath(
+		ctx,
+		req,
+		resp,
+		path.Root("id"),
+		"silo",
+		"",
+		func(ctx context.Context, names []string) (string, error) {
+			silo, err := r.client.SiloView(ctx, oxide.SiloViewParams{
+				Silo: oxide.NameOrId(names[0]),
+			})
+			if err != nil {
+				return "", err
+			}
+			return silo.Id, nil
+		},
+	)
 }
 
 const tlsCertificateRegEx = `^[a-zA-Z0-9-]+$`
@@ -349,10 +365,10 @@ func (r *Resource) Create(
 
 	silo, err := r.client.SiloCreate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error creating silo",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -404,10 +420,10 @@ func (r *Resource) Read(
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read Silo:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -417,10 +433,10 @@ func (r *Resource) Read(
 		Silo: oxide.NameOrId(state.ID.ValueString()),
 	})
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read silo quotas:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -486,10 +502,10 @@ func (r *Resource) Update(
 
 	siloQuotas, err := r.client.SiloQuotasUpdate(ctx, siloQuotasParams)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error updating silo quotas",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -503,10 +519,10 @@ func (r *Resource) Update(
 		Silo: oxide.NameOrId(state.ID.ValueString()),
 	})
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error updating silo quotas",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -553,10 +569,10 @@ func (r *Resource) Delete(
 
 	if err := r.client.SiloDelete(ctx, params); err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Error deleting silo:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
@@ -85,7 +85,7 @@ func (r *Resource) Configure(
 		return
 	}
 
-	r.client = req.ProviderData.(*oxide.Client)
+	r.client = req.ProviderData.(*shared.ProviderData).Client
 }
 
 func (r *Resource) Schema(
@@ -352,10 +352,10 @@ func (r *Resource) Create(
 			map[string]any{"success": true},
 		)
 	} else if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error creating SAML identity provider",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	} else {
 		tflog.Trace(
@@ -406,10 +406,10 @@ func (r *Resource) Read(
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read SAML identity provider:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -489,7 +489,27 @@ func (r *Resource) ImportState(
 	req resource.ImportStateRequest,
 	resp *resource.ImportStateResponse,
 ) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	shared.ImportStateByPath(
+		ctx,
+		req,
+		resp,
+		path.Root("id"),
+		"silo/identity_provider",
+		"",
+		func(ctx context.Context, names []string) (string, error) {
+			identityProvider, err := r.client.SamlIdentityProviderView(
+				ctx,
+				oxide.SamlIdentityProviderViewParams{
+					Silo:     oxide.NameOrId(names[0]),
+					Provider: oxide.NameOrId(names[1]),
+				},
+			)
+			if err != nil {
+				return "", err
+			}
+			return identityProvider.Id, nil
+		},
+	)
 }
 
 // UpgradeState implements [resource.ResourceWithUpgradeState].
@@ -0,0 +1,213 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/oxidecomputer/oxide.go/oxide"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/shared"
+	oxidevalidator "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/validator"
+)
+
+var (
+	_ datasource.DataSource              = (*DataSource)(nil)
+	_ datasource.DataSourceWithConfigure = (*DataSource)(nil)
+)
+
+// NewDataSource initialises a snapshot datasource
+func NewDataSource() datasource.DataSource {
+	return &DataSource{}
+}
+
+type DataSource struct {
+	client           *oxide.Client
+	defaultProjectID string
+}
+
+type DataSourceModel struct {
+	Description  types.String   `tfsdk:"description"`
+	DiskID       types.String   `tfsdk:"disk_id"`
+	ID           types.String   `tfsdk:"id"`
+	Name         types.String   `tfsdk:"name"`
+	ProjectID    types.String   `tfsdk:"project_id"`
+	ProjectName  types.String   `tfsdk:"project_name"`
+	Size         types.Int64    `tfsdk:"size"`
+	State        types.String   `tfsdk:"state"`
+	TimeCreated  types.String   `tfsdk:"time_created"`
+	TimeModified types.String   `tfsdk:"time_modified"`
+	Timeouts     timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (d *DataSource) Metadata(
+	ctx context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = "oxide_snapshot"
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *DataSource) Configure(
+	_ context.Context,
+	req datasource.ConfigureRequest,
+	_ *datasource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData := req.ProviderData.(*shared.ProviderData)
+	d.client = providerData.Client
+	d.defaultProjectID = providerData.DefaultProjectID
+}
+
+func (d *DataSource) Schema(
+	ctx context.Context,
+	req datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: shared.ReplaceBackticks(`
+Retrieve information about a specified snapshot.
+
+The snapshot is looked up by ''id'', or by ''name'' within a project.
+`),
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Unique, immutable, system-controlled identifier of the snapshot.",
+				Validators: []validator.String{
+					oxidevalidator.IsUUID(),
+					stringvalidator.ExactlyOneOf(path.MatchRoot("name")),
+				},
+			},
+			"name": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Name of the snapshot.",
+			},
+			"project_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the project that contains the snapshot. Only used with `name`. Defaults to the provider's `default_project`.",
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("id")),
+				},
+			},
+			"timeouts": timeouts.Attributes(ctx),
+			"description": schema.StringAttribute{
+				Computed:    true,
+				Description: "Description for the snapshot.",
+			},
+			"disk_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "ID of the disk the snapshot was created from.",
+			},
+			"project_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "ID of the project that contains the snapshot.",
+			},
+			"size": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Size of the snapshot in bytes.",
+			},
+			"state": schema.StringAttribute{
+				Computed:    true,
+				Description: "State of the snapshot.",
+			},
+			"time_created": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp of when this snapshot was created.",
+			},
+			"time_modified": schema.StringAttribute{
+				Computed:    true,
+				Description: "Timestamp of when this snapshot was last modified.",
+			},
+		},
+	}
+}
+
+func (d *DataSource) Read(
+	ctx context.Context,
+	req datasource.ReadRequest,
+	resp *datasource.ReadResponse,
+) {
+	var state DataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	// A snapshot ID is unique across projects, only a name needs a project.
+	params := oxide.SnapshotViewParams{
+		Snapshot: oxide.NameOrId(state.ID.ValueString()),
+	}
+	if !state.Name.IsNull() {
+		project, diags := shared.ProjectOrDefault(
+			state.ProjectName,
+			"project_name",
+			d.defaultProjectID,
+		)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		params = oxide.SnapshotViewParams{
+			Snapshot: oxide.NameOrId(state.Name.ValueString()),
+			Project:  project,
+		}
+	}
+
+	snapshot, err := d.client.SnapshotView(ctx, params)
+	if err != nil {
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+			"Unable to read snapshot:",
+			err,
+		))
+		return
+	}
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("read snapshot with ID: %v", snapshot.Id),
+		map[string]any{"success": true},
+	)
+
+	state.Description = types.StringValue(snapshot.Description)
+	state.DiskID = types.StringValue(string(snapshot.DiskId))
+	state.ID = types.StringValue(snapshot.Id)
+	state.Name = types.StringValue(string(snapshot.Name))
+	state.ProjectID = types.StringValue(snapshot.ProjectId)
+	state.Size = types.Int64Value(int64(snapshot.Size))
+	state.State = types.StringValue(string(snapshot.State))
+	state.TimeCreated = types.StringValue(snapshot.TimeCreated.String())
+	state.TimeModified = types.StringValue(snapshot.TimeModified.String())
+
+	// Save state into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,93 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package snapshot_test
+
+import (
+	"testing"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/sharedtest"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+type dataSourceConfig struct {
+	DiskName     string
+	SnapshotName string
+}
+
+var dataSourceConfigTpl = `
+data "oxide_project" "test" {
+	name = "tf-acc-test"
+}
+
+resource "oxide_disk" "test" {
+  project_id  = data.oxide_project.test.id
+  description = "a test disk for data source"
+  name        = "{{.DiskName}}"
+  size        = 1073741824
+  block_size  = 512
+}
+
+resource "oxide_snapshot" "test" {
+  project_id  = data.oxide_project.test.id
+  description = "a test snapshot for data source"
+  name        = "{{.SnapshotName}}"
+  disk_id     = oxide_disk.test.id
+}
+
+data "oxide_snapshot" "by_name" {
+  project_name = data.oxide_project.test.name
+  name         = oxide_snapshot.test.name
+  timeouts = {
+    read = "1m"
+  }
+}
+
+data "oxide_snapshot" "by_id" {
+  id = oxide_snapshot.test.id
+}
+`
+
+func TestAccCloudDataSourceSnapshot_full(t *testing.T) {
+	snapshotName := sharedtest.NewResourceName()
+	config := sharedtest.ParsedAccConfig(t,
+		dataSourceConfig{
+			DiskName:     sharedtest.NewResourceName(),
+			SnapshotName: snapshotName,
+		},
+		dataSourceConfigTpl,
+	)
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { sharedtest.PreCheck(t) },
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkDataSource("data.oxide_snapshot.by_name", snapshotName),
+					checkDataSource("data.oxide_snapshot.by_id", snapshotName),
+					resource.TestCheckResourceAttr(
+						"data.oxide_snapshot.by_name", "timeouts.read", "1m",
+					),
+				),
+			},
+		},
+	})
+}
+
+func checkDataSource(dataName, snapshotName string) resource.TestCheckFunc {
+	return resource.ComposeAggregateTestCheckFunc([]resource.TestCheckFunc{
+		resource.TestCheckResourceAttrPair(dataName, "id", "oxide_snapshot.test", "id"),
+		resource.TestCheckResourceAttr(dataName, "name", snapshotName),
+		resource.TestCheckResourceAttr(dataName, "description", "a test snapshot for data source"),
+		resource.TestCheckResourceAttrPair(dataName, "disk_id", "oxide_disk.test", "id"),
+		resource.TestCheckResourceAttrPair(dataName, "project_id", "data.oxide_project.test", "id"),
+		resource.TestCheckResourceAttr(dataName, "size", "1073741824"),
+		resource.TestCheckResourceAttr(dataName, "state", "ready"),
+		resource.TestCheckResourceAttrSet(dataName, "time_created"),
+		resource.TestCheckResourceAttrSet(dataName, "time_modified"),
+	}...)
+}
@@ -25,8 +25,9 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = (*Resource)(nil)
-	_ resource.ResourceWithConfigure = (*Resource)(nil)
+	_ resource.Resource               = (*Resource)(nil)
+	_ resource.ResourceWithConfigure  = (*Resource)(nil)
+	_ resource.ResourceWithModifyPlan = (*Resource)(nil)
 )
 
 // NewResource is a helper function to simplify the provider implementation.
@@ -36,7 +37,8 @@ func NewResource() resource.Resource {
 
 // Resource is the resource implementation.
 type Resource struct {
-	client *oxide.Client
+	client           *oxide.Client
+	defaultProjectID string
 }
 
 type ResourceModel struct {
@@ -70,7 +72,19 @@ func (r *Resource) Configure(
 		return
 	}
 
-	r.client = req.ProviderData.(*oxide.Client)
+	providerData := req.ProviderData.(*shared.ProviderData)
+	r.client = providerData.Client
+	r.defaultProjectID = providerData.DefaultProjectID
+}
+
+// ModifyPlan sets project_id to the provider's default project when it's not
+// set in the configuration.
+func (r *Resource) ModifyPlan(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	shared.ModifyPlanForDefaultProject(ctx, r.defaultProjectID, req, resp)
 }
 
 func (r *Resource) ImportState(
@@ -78,7 +92,24 @@ func (r *Resource) ImportState(
 	req resource.ImportStateRequest,
 	resp *resource.ImportStateResponse,
 ) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	shared.ImportStateByPath(
+		ctx,
+		req,
+		resp,
+		path.Root("id"),
+		"project/snapshot",
+		r.defaultProjectID,
+		func(ctx context.Context, names []string) (string, error) {
+			snapshot, err := r.client.SnapshotView(ctx, oxide.SnapshotViewParams{
+				Project:  oxide.NameOrId(names[0]),
+				Snapshot: oxide.NameOrId(names[1]),
+			})
+			if err != nil {
+				return "", err
+			}
+			return snapshot.Id, nil
+		},
+	)
 }
 
 // Schema defines the schema for the resource.
@@ -95,13 +126,14 @@ This resource manages snapshots.
 `,
 		Attributes: map[string]schema.Attribute{
 			"project_id": schema.StringAttribute{
-				Required:    true,
-				Description: "ID of the project that will contain the snapshot.",
+				Optional:    true,
+				Computed:    true,
+				Description: "ID of the project that will contain the snapshot. Defaults to the provider's `default_project`.",
 				Validators: []validator.String{
 					oxidevalidator.IsUUID(),
 				},
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIfConfigured(),
 				},
 			},
 			"name": schema.StringAttribute{
@@ -181,10 +213,10 @@ func (r *Resource) Create(
 	}
 	disk, err := r.client.DiskView(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error retrieving information from disk",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -203,10 +235,10 @@ func (r *Resource) Create(
 	}
 	snapshot, err := r.client.SnapshotCreate(ctx, params2)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error creating snapshot",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -260,10 +292,10 @@ func (r *Resource) Read(
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read snapshot:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -326,10 +358,10 @@ func (r *Resource) Delete(
 	}
 	if err := r.client.SnapshotDelete(ctx, params); err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Error deleting snapshot:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
@@ -0,0 +1,272 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package snapshots
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/oxidecomputer/oxide.go/oxide"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/shared"
+	oxidevalidator "github.com/oxidecomputer/terraform-provider-oxide/internal/provider/validator"
+)
+
+var (
+	_ datasource.DataSource              = (*DataSource)(nil)
+	_ datasource.DataSourceWithConfigure = (*DataSource)(nil)
+)
+
+// NewDataSource initialises a snapshots datasource
+func NewDataSource() datasource.DataSource {
+	return &DataSource{}
+}
+
+type DataSource struct {
+	client           *oxide.Client
+	defaultProjectID string
+}
+
+type DataSourceModel struct {
+	DiskID      types.String              `tfsdk:"disk_id"`
+	ID          types.String              `tfsdk:"id"`
+	MaxAge      types.String              `tfsdk:"max_age"`
+	NameRegex   types.String              `tfsdk:"name_regex"`
+	ProjectName types.String              `tfsdk:"project_name"`
+	Timeouts    timeouts.Value            `tfsdk:"timeouts"`
+	Snapshots   []SnapshotDataSourceModel `tfsdk:"snapshots"`
+}
+
+type SnapshotDataSourceModel struct {
+	Description  types.String `tfsdk:"description"`
+	DiskID       types.String `tfsdk:"disk_id"`
+	ID           types.String `tfsdk:"id"`
+	Name         types.String `tfsdk:"name"`
+	ProjectID    types.String `tfsdk:"project_id"`
+	Size         types.Int64  `tfsdk:"size"`
+	State        types.String `tfsdk:"state"`
+	TimeCreated  types.String `tfsdk:"time_created"`
+	TimeModified types.String `tfsdk:"time_modified"`
+}
+
+func (d *DataSource) Metadata(
+	ctx context.Context,
+	req datasource.MetadataRequest,
+	resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = "oxide_snapshots"
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *DataSource) Configure(
+	_ context.Context,
+	req datasource.ConfigureRequest,
+	_ *datasource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData := req.ProviderData.(*shared.ProviderData)
+	d.client = providerData.Client
+	d.defaultProjectID = providerData.DefaultProjectID
+}
+
+func (d *DataSource) Schema(
+	ctx context.Context,
+	req datasource.SchemaRequest,
+	resp *datasource.SchemaResponse,
+) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: shared.ReplaceBackticks(`
+Retrieve a list of all snapshots belonging to a project, optionally filtered by source disk, name or age.
+
+Snapshots are sorted from the most to the least recently created, so ''snapshots[0]'' is the latest matching snapshot.
+`),
+		Attributes: map[string]schema.Attribute{
+			"project_name": schema.StringAttribute{
+				Optional:    true,
+				Description: "Name of the project which contains the snapshots. Defaults to the provider's `default_project`.",
+			},
+			"disk_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return snapshots created from this disk.",
+				Validators: []validator.String{
+					oxidevalidator.IsUUID(),
+				},
+			},
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Regular expression the snapshot names must match.",
+				Validators: []validator.String{
+					oxidevalidator.IsRegexp(),
+				},
+			},
+			"max_age": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return snapshots created within this duration, such as `24h`.",
+				Validators: []validator.String{
+					oxidevalidator.IsDuration(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"timeouts": timeouts.Attributes(ctx),
+			"snapshots": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"description": schema.StringAttribute{
+							Computed:    true,
+							Description: "Description for the snapshot.",
+						},
+						"disk_id": schema.StringAttribute{
+							Computed:    true,
+							Description: "ID of the disk the snapshot was created from.",
+						},
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "Unique, immutable, system-controlled identifier of the snapshot.",
+						},
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "Name of the snapshot.",
+						},
+						"project_id": schema.StringAttribute{
+							Computed:    true,
+							Description: "ID of the project that contains the snapshot.",
+						},
+						"size": schema.Int64Attribute{
+							Computed:    true,
+							Description: "Size of the snapshot in bytes.",
+						},
+						"state": schema.StringAttribute{
+							Computed:    true,
+							Description: "State of the snapshot.",
+						},
+						"time_created": schema.StringAttribute{
+							Computed:    true,
+							Description: "Timestamp of when this snapshot was created.",
+						},
+						"time_modified": schema.StringAttribute{
+							Computed:    true,
+							Description: "Timestamp of when this snapshot was last modified.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *DataSource) Read(
+	ctx context.Context,
+	req datasource.ReadRequest,
+	resp *datasource.ReadResponse,
+) {
+	var state DataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := state.Timeouts.Read(ctx, shared.DefaultTimeout())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	project, diags := shared.ProjectOrDefault(state.ProjectName, "project_name", d.defaultProjectID)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	params := oxide.SnapshotListParams{
+		Project: project,
+		SortBy:  oxide.NameOrIdSortModeNameAscending,
+	}
+	snapshots, err := d.client.SnapshotListAllPages(ctx, params)
+	if err != nil {
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
+			"Unable to read snapshots:",
+			err,
+		))
+		return
+	}
+
+	tflog.Trace(
+		ctx,
+		fmt.Sprintf("read all snapshots from project: %v", project),
+		map[string]any{"success": true},
+	)
+
+	// Set a unique ID for the datasource payload
+	state.ID = types.StringValue(uuid.New().String())
+
+	// The validators ensure the regular expression and the duration are valid.
+	var nameRegex *regexp.Regexp
+	if !state.NameRegex.IsNull() {
+		nameRegex = regexp.MustCompile(state.NameRegex.ValueString())
+	}
+	var createdAfter time.Time
+	if !state.MaxAge.IsNull() {
+		maxAge, _ := time.ParseDuration(state.MaxAge.ValueString())
+		createdAfter = time.Now().Add(-maxAge)
+	}
+
+	snapshots = slices.DeleteFunc(snapshots, func(snapshot oxide.Snapshot) bool {
+		switch {
+		case !state.DiskID.IsNull() && string(snapshot.DiskId) != state.DiskID.ValueString():
+			return true
+		case nameRegex != nil && !nameRegex.MatchString(string(snapshot.Name)):
+			return true
+		case !createdAfter.IsZero() && snapshot.TimeCreated.Before(createdAfter):
+			return true
+		}
+		return false
+	})
+
+	// List the latest snapshot first.
+	slices.SortStableFunc(snapshots, func(a, b oxide.Snapshot) int {
+		return b.TimeCreated.Compare(*a.TimeCreated)
+	})
+
+	// Map response body to model
+	for _, snapshot := range snapshots {
+		state.Snapshots = append(state.Snapshots, SnapshotDataSourceModel{
+			Description:  types.StringValue(snapshot.Description),
+			DiskID:       types.StringValue(string(snapshot.DiskId)),
+			ID:           types.StringValue(snapshot.Id),
+			Name:         types.StringValue(string(snapshot.Name)),
+			ProjectID:    types.StringValue(snapshot.ProjectId),
+			Size:         types.Int64Value(int64(snapshot.Size)),
+			State:        types.StringValue(string(snapshot.State)),
+			TimeCreated:  types.StringValue(snapshot.TimeCreated.String()),
+			TimeModified: types.StringValue(snapshot.TimeModified.String()),
+		})
+	}
+
+	// Save state into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
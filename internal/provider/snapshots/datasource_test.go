@@ -0,0 +1,127 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package snapshots_test
+
+import (
+	"testing"
+
+	"github.com/oxidecomputer/terraform-provider-oxide/internal/provider/sharedtest"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+type dataSourceConfig struct {
+	DiskName     string
+	SnapshotName string
+}
+
+var dataSourceConfigTpl = `
+data "oxide_project" "test" {
+	name = "tf-acc-test"
+}
+
+resource "oxide_disk" "test" {
+  project_id  = data.oxide_project.test.id
+  description = "a test disk for data source"
+  name        = "{{.DiskName}}"
+  size        = 1073741824
+  block_size  = 512
+}
+
+resource "oxide_snapshot" "first" {
+  project_id  = data.oxide_project.test.id
+  description = "a test snapshot for data source"
+  name        = "{{.SnapshotName}}-first"
+  disk_id     = oxide_disk.test.id
+}
+
+resource "oxide_snapshot" "second" {
+  project_id  = data.oxide_project.test.id
+  description = "a test snapshot for data source"
+  name        = "{{.SnapshotName}}-second"
+  disk_id     = oxide_disk.test.id
+
+  depends_on = [oxide_snapshot.first]
+}
+
+data "oxide_snapshots" "disk" {
+  project_name = data.oxide_project.test.name
+  disk_id      = oxide_disk.test.id
+  max_age      = "1h"
+  timeouts = {
+    read = "1m"
+  }
+
+  depends_on = [oxide_snapshot.first, oxide_snapshot.second]
+}
+
+data "oxide_snapshots" "name_regex" {
+  project_name = data.oxide_project.test.name
+  name_regex   = "^{{.SnapshotName}}-f"
+
+  depends_on = [oxide_snapshot.first, oxide_snapshot.second]
+}
+`
+
+func TestAccCloudDataSourceSnapshots_full(t *testing.T) {
+	config := sharedtest.ParsedAccConfig(t,
+		dataSourceConfig{
+			DiskName:     sharedtest.NewResourceName(),
+			SnapshotName: sharedtest.NewResourceName(),
+		},
+		dataSourceConfigTpl,
+	)
+
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:                 func() { sharedtest.PreCheck(t) },
+		ProtoV6ProviderFactories: sharedtest.ProviderFactories(),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkDataSourceDisk("data.oxide_snapshots.disk"),
+					checkDataSourceNameRegex("data.oxide_snapshots.name_regex"),
+				),
+			},
+		},
+	})
+}
+
+func checkDataSourceDisk(dataName string) resource.TestCheckFunc {
+	return resource.ComposeAggregateTestCheckFunc([]resource.TestCheckFunc{
+		resource.TestCheckResourceAttrSet(dataName, "id"),
+		resource.TestCheckResourceAttr(dataName, "timeouts.read", "1m"),
+		resource.TestCheckResourceAttr(dataName, "snapshots.#", "2"),
+		// The latest snapshot is listed first.
+		resource.TestCheckResourceAttrPair(
+			dataName, "snapshots.0.id", "oxide_snapshot.second", "id",
+		),
+		resource.TestCheckResourceAttrPair(
+			dataName, "snapshots.1.id", "oxide_snapshot.first", "id",
+		),
+		resource.TestCheckResourceAttr(
+			dataName, "snapshots.0.description", "a test snapshot for data source",
+		),
+		resource.TestCheckResourceAttrPair(
+			dataName, "snapshots.0.disk_id", "oxide_disk.test", "id",
+		),
+		resource.TestCheckResourceAttrPair(
+			dataName, "snapshots.0.project_id", "data.oxide_project.test", "id",
+		),
+		resource.TestCheckResourceAttr(dataName, "snapshots.0.size", "1073741824"),
+		resource.TestCheckResourceAttr(dataName, "snapshots.0.state", "ready"),
+		resource.TestCheckResourceAttrSet(dataName, "snapshots.0.time_created"),
+		resource.TestCheckResourceAttrSet(dataName, "snapshots.0.time_modified"),
+	}...)
+}
+
+func checkDataSourceNameRegex(dataName string) resource.TestCheckFunc {
+	return resource.ComposeAggregateTestCheckFunc([]resource.TestCheckFunc{
+		resource.TestCheckResourceAttr(dataName, "snapshots.#", "1"),
+		resource.TestCheckResourceAttrPair(
+			dataName, "snapshots.0.id", "oxide_snapshot.first", "id",
+		),
+	}...)
+}
@@ -68,7 +68,7 @@ func (r *Resource) Configure(
 		return
 	}
 
-	r.client = req.ProviderData.(*oxide.Client)
+	r.client = req.ProviderData.(*shared.ProviderData).Client
 }
 
 // ImportState configures the resource to be imported by its ID.
@@ -77,7 +77,23 @@ func (r *Resource) ImportState(
 	req resource.ImportStateRequest,
 	resp *resource.ImportStateResponse,
 ) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	shared.ImportStateByPath(
+		ctx,
+		req,
+		resp,
+		path.Root("id"),
+		"ssh_key",
+		"",
+		func(ctx context.Context, names []string) (string, error) {
+			sshKey, err := r.client.CurrentUserSshKeyView(ctx, oxide.CurrentUserSshKeyViewParams{
+				SshKey: oxide.NameOrId(names[0]),
+			})
+			if err != nil {
+				return "", err
+			}
+			return sshKey.Id, nil
+		},
+	)
 }
 
 // Schema defines the schema for the resource.
@@ -174,10 +190,10 @@ func (r *Resource) Create(
 
 	sshKey, err := r.client.CurrentUserSshKeyCreate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error creating SSH key",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -231,10 +247,10 @@ func (r *Resource) Read(
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read SSH key:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -301,10 +317,10 @@ func (r *Resource) Delete(
 	}
 	if err := r.client.CurrentUserSshKeyDelete(ctx, params); err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Error deleting SSH key:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
@@ -66,7 +66,7 @@ func (d *DataSource) Configure(
 		return
 	}
 
-	d.client = req.ProviderData.(*oxide.Client)
+	d.client = req.ProviderData.(*shared.ProviderData).Client
 }
 
 // Schema defines the schema for the data source.
@@ -154,10 +154,10 @@ func (d *DataSource) Read(
 	}
 	pool, err := d.client.SystemSubnetPoolView(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read subnet pool:",
-			err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -183,10 +183,10 @@ func (d *DataSource) Read(
 		},
 	)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read subnet pool members:",
-			err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
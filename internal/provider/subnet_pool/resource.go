@@ -69,7 +69,7 @@ func (r *Resource) Configure(
 		return
 	}
 
-	r.client = req.ProviderData.(*oxide.Client)
+	r.client = req.ProviderData.(*shared.ProviderData).Client
 }
 
 func (r *Resource) ImportState(
@@ -77,7 +77,23 @@ func (r *Resource) ImportState(
 	req resource.ImportStateRequest,
 	resp *resource.ImportStateResponse,
 ) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	shared.ImportStateByPath(
+		ctx,
+		req,
+		resp,
+		path.Root("id"),
+		"subnet_pool",
+		"",
+		func(ctx context.Context, names []string) (string, error) {
+			subnetPool, err := r.client.SystemSubnetPoolView(ctx, oxide.SystemSubnetPoolViewParams{
+				Pool: oxide.NameOrId(names[0]),
+			})
+			if err != nil {
+				return "", err
+			}
+			return subnetPool.Id, nil
+		},
+	)
 }
 
 // Schema defines the schema for the resource.
@@ -163,10 +179,10 @@ func (r *Resource) Create(
 	}
 	pool, err := r.client.SystemSubnetPoolCreate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error creating subnet pool",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -218,10 +234,10 @@ func (r *Resource) Read(
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read subnet pool:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -284,10 +300,10 @@ func (r *Resource) Update(
 
 	pool, err := r.client.SystemSubnetPoolUpdate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error updating subnet pool",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -336,10 +352,10 @@ func (r *Resource) Delete(
 			Pool: oxide.NameOrId(state.ID.ValueString()),
 		}); err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Error deleting subnet pool:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
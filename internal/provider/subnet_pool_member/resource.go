@@ -70,7 +70,7 @@ func (r *Resource) Configure(
 		return
 	}
 
-	r.client = req.ProviderData.(*oxide.Client)
+	r.client = req.ProviderData.(*shared.ProviderData).Client
 }
 
 func (r *Resource) ImportState(
@@ -206,10 +206,10 @@ func (r *Resource) Create(
 
 	member, err := r.client.SystemSubnetPoolMemberAdd(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error creating subnet pool member",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -267,10 +267,10 @@ func (r *Resource) Read(
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read subnet pool members:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -367,10 +367,10 @@ func (r *Resource) Delete(
 		//
 		// TODO: Switch to a 404 in omicron.
 		if !shared.Is404(err) && !strings.Contains(err.Error(), "does not exist") {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Error deleting subnet pool member:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
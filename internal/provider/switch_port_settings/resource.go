@@ -173,7 +173,7 @@ func (r *Resource) Configure(
 		return
 	}
 
-	r.client = req.ProviderData.(*oxide.Client)
+	r.client = req.ProviderData.(*shared.ProviderData).Client
 }
 
 // ImportState contains logic on how to import the resource.
@@ -182,7 +182,26 @@ func (r *Resource) ImportState(
 	req resource.ImportStateRequest,
 	resp *resource.ImportStateResponse,
 ) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	shared.ImportStateByPath(
+		ctx,
+		req,
+		resp,
+		path.Root("id"),
+		"switch_port_settings",
+		"",
+		func(ctx context.Context, names []string) (string, error) {
+			switchPortSettings, err := r.client.NetworkingSwitchPortSettingsView(
+				ctx,
+				oxide.NetworkingSwitchPortSettingsViewParams{
+					Port: oxide.NameOrId(names[0]),
+				},
+			)
+			if err != nil {
+				return "", err
+			}
+			return switchPortSettings.Id, nil
+		},
+	)
 }
 
 // UpgradeState upgrades the Terraform state for the oxide_switch_port_settings
@@ -626,10 +645,10 @@ func (r *Resource) Create(
 
 	settings, err := r.client.NetworkingSwitchPortSettingsCreate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error creating switch port settings",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -681,10 +700,10 @@ func (r *Resource) Read(
 	)
 
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read Switch Port Settings:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -766,10 +785,10 @@ func (r *Resource) Update(
 
 	settings, err := r.client.NetworkingSwitchPortSettingsCreate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error updating switch port settings",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -819,10 +838,10 @@ func (r *Resource) Delete(
 			PortSettings: oxide.NameOrId(state.ID.ValueString()),
 		}); err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Error deleting Switch Port Settings:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
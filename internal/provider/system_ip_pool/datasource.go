@@ -62,7 +62,7 @@ func (d *DataSource) Configure(
 		return
 	}
 
-	d.client = req.ProviderData.(*oxide.Client)
+	d.client = req.ProviderData.(*shared.ProviderData).Client
 }
 
 // Schema defines the schema for the data source.
@@ -140,10 +140,10 @@ func (d *DataSource) Read(
 		Pool: oxide.NameOrId(state.Pool.ValueString()),
 	})
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read system IP pool:",
-			err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -62,7 +62,7 @@ func (d *DataSource) Configure(
 		return
 	}
 
-	d.client = req.ProviderData.(*oxide.Client)
+	d.client = req.ProviderData.(*shared.ProviderData).Client
 }
 
 func (d *DataSource) Schema(
@@ -136,10 +136,10 @@ func (d *DataSource) Read(
 	}
 	ipPools, err := d.client.SystemIpPoolListAllPages(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read system IP pools list:",
-			err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -63,7 +63,7 @@ func (d *DataSource) Configure(
 		return
 	}
 
-	d.client = req.ProviderData.(*oxide.Client)
+	d.client = req.ProviderData.(*shared.ProviderData).Client
 }
 
 func (d *DataSource) Schema(
@@ -138,10 +138,10 @@ func (d *DataSource) Read(
 	}
 	subnetPools, err := d.client.SystemSubnetPoolListAllPages(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read system subnet pools list:",
-			err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
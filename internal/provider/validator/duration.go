@@ -0,0 +1,62 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package validator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// Compile-time interface assertion.
+var _ validator.String = isDuration{}
+
+// isDuration validates that a configured string is a positive duration.
+type isDuration struct{}
+
+// Description returns a plain text description of the validator's behavior.
+func (v isDuration) Description(_ context.Context) string {
+	return "Value must be a positive duration"
+}
+
+// MarkdownDescription returns a markdown description of the validator's
+// behavior.
+func (v isDuration) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString validates that a configured string is a positive duration
+// accepted by [time.ParseDuration]. Null and unknown values are skipped so
+// that this validator can be composed with others.
+func (v isDuration) ValidateString(
+	_ context.Context,
+	req validator.StringRequest,
+	resp *validator.StringResponse,
+) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid duration",
+			fmt.Sprintf(
+				"Attribute %s value must be a positive duration such as \"24h\", got: %s",
+				req.Path,
+				value,
+			),
+		)
+	}
+}
+
+// IsDuration returns a string validator which ensures that a configured value
+// is a positive duration.
+func IsDuration() validator.String {
+	return isDuration{}
+}
@@ -0,0 +1,64 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package validator
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// Compile-time interface assertion.
+var _ validator.String = isRegexp{}
+
+// isRegexp validates that a configured string is a valid regular expression.
+type isRegexp struct{}
+
+// Description returns a plain text description of the validator's behavior.
+func (v isRegexp) Description(_ context.Context) string {
+	return "Value must be a valid regular expression"
+}
+
+// MarkdownDescription returns a markdown description of the validator's
+// behavior.
+func (v isRegexp) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString validates that a configured string compiles as a regular
+// expression using the RE2 syntax accepted by [regexp.Compile]. Null and
+// unknown values are skipped so that this validator can be composed with
+// others.
+func (v isRegexp) ValidateString(
+	_ context.Context,
+	req validator.StringRequest,
+	resp *validator.StringResponse,
+) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if _, err := regexp.Compile(value); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid regular expression",
+			fmt.Sprintf(
+				"Attribute %s value must be a valid regular expression, got: %s: %v",
+				req.Path,
+				value,
+				err,
+			),
+		)
+	}
+}
+
+// IsRegexp returns a string validator which ensures that a configured value is
+// a valid regular expression.
+func IsRegexp() validator.String {
+	return isRegexp{}
+}
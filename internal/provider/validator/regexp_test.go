@@ -0,0 +1,58 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package validator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_IsRegexp(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     types.String
+		wantError bool
+	}{
+		{
+			name:      "valid regexp",
+			value:     types.StringValue("^web-[0-9]+$"),
+			wantError: false,
+		},
+		{
+			name:      "invalid regexp",
+			value:     types.StringValue("web-("),
+			wantError: true,
+		},
+		{
+			name:      "null is skipped",
+			value:     types.StringNull(),
+			wantError: false,
+		},
+		{
+			name:      "unknown is skipped",
+			value:     types.StringUnknown(),
+			wantError: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validator.StringRequest{
+				Path:        path.Root("test"),
+				ConfigValue: tt.value,
+			}
+			resp := &validator.StringResponse{}
+
+			IsRegexp().ValidateString(context.Background(), req, resp)
+
+			assert.Equal(t, tt.wantError, resp.Diagnostics.HasError())
+		})
+	}
+}
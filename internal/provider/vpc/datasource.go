@@ -29,7 +29,8 @@ func NewDataSource() datasource.DataSource {
 }
 
 type DataSource struct {
-	client *oxide.Client
+	client           *oxide.Client
+	defaultProjectID string
 }
 
 type DataSourceModel struct {
@@ -64,7 +65,9 @@ func (d *DataSource) Configure(
 		return
 	}
 
-	d.client = req.ProviderData.(*oxide.Client)
+	providerData := req.ProviderData.(*shared.ProviderData)
+	d.client = providerData.Client
+	d.defaultProjectID = providerData.DefaultProjectID
 }
 
 func (d *DataSource) Schema(
@@ -78,8 +81,8 @@ Retrieve information about a specified VPC.
 `,
 		Attributes: map[string]schema.Attribute{
 			"project_name": schema.StringAttribute{
-				Required:    true,
-				Description: "Name of the project that contains the VPC.",
+				Optional:    true,
+				Description: "Name of the project that contains the VPC. Defaults to the provider's `default_project`.",
 			},
 			"name": schema.StringAttribute{
 				Required:    true,
@@ -143,16 +146,22 @@ func (d *DataSource) Read(
 	ctx, cancel := context.WithTimeout(ctx, readTimeout)
 	defer cancel()
 
+	project, diags := shared.ProjectOrDefault(state.ProjectName, "project_name", d.defaultProjectID)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	params := oxide.VpcViewParams{
 		Vpc:     oxide.NameOrId(state.Name.ValueString()),
-		Project: oxide.NameOrId(state.ProjectName.ValueString()),
+		Project: project,
 	}
 	vpc, err := d.client.VpcView(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read VPC:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(ctx, fmt.Sprintf("read VPC with ID: %v", vpc.Id), map[string]any{"success": true})
@@ -25,8 +25,9 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = (*Resource)(nil)
-	_ resource.ResourceWithConfigure = (*Resource)(nil)
+	_ resource.Resource               = (*Resource)(nil)
+	_ resource.ResourceWithConfigure  = (*Resource)(nil)
+	_ resource.ResourceWithModifyPlan = (*Resource)(nil)
 )
 
 // NewResource is a helper function to simplify the provider implementation.
@@ -36,7 +37,8 @@ func NewResource() resource.Resource {
 
 // Resource is the resource implementation.
 type Resource struct {
-	client *oxide.Client
+	client           *oxide.Client
+	defaultProjectID string
 }
 
 type ResourceModel struct {
@@ -71,7 +73,19 @@ func (r *Resource) Configure(
 		return
 	}
 
-	r.client = req.ProviderData.(*oxide.Client)
+	providerData := req.ProviderData.(*shared.ProviderData)
+	r.client = providerData.Client
+	r.defaultProjectID = providerData.DefaultProjectID
+}
+
+// ModifyPlan sets project_id to the provider's default project when it's not
+// set in the configuration.
+func (r *Resource) ModifyPlan(
+	ctx context.Context,
+	req resource.ModifyPlanRequest,
+	resp *resource.ModifyPlanResponse,
+) {
+	shared.ModifyPlanForDefaultProject(ctx, r.defaultProjectID, req, resp)
 }
 
 // ImportState imports an existing VPC resource into Terraform state.
@@ -80,7 +94,24 @@ func (r *Resource) ImportState(
 	req resource.ImportStateRequest,
 	resp *resource.ImportStateResponse,
 ) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	shared.ImportStateByPath(
+		ctx,
+		req,
+		resp,
+		path.Root("id"),
+		"project/vpc",
+		r.defaultProjectID,
+		func(ctx context.Context, names []string) (string, error) {
+			vpc, err := r.client.VpcView(ctx, oxide.VpcViewParams{
+				Project: oxide.NameOrId(names[0]),
+				Vpc:     oxide.NameOrId(names[1]),
+			})
+			if err != nil {
+				return "", err
+			}
+			return vpc.Id, nil
+		},
+	)
 }
 
 // Schema defines the schema for the resource.
@@ -95,13 +126,14 @@ This resource manages VPCs.
 `,
 		Attributes: map[string]schema.Attribute{
 			"project_id": schema.StringAttribute{
-				Required:    true,
-				Description: "ID of the project that will contain the VPC.",
+				Optional:    true,
+				Computed:    true,
+				Description: "ID of the project that will contain the VPC. Defaults to the provider's `default_project`.",
 				Validators: []validator.String{
 					oxidevalidator.IsUUID(),
 				},
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIfConfigured(),
 				},
 			},
 			"name": schema.StringAttribute{
@@ -186,10 +218,10 @@ func (r *Resource) Create(
 	}
 	vpc, err := r.client.VpcCreate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error creating VPC",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -245,10 +277,10 @@ func (r *Resource) Read(
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read VPC:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(ctx, fmt.Sprintf("read VPC with ID: %v", vpc.Id), map[string]any{"success": true})
@@ -310,10 +342,10 @@ func (r *Resource) Update(
 	}
 	vpc, err := r.client.VpcUpdate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error updating vpc",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -365,10 +397,10 @@ func (r *Resource) Delete(
 	}
 	if err := r.client.VpcSubnetDelete(ctx, paramsSubnet); err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Error deleting default subnet:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
@@ -383,10 +415,10 @@ func (r *Resource) Delete(
 	}
 	if err := r.client.VpcDelete(ctx, params); err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Error deleting VPC:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
@@ -125,6 +125,12 @@ func TestAccCloudResourceVPC_full(t *testing.T) {
 				ImportState:       true,
 				ImportStateVerify: true,
 			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateId:     "tf-acc-test/" + vpcNameUpdated,
+				ImportStateVerify: true,
+			},
 			{
 				Config: config2,
 				Check:  checkResourceIPv6(resourceName2, vpcName2),
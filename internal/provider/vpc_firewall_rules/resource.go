@@ -50,7 +50,8 @@ func NewResource() resource.Resource {
 
 // Resource is the resource implementation.
 type Resource struct {
-	client *oxide.Client
+	client           *oxide.Client
+	defaultProjectID string
 }
 
 type ResourceModel struct {
@@ -123,7 +124,9 @@ func (r *Resource) Configure(
 		return
 	}
 
-	r.client = req.ProviderData.(*oxide.Client)
+	providerData := req.ProviderData.(*shared.ProviderData)
+	r.client = providerData.Client
+	r.defaultProjectID = providerData.DefaultProjectID
 }
 
 // ImportState imports an existing VPC firewall rules resource into Terraform state.
@@ -132,7 +135,24 @@ func (r *Resource) ImportState(
 	req resource.ImportStateRequest,
 	resp *resource.ImportStateResponse,
 ) {
-	resource.ImportStatePassthroughID(ctx, path.Root("vpc_id"), req, resp)
+	shared.ImportStateByPath(
+		ctx,
+		req,
+		resp,
+		path.Root("vpc_id"),
+		"project/vpc",
+		r.defaultProjectID,
+		func(ctx context.Context, names []string) (string, error) {
+			vpc, err := r.client.VpcView(ctx, oxide.VpcViewParams{
+				Project: oxide.NameOrId(names[0]),
+				Vpc:     oxide.NameOrId(names[1]),
+			})
+			if err != nil {
+				return "", err
+			}
+			return vpc.Id, nil
+		},
+	)
 }
 
 // UpgradeState upgrades the Terraform state for the oxide_vpc_firewall_rules
@@ -449,10 +469,10 @@ func (r *Resource) Create(
 
 	firewallRules, err := r.client.VpcFirewallRulesUpdate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error creating firewall rules",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -525,10 +545,10 @@ func (r *Resource) Read(
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read firewall rules:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -613,10 +633,10 @@ func (r *Resource) Update(
 	}
 	firewallRules, err := r.client.VpcFirewallRulesUpdate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error updating VPC firewall rules",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -689,10 +709,10 @@ func (r *Resource) Delete(
 	}
 	_, err := r.client.VpcFirewallRulesUpdate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error deleting VPC firewall rules",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
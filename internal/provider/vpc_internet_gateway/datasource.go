@@ -29,7 +29,8 @@ func NewDataSource() datasource.DataSource {
 }
 
 type DataSource struct {
-	client *oxide.Client
+	client           *oxide.Client
+	defaultProjectID string
 }
 
 type DataSourceModel struct {
@@ -62,7 +63,9 @@ func (d *DataSource) Configure(
 		return
 	}
 
-	d.client = req.ProviderData.(*oxide.Client)
+	providerData := req.ProviderData.(*shared.ProviderData)
+	d.client = providerData.Client
+	d.defaultProjectID = providerData.DefaultProjectID
 }
 
 func (d *DataSource) Schema(
@@ -76,8 +79,8 @@ Retrieve information about a specified VPC internet gateway.
 `,
 		Attributes: map[string]schema.Attribute{
 			"project_name": schema.StringAttribute{
-				Required:    true,
-				Description: "Name of the project that contains the VPC internet gateway.",
+				Optional:    true,
+				Description: "Name of the project that contains the VPC internet gateway. Defaults to the provider's `default_project`.",
 			},
 			"name": schema.StringAttribute{
 				Required:    true,
@@ -133,17 +136,23 @@ func (d *DataSource) Read(
 	ctx, cancel := context.WithTimeout(ctx, readTimeout)
 	defer cancel()
 
+	project, diags := shared.ProjectOrDefault(state.ProjectName, "project_name", d.defaultProjectID)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	params := oxide.InternetGatewayViewParams{
 		Gateway: oxide.NameOrId(state.Name.ValueString()),
 		Vpc:     oxide.NameOrId(state.VPCName.ValueString()),
-		Project: oxide.NameOrId(state.ProjectName.ValueString()),
+		Project: project,
 	}
 	vpcInternetGateway, err := d.client.InternetGatewayView(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read VPC internet gateway:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
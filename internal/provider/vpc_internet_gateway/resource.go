@@ -37,7 +37,8 @@ func NewResource() resource.Resource {
 
 // Resource is the resource implementation.
 type Resource struct {
-	client *oxide.Client
+	client           *oxide.Client
+	defaultProjectID string
 }
 
 type ResourceModel struct {
@@ -70,7 +71,9 @@ func (r *Resource) Configure(
 		return
 	}
 
-	r.client = req.ProviderData.(*oxide.Client)
+	providerData := req.ProviderData.(*shared.ProviderData)
+	r.client = providerData.Client
+	r.defaultProjectID = providerData.DefaultProjectID
 }
 
 // ImportState imports the resource state from Terraform.
@@ -79,7 +82,28 @@ func (r *Resource) ImportState(
 	req resource.ImportStateRequest,
 	resp *resource.ImportStateResponse,
 ) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	shared.ImportStateByPath(
+		ctx,
+		req,
+		resp,
+		path.Root("id"),
+		"project/vpc/internet_gateway",
+		r.defaultProjectID,
+		func(ctx context.Context, names []string) (string, error) {
+			internetGateway, err := r.client.InternetGatewayView(
+				ctx,
+				oxide.InternetGatewayViewParams{
+					Project: oxide.NameOrId(names[0]),
+					Vpc:     oxide.NameOrId(names[1]),
+					Gateway: oxide.NameOrId(names[2]),
+				},
+			)
+			if err != nil {
+				return "", err
+			}
+			return internetGateway.Id, nil
+		},
+	)
 }
 
 // Schema defines the schema for the resource.
@@ -177,10 +201,10 @@ func (r *Resource) Create(
 
 	vpcInternetGateway, err := r.client.InternetGatewayCreate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error creating VPC internet gateway",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -234,10 +258,10 @@ func (r *Resource) Read(
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read VPC internet gateway:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -306,10 +330,10 @@ func (r *Resource) Update(
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read VPC internet gateway:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -357,10 +381,10 @@ func (r *Resource) Delete(
 	}
 	if err := r.client.InternetGatewayDelete(ctx, params); err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Unable to delete VPC internet gateway:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
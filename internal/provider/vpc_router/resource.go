@@ -36,7 +36,8 @@ func NewResource() resource.Resource {
 
 // Resource is the resource implementation.
 type Resource struct {
-	client *oxide.Client
+	client           *oxide.Client
+	defaultProjectID string
 }
 
 type ResourceModel struct {
@@ -69,7 +70,9 @@ func (r *Resource) Configure(
 		return
 	}
 
-	r.client = req.ProviderData.(*oxide.Client)
+	providerData := req.ProviderData.(*shared.ProviderData)
+	r.client = providerData.Client
+	r.defaultProjectID = providerData.DefaultProjectID
 }
 
 func (r *Resource) ImportState(
@@ -77,7 +80,25 @@ func (r *Resource) ImportState(
 	req resource.ImportStateRequest,
 	resp *resource.ImportStateResponse,
 ) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	shared.ImportStateByPath(
+		ctx,
+		req,
+		resp,
+		path.Root("id"),
+		"project/vpc/router",
+		r.defaultProjectID,
+		func(ctx context.Context, names []string) (string, error) {
+			router, err := r.client.VpcRouterView(ctx, oxide.VpcRouterViewParams{
+				Project: oxide.NameOrId(names[0]),
+				Vpc:     oxide.NameOrId(names[1]),
+				Router:  oxide.NameOrId(names[2]),
+			})
+			if err != nil {
+				return "", err
+			}
+			return router.Id, nil
+		},
+	)
 }
 
 // Schema defines the schema for the resource.
@@ -169,10 +190,10 @@ func (r *Resource) Create(
 
 	vpcRouter, err := r.client.VpcRouterCreate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error creating vpcRouter",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -227,10 +248,10 @@ func (r *Resource) Read(
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read vpcRouter:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -293,10 +314,10 @@ func (r *Resource) Update(
 	}
 	vpcRouter, err := r.client.VpcRouterUpdate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error updating VPC router",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -345,10 +366,10 @@ func (r *Resource) Delete(
 	}
 	if err := r.client.VpcRouterDelete(ctx, params); err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Unable to delete VPC router:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
@@ -30,7 +30,8 @@ func NewDataSource() datasource.DataSource {
 }
 
 type DataSource struct {
-	client *oxide.Client
+	client           *oxide.Client
+	defaultProjectID string
 }
 
 type DataSourceModel struct {
@@ -77,7 +78,9 @@ func (d *DataSource) Configure(
 		return
 	}
 
-	d.client = req.ProviderData.(*oxide.Client)
+	providerData := req.ProviderData.(*shared.ProviderData)
+	d.client = providerData.Client
+	d.defaultProjectID = providerData.DefaultProjectID
 }
 
 func (d *DataSource) Schema(
@@ -91,8 +94,8 @@ Retrieve information about a specified VPC router route.
 `,
 		Attributes: map[string]schema.Attribute{
 			"project_name": schema.StringAttribute{
-				Required:    true,
-				Description: "Name of the project that contains the VPC router route.",
+				Optional:    true,
+				Description: "Name of the project that contains the VPC router route. Defaults to the provider's `default_project`.",
 			},
 			"name": schema.StringAttribute{
 				Required:    true,
@@ -197,18 +200,24 @@ func (d *DataSource) Read(
 	ctx, cancel := context.WithTimeout(ctx, readTimeout)
 	defer cancel()
 
+	project, diags := shared.ProjectOrDefault(state.ProjectName, "project_name", d.defaultProjectID)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	params := oxide.VpcRouterRouteViewParams{
 		Route:   oxide.NameOrId(state.Name.ValueString()),
-		Project: oxide.NameOrId(state.ProjectName.ValueString()),
+		Project: project,
 		Router:  oxide.NameOrId(state.VPCRouterName.ValueString()),
 		Vpc:     oxide.NameOrId(state.VPCName.ValueString()),
 	}
 	route, err := d.client.VpcRouterRouteView(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read VPC router route:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -37,7 +37,8 @@ func NewResource() resource.Resource {
 
 // Resource is the resource implementation.
 type Resource struct {
-	client *oxide.Client
+	client           *oxide.Client
+	defaultProjectID string
 }
 
 type ResourceModel struct {
@@ -82,7 +83,9 @@ func (r *Resource) Configure(
 		return
 	}
 
-	r.client = req.ProviderData.(*oxide.Client)
+	providerData := req.ProviderData.(*shared.ProviderData)
+	r.client = providerData.Client
+	r.defaultProjectID = providerData.DefaultProjectID
 }
 
 // ImportState imports the resource state from Terraform state.
@@ -91,7 +94,26 @@ func (r *Resource) ImportState(
 	req resource.ImportStateRequest,
 	resp *resource.ImportStateResponse,
 ) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	shared.ImportStateByPath(
+		ctx,
+		req,
+		resp,
+		path.Root("id"),
+		"project/vpc/router/route",
+		r.defaultProjectID,
+		func(ctx context.Context, names []string) (string, error) {
+			route, err := r.client.VpcRouterRouteView(ctx, oxide.VpcRouterRouteViewParams{
+				Project: oxide.NameOrId(names[0]),
+				Vpc:     oxide.NameOrId(names[1]),
+				Router:  oxide.NameOrId(names[2]),
+				Route:   oxide.NameOrId(names[3]),
+			})
+			if err != nil {
+				return "", err
+			}
+			return route.Id, nil
+		},
+	)
 }
 
 // Schema defines the schema for the resource.
@@ -268,10 +290,10 @@ func (r *Resource) Create(
 
 	vpcRouterRoute, err := r.client.VpcRouterRouteCreate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error creating vpcRouterRoute",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 
@@ -326,10 +348,10 @@ func (r *Resource) Read(
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read vpcRouterRoute:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -435,10 +457,10 @@ func (r *Resource) Update(
 
 	vpcRouterRoute, err := r.client.VpcRouterRouteUpdate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error updating VPC router route",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -487,10 +509,10 @@ func (r *Resource) Delete(
 	}
 	if err := r.client.VpcRouterRouteDelete(ctx, params); err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Unable to delete VPC router route:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}
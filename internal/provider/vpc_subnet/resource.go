@@ -37,7 +37,8 @@ func NewResource() resource.Resource {
 
 // Resource is the resource implementation.
 type Resource struct {
-	client *oxide.Client
+	client           *oxide.Client
+	defaultProjectID string
 }
 
 type ResourceModel struct {
@@ -71,7 +72,9 @@ func (r *Resource) Configure(
 		return
 	}
 
-	r.client = req.ProviderData.(*oxide.Client)
+	providerData := req.ProviderData.(*shared.ProviderData)
+	r.client = providerData.Client
+	r.defaultProjectID = providerData.DefaultProjectID
 }
 
 // ImportState imports an existing VPC subnet into Terraform state.
@@ -80,7 +83,25 @@ func (r *Resource) ImportState(
 	req resource.ImportStateRequest,
 	resp *resource.ImportStateResponse,
 ) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	shared.ImportStateByPath(
+		ctx,
+		req,
+		resp,
+		path.Root("id"),
+		"project/vpc/subnet",
+		r.defaultProjectID,
+		func(ctx context.Context, names []string) (string, error) {
+			subnet, err := r.client.VpcSubnetView(ctx, oxide.VpcSubnetViewParams{
+				Project: oxide.NameOrId(names[0]),
+				Vpc:     oxide.NameOrId(names[1]),
+				Subnet:  oxide.NameOrId(names[2]),
+			})
+			if err != nil {
+				return "", err
+			}
+			return subnet.Id, nil
+		},
+	)
 }
 
 // Schema defines the schema for the resource.
@@ -193,10 +214,10 @@ func (r *Resource) Create(
 	}
 	subnet, err := r.client.VpcSubnetCreate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error creating VPC subnet",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -251,10 +272,10 @@ func (r *Resource) Read(
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Unable to read VPC subnet:",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -318,10 +339,10 @@ func (r *Resource) Update(
 	}
 	subnet, err := r.client.VpcSubnetUpdate(ctx, params)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 			"Error updating VPC subnet",
-			"API error: "+err.Error(),
-		)
+			err,
+		))
 		return
 	}
 	tflog.Trace(
@@ -370,10 +391,10 @@ func (r *Resource) Delete(
 	}
 	if err := r.client.VpcSubnetDelete(ctx, params); err != nil {
 		if !shared.Is404(err) {
-			resp.Diagnostics.AddError(
+			resp.Diagnostics.Append(shared.APIErrorDiagnostic(
 				"Error deleting VPC subnet:",
-				"API error: "+err.Error(),
-			)
+				err,
+			))
 			return
 		}
 	}